@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	domainBackup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/backup"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupOutput       string
+	backupIncludeMedia bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the session, chat and media storages into one archive",
+	Long:  `Write a zip archive with a consistent snapshot of the WhatsApp session database, the chat storage, the encryption key file and the downloaded media, plus a manifest with checksums. SQLite databases are copied with the online backup API, so the server may keep running. PostgreSQL databases are not included, back them up with pg_dump. Media kept in an S3 bucket is not included either.`,
+	Run:   backupStorages,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "file to write the archive to (default: whatsapp-backup-<time>.zip in the current directory)")
+	backupCmd.Flags().BoolVar(&backupIncludeMedia, "include-media", true, "include the downloaded media files")
+}
+
+func backupStorages(_ *cobra.Command, _ []string) {
+	output := backupOutput
+	if output == "" {
+		output = fmt.Sprintf("whatsapp-backup-%s.zip", time.Now().UTC().Format("20060102-150405"))
+	}
+
+	// Write next to the destination first so a failed backup never leaves a truncated archive behind
+	file, err := os.CreateTemp(".", ".backup-*.zip")
+	if err != nil {
+		logrus.Fatalf("failed to create archive: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	manifest, err := backupUsecase.Backup(context.Background(), domainBackup.BackupRequest{IncludeMedia: backupIncludeMedia}, file)
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		_ = file.Close()
+		logrus.Fatalf("failed to back up: %v", err)
+	}
+	if err := os.Rename(file.Name(), output); err != nil {
+		logrus.Fatalf("failed to write %s: %v", output, err)
+	}
+
+	fmt.Printf("Backed up %d stores of %d devices to %s\n", len(manifest.Stores), len(manifest.Devices), output)
+	for _, skipped := range manifest.Skipped {
+		fmt.Printf("Skipped %s\n", skipped)
+	}
+	if len(manifest.Devices) > 0 {
+		fmt.Printf("Devices: %s\n", strings.Join(manifest.Devices, ", "))
+	}
+}
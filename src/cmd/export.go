@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportChatJID      string
+	exportFormat       string
+	exportIncludeMedia bool
+	exportStartTime    string
+	exportEndTime      string
+	exportOutput       string
+	exportDevice       string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the stored history of a chat",
+	Long:  `Export the stored history of a chat as a WhatsApp-style text file, JSON or a standalone HTML page. With --include-media the media is downloaded from WhatsApp and bundled with the transcript in a zip archive.`,
+	Run:   exportChat,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportChatJID, "chat", "", "JID of the chat to export | example: --chat=6289685028129@s.whatsapp.net")
+	exportCmd.Flags().StringVar(&exportFormat, "format", domainChat.ExportFormatText, "export format: txt, json or html")
+	exportCmd.Flags().BoolVar(&exportIncludeMedia, "include-media", false, "download the media and bundle it with the transcript in a zip archive")
+	exportCmd.Flags().StringVar(&exportStartTime, "start-time", "", "only export messages sent at or after this RFC3339 time")
+	exportCmd.Flags().StringVar(&exportEndTime, "end-time", "", "only export messages sent at or before this RFC3339 time")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "file to write the export to (default: named after the chat in the current directory)")
+	exportCmd.Flags().StringVar(&exportDevice, "device", "", "device ID to export from (default: the only registered device)")
+	_ = exportCmd.MarkFlagRequired("chat")
+}
+
+func exportChat(_ *cobra.Command, _ []string) {
+	ctx := context.Background()
+
+	instance, _, err := whatsapp.GetDeviceManager().ResolveDevice(exportDevice)
+	if err != nil {
+		logrus.Fatalf("failed to resolve device: %v", err)
+	}
+	ctx = whatsapp.ContextWithDevice(ctx, instance)
+
+	// Media can only be downloaded over a live connection
+	if exportIncludeMedia {
+		client := instance.GetClient()
+		if client == nil || client.Store.ID == nil {
+			logrus.Fatalf("device %s is not logged in, cannot download media", instance.ID())
+		}
+		if !client.IsConnected() {
+			if err := client.Connect(); err != nil {
+				logrus.Fatalf("failed to connect device %s: %v", instance.ID(), err)
+			}
+			defer client.Disconnect()
+		}
+	}
+
+	request := domainChat.ExportChatRequest{
+		ChatJID:      exportChatJID,
+		Format:       exportFormat,
+		IncludeMedia: exportIncludeMedia,
+	}
+	if exportStartTime != "" {
+		request.StartTime = &exportStartTime
+	}
+	if exportEndTime != "" {
+		request.EndTime = &exportEndTime
+	}
+
+	response, err := chatUsecase.ExportChat(ctx, request)
+	if err != nil {
+		logrus.Fatalf("failed to export chat: %v", err)
+	}
+
+	output := exportOutput
+	if output == "" {
+		output = response.Filename
+	}
+	if err := os.WriteFile(output, response.Content, 0644); err != nil {
+		logrus.Fatalf("failed to write export: %v", err)
+	}
+	fmt.Printf("Exported %s to %s\n", exportChatJID, output)
+}
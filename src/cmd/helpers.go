@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/sirupsen/logrus"
@@ -25,3 +27,13 @@ func startAutoReconnectCheckerIfClientAvailable() {
 	}
 	go helpers.SetAutoReconnectChecking(client)
 }
+
+// startBackgroundWorkers launches the long-running workers shared by the REST and MCP servers.
+func startBackgroundWorkers() {
+	whatsapp.StartWebhookDispatcher(context.Background())
+	go scheduleUsecase.Run(context.Background())
+	go campaignUsecase.Run(context.Background())
+	go queueUsecase.Run(context.Background())
+	go retentionUsecase.Run(context.Background())
+	go auditUsecase.Run(context.Background())
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importChatJID   string
+	importFile      string
+	importChatName  string
+	importSelfName  string
+	importSenders   map[string]string
+	importTimezone  string
+	importDateOrder string
+	importDevice    string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a WhatsApp \"Export chat\" archive into the chat storage",
+	Long:  `Import a chat exported from the WhatsApp app, either the zip archive with media or the bare transcript, into the chat storage. Messages the storage already has, for example from the history sync, are skipped.`,
+	Run:   importChat,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importChatJID, "chat", "", "JID of the chat the export belongs to | example: --chat=6289685028129@s.whatsapp.net")
+	importCmd.Flags().StringVarP(&importFile, "file", "f", "", "exported zip archive or transcript text file")
+	importCmd.Flags().StringVar(&importChatName, "chat-name", "", "name of the chat (default: taken from the export file name)")
+	importCmd.Flags().StringVar(&importSelfName, "self-name", "", "your own name as it appears in the transcript")
+	importCmd.Flags().StringToStringVar(&importSenders, "sender", nil, "map a transcript name to a phone number or JID | example: --sender=\"Ana=6289685028129\"")
+	importCmd.Flags().StringVar(&importTimezone, "timezone", "", "time zone of the phone that exported the chat (default: the server time zone)")
+	importCmd.Flags().StringVar(&importDateOrder, "date-order", "", "order of day and month in the transcript: dmy or mdy (default: detected)")
+	importCmd.Flags().StringVar(&importDevice, "device", "", "device ID to import into (default: the only registered device)")
+	_ = importCmd.MarkFlagRequired("chat")
+	_ = importCmd.MarkFlagRequired("file")
+}
+
+func importChat(_ *cobra.Command, _ []string) {
+	ctx := context.Background()
+
+	instance, _, err := whatsapp.GetDeviceManager().ResolveDevice(importDevice)
+	if err != nil {
+		logrus.Fatalf("failed to resolve device: %v", err)
+	}
+	ctx = whatsapp.ContextWithDevice(ctx, instance)
+
+	content, err := os.ReadFile(importFile)
+	if err != nil {
+		logrus.Fatalf("failed to read export: %v", err)
+	}
+
+	response, err := chatUsecase.ImportChat(ctx, domainChat.ImportChatRequest{
+		ChatJID:   importChatJID,
+		ChatName:  importChatName,
+		SelfName:  importSelfName,
+		Senders:   importSenders,
+		Timezone:  importTimezone,
+		DateOrder: importDateOrder,
+		FileName:  filepath.Base(importFile),
+		File:      content,
+	})
+	if err != nil {
+		logrus.Fatalf("failed to import chat: %v", err)
+	}
+
+	fmt.Printf("Imported %d messages into %s (%d duplicates, %d skipped, %d media files)\n",
+		response.Imported, response.ChatJID, response.Duplicates, response.Skipped, response.MediaFiles)
+	if len(response.UnmappedSenders) > 0 {
+		fmt.Printf("Senders without a JID, map them with --sender: %s\n", strings.Join(response.UnmappedSenders, ", "))
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/ratelimit"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -53,7 +57,15 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	app := fiber.New(fiberConfig)
 
-	app.Static(config.AppBasePath+"/statics", "./statics")
+	if staticsSigner != nil {
+		// Secure mode: only signed links are served, there is no static mount to guess paths on
+		app.Get(config.AppBasePath+"/statics/*", middleware.Statics("statics", staticsSigner))
+	} else {
+		if encryption.Active() != nil {
+			app.Get(config.AppBasePath+"/statics/*", middleware.Statics("statics", nil))
+		}
+		app.Static(config.AppBasePath+"/statics", "./statics")
+	}
 	app.Use(config.AppBasePath+"/components", filesystem.New(filesystem.Config{
 		Root:       http.FS(EmbedViews),
 		PathPrefix: "views/components",
@@ -65,6 +77,10 @@ func restServer(_ *cobra.Command, _ []string) {
 		Browse:     true,
 	}))
 
+	app.Use(middleware.Metrics())
+	app.Use(middleware.Tracing())
+	// Audit wraps Recovery to record the responses of handlers that panic
+	app.Use(middleware.Audit(auditUsecase))
 	app.Use(middleware.Recovery())
 	app.Use(middleware.RequestTimeout(middleware.DefaultRequestTimeout))
 	app.Use(middleware.BasicAuth())
@@ -76,6 +92,9 @@ func restServer(_ *cobra.Command, _ []string) {
 		AllowHeaders: "Origin, Content-Type, Accept",
 	}))
 
+	// API keys are checked first so their permissions and devices apply before DeviceMiddleware
+	app.Use(middleware.APIKeyAuth(apiKeyUsecase))
+
 	if len(config.AppBasicAuthCredential) > 0 {
 		account := make(map[string]string)
 		for _, basicAuth := range config.AppBasicAuthCredential {
@@ -88,6 +107,8 @@ func restServer(_ *cobra.Command, _ []string) {
 
 		app.Use(basicauth.New(basicauth.Config{
 			Users: account,
+			// A valid API key replaces basic auth
+			Next: middleware.AuthenticatedByAPIKey,
 		}))
 	}
 
@@ -99,20 +120,47 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	// Device manager aware routing
 	dm := whatsapp.GetDeviceManager()
+	if err := metrics.RegisterDeviceStates(dm.States); err != nil {
+		logrus.Warnf("Failed to register device state metrics: %v", err)
+	}
+
+	rateLimits := middleware.RateLimits{
+		PerCredential: config.AppRateLimitCredential,
+		PerDevice:     config.AppRateLimitDevice,
+		PerRecipient:  config.AppRateLimitRecipient,
+	}
 
 	registerDeviceScopedRoutes := func(r fiber.Router) {
 		rest.InitRestApp(r, appUsecase)
 		rest.InitRestChat(r, chatUsecase)
+		r.Use("/send", middleware.Idempotency(idempotencyRepo, time.Duration(config.AppIdempotencyHours)*time.Hour))
+		if rateLimits.Enabled() {
+			// Registered after Idempotency so replayed responses do not use the budget
+			rateLimit := middleware.RateLimit(ratelimit.New(), rateLimits)
+			r.Use("/send", rateLimit)
+			r.Use("/group", rateLimit)
+		}
+		rest.InitRestQueue(r, queueUsecase)
 		rest.InitRestSend(r, sendUsecase)
+		rest.InitRestSchedule(r, scheduleUsecase)
+		rest.InitRestCampaign(r, campaignUsecase)
 		rest.InitRestUser(r, userUsecase)
 		rest.InitRestMessage(r, messageUsecase)
 		rest.InitRestGroup(r, groupUsecase)
 		rest.InitRestNewsletter(r, newsletterUsecase)
+		rest.InitRestWebhookReplay(r, webhookUsecase)
 		websocket.RegisterRoutes(r, appUsecase)
 	}
 
 	// Device management routes (no device_id required)
 	rest.InitRestDevice(apiGroup, deviceUsecase)
+	rest.InitRestWebhook(apiGroup, webhookUsecase)
+	rest.InitRestTemplate(apiGroup, templateUsecase)
+	rest.InitRestRetention(apiGroup, retentionUsecase)
+	rest.InitRestBackup(apiGroup, backupUsecase)
+	rest.InitRestAPIKey(apiGroup, apiKeyUsecase)
+	rest.InitRestAudit(apiGroup, auditUsecase)
+	rest.InitRestMetrics(apiGroup)
 
 	// Device-scoped operations (header-based)
 	headerDeviceGroup := apiGroup.Group("", middleware.DeviceMiddleware(dm))
@@ -131,6 +179,8 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	go websocket.RunHub()
 
+	startBackgroundWorkers()
+
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
 
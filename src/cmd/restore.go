@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainBackup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/backup"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreFile  string
+	restoreForce bool
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the storages from a backup archive",
+	Long:  `Restore an archive written by the backup command into the storages configured on this host, so a device keeps its session without pairing again. Every file is checked against the manifest and every database against the SQLite integrity check before anything is replaced. Stop the server first, restore replaces the database files underneath it.`,
+	// Restore replaces the database files, so it must not open them like the other commands do
+	PersistentPreRun: func(_ *cobra.Command, _ []string) {},
+	Run:              restoreStorages,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().StringVarP(&restoreFile, "file", "f", "", "backup archive to restore")
+	restoreCmd.Flags().BoolVar(&restoreForce, "force", false, "overwrite storages that already exist on this host")
+	_ = restoreCmd.MarkFlagRequired("file")
+}
+
+func restoreStorages(_ *cobra.Command, _ []string) {
+	manifest, err := usecase.NewBackupService().Restore(context.Background(), domainBackup.RestoreRequest{
+		Archive: restoreFile,
+		Force:   restoreForce,
+	})
+	if err != nil {
+		logrus.Fatalf("failed to restore: %v", err)
+	}
+
+	fmt.Printf("Restored %d stores from a backup taken at %s\n", len(manifest.Stores), manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	if len(manifest.Devices) > 0 {
+		fmt.Printf("Devices: %s\n", strings.Join(manifest.Devices, ", "))
+	}
+}
@@ -14,6 +14,15 @@ var (
 	AppBasicAuthCredential []string
 	AppBasePath            = ""
 	AppTrustedProxies      []string // Trusted proxy IP ranges (e.g., "0.0.0.0/0" for all, or specific CIDRs)
+	AppIdempotencyHours    = 24     // Hours a send response is replayed for retries with the same Idempotency-Key
+	AppSecureStatics       = false  // Serve /statics only through signed, expiring links
+	AppStaticsSecret       = ""     // HMAC secret for /statics links, random per start when empty
+	AppStaticsURLExpiry    = 3600   // Seconds a signed /statics link stays valid
+	AppAuditRetentionDays  = 90     // Days audit log entries are kept, 0 keeps them forever
+	AppRateLimitCredential = 0      // Send and group requests per minute per API key, basic auth user or IP (0 = unlimited)
+	AppRateLimitDevice     = 0      // Send and group requests per minute per device (0 = unlimited)
+	AppRateLimitRecipient  = 0      // Send and group requests per minute per recipient and device (0 = unlimited)
+	AppTracingEndpoint     = ""     // OTLP/HTTP collector URL for traces, e.g. http://localhost:4318 (empty = disabled)
 
 	McpPort = "8080"
 	McpHost = "localhost"
@@ -31,8 +40,12 @@ var (
 	WhatsappAutoDownloadMedia         = true  // Auto-download media from incoming messages
 	WhatsappWebhook                   []string
 	WhatsappWebhookSecret             = "secret"
-	WhatsappWebhookInsecureSkipVerify = false  // Skip TLS certificate verification for webhooks (insecure)
-	WhatsappWebhookEvents             []string // Whitelist of events to forward to webhook (empty = all events)
+	WhatsappWebhookInsecureSkipVerify = false         // Skip TLS certificate verification for webhooks (insecure)
+	WhatsappWebhookEvents             []string        // Whitelist of events to forward to webhook (empty = all events)
+	WhatsappWebhookMaxAttempts                 = 10   // Delivery attempts before an outbox entry is dead-lettered
+	WhatsappCampaignRatePerMinute              = 20   // Default campaign messages per minute per device
+	WhatsappCampaignJitterSeconds              = 10   // Default random extra delay added between campaign messages
+	WhatsappCampaignDailyCap                   = 1000 // Default campaign messages per device in any 24 hours (0 = unlimited)
 	WhatsappLogLevel                           = "ERROR"
 	WhatsappSettingMaxImageSize       int64    = 20000000  // 20MB
 	WhatsappSettingMaxFileSize        int64    = 50000000  // 50MB
@@ -45,4 +58,16 @@ var (
 	ChatStorageURI               = "file:storages/chatstorage.db"
 	ChatStorageEnableForeignKeys = true
 	ChatStorageEnableWAL         = true
+	ChatStorageEncryptionKey     = "" // base64 encoded 32 byte key, sealed message content and media at rest
+	ChatStorageEncryptionKeyFile = "" // JSON key file with several keys, used for key rotation
+
+	MediaStorage = "local" // Where downloaded media and QR codes are kept: local or s3
+	S3Endpoint   = ""      // S3 compatible endpoint, empty for AWS S3
+	S3Region     = "us-east-1"
+	S3Bucket     = ""
+	S3AccessKey  = ""
+	S3SecretKey  = ""
+	S3Prefix     = ""   // Prefix for every object key, e.g. "whatsapp/"
+	S3PathStyle  = true // Address the bucket in the path instead of a subdomain, needed by MinIO
+	S3URLExpiry  = 3600 // Seconds the signed media URLs stay valid
 )
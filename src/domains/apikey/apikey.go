@@ -0,0 +1,54 @@
+package apikey
+
+import (
+	"slices"
+	"time"
+)
+
+// Permissions an API key can be granted. Admin implies every other permission.
+const (
+	PermissionSend          = "send"
+	PermissionReadChats     = "read_chats"
+	PermissionManageGroups  = "manage_groups"
+	PermissionManageDevices = "manage_devices"
+	PermissionAdmin         = "admin"
+)
+
+// Permissions lists every permission in the order they are documented
+var Permissions = []string{PermissionSend, PermissionReadChats, PermissionManageGroups, PermissionManageDevices, PermissionAdmin}
+
+// APIKey grants access to the API as an alternative to basic auth. Only a hash of the key is stored,
+// the key itself is returned once when it is created.
+type APIKey struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	KeyHash     string     `json:"-"`
+	Devices     []string   `json:"devices"`
+	Permissions []string   `json:"permissions"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+}
+
+// HasPermission reports whether the key was granted the permission or admin
+func (k APIKey) HasPermission(permission string) bool {
+	return slices.Contains(k.Permissions, PermissionAdmin) || slices.Contains(k.Permissions, permission)
+}
+
+// AllowsDevice reports whether the key may act on the device, a key without devices may act on all of them
+func (k APIKey) AllowsDevice(deviceID string) bool {
+	return len(k.Devices) == 0 || slices.Contains(k.Devices, deviceID)
+}
+
+type CreateAPIKeyRequest struct {
+	Name        string   `json:"name" form:"name"`
+	Devices     []string `json:"devices" form:"devices"`
+	Permissions []string `json:"permissions" form:"permissions"`
+}
+
+// CreateAPIKeyResponse carries the plaintext key, it cannot be retrieved again
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,27 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+// IAPIKeyRepository persists API keys.
+type IAPIKeyRepository interface {
+	CreateKey(key *APIKey) error
+	GetKey(keyID string) (*APIKey, error)
+	// GetKeyByHash returns the key with this hash, revoked or not, or nil when there is none
+	GetKeyByHash(keyHash string) (*APIKey, error)
+	ListKeys() ([]*APIKey, error)
+	RevokeKey(keyID string, revokedAt time.Time) error
+	TouchKey(keyID string, usedAt time.Time) error
+}
+
+// IAPIKeyUsecase manages API keys and authenticates the requests carrying them.
+type IAPIKeyUsecase interface {
+	CreateKey(ctx context.Context, request CreateAPIKeyRequest) (CreateAPIKeyResponse, error)
+	ListKeys(ctx context.Context) ([]APIKey, error)
+	RevokeKey(ctx context.Context, keyID string) error
+
+	// Authenticate returns the active key matching the plaintext key and records that it was used.
+	Authenticate(ctx context.Context, key string) (APIKey, error)
+}
@@ -22,6 +22,7 @@ type DevicesResponse struct {
 
 type LoginResponse struct {
 	ImagePath string        `json:"image_path"`
+	ImageURL  string        `json:"image_url"` // signed URL with object storage, otherwise ImagePath
 	Duration  time.Duration `json:"duration"`
 	Code      string        `json:"code"`
 }
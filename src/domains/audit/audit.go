@@ -0,0 +1,67 @@
+package audit
+
+import "time"
+
+// Who made an audited call
+const (
+	ActorBasicAuth = "basic_auth"
+	ActorAPIKey    = "api_key"
+	ActorAnonymous = "anonymous"
+)
+
+// Entry records one state-changing API call and how it ended
+type Entry struct {
+	ID        string `json:"id"`
+	ActorType string `json:"actor_type"`
+	// Actor is the basic auth user or the name of the API key
+	Actor    string `json:"actor"`
+	APIKeyID string `json:"api_key_id,omitempty"`
+	DeviceID string `json:"device_id"`
+	Method   string `json:"method"`
+	// Route is the registered route, e.g. /group/participants/remove, Path the requested one
+	Route   string   `json:"route"`
+	Path    string   `json:"path"`
+	Targets []string `json:"targets"`
+	// Summary lists the request fields, message content is reduced to its length
+	Summary    map[string]string `json:"summary"`
+	StatusCode int               `json:"status_code"`
+	Success    bool              `json:"success"`
+	Error      string            `json:"error,omitempty"`
+	DurationMs int64             `json:"duration_ms"`
+	RemoteIP   string            `json:"remote_ip"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Filter narrows audit log listings, empty fields match everything
+type Filter struct {
+	DeviceID  string
+	Actor     string
+	Route     string
+	Target    string
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int
+	Offset    int
+}
+
+type ListRequest struct {
+	DeviceID  string `json:"device_id" query:"device_id"`
+	Actor     string `json:"actor" query:"actor"`
+	Route     string `json:"route" query:"route"`
+	Target    string `json:"target" query:"target"`
+	StartTime string `json:"start_time" query:"start_time"`
+	EndTime   string `json:"end_time" query:"end_time"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
+
+type ListResponse struct {
+	Data       []Entry            `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// IAuditRepository persists the audit log.
+type IAuditRepository interface {
+	StoreEntry(entry *Entry) error
+	// ListEntries returns entries matching the filter, newest first, together with the total count
+	ListEntries(filter *Filter) ([]*Entry, int64, error)
+	PurgeEntriesBefore(before time.Time) (int64, error)
+}
+
+// IAuditUsecase records state-changing API calls and serves the audit log.
+type IAuditUsecase interface {
+	Record(ctx context.Context, entry Entry) error
+	List(ctx context.Context, request ListRequest) (ListResponse, error)
+
+	// Run purges entries older than the audit retention periodically and blocks until ctx is cancelled.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,64 @@
+package backup
+
+import "time"
+
+// ManifestVersion is the archive layout written by this version, restore refuses newer archives
+const ManifestVersion = 1
+
+// ManifestName is the archive entry that describes every other entry
+const ManifestName = "manifest.json"
+
+// Store names, restore maps each one to the location configured on the target host
+const (
+	StoreWhatsApp    = "whatsapp"
+	StoreKeys        = "keys"
+	StoreChatStorage = "chatstorage"
+	StoreMedia       = "media"
+	// StoreEncryptionKeys is the --db-encryption-key-file keyring, without it the encrypted data is unreadable
+	StoreEncryptionKeys = "encryption_keys"
+	// StorePolls is the legacy poll_store.json written by older archives, polls live in the chat storage now
+	StorePolls = "poll_store"
+)
+
+// Store kinds
+const (
+	KindSQLite    = "sqlite"
+	KindFile      = "file"
+	KindDirectory = "directory"
+)
+
+// Manifest describes a backup archive. The checksums let restore reject a truncated or altered archive
+// before it touches anything.
+type Manifest struct {
+	Version    int       `json:"version"`
+	AppVersion string    `json:"app_version"`
+	CreatedAt  time.Time `json:"created_at"`
+	Devices    []string  `json:"devices"`
+	Stores     []Store   `json:"stores"`
+	// Skipped lists the stores that are not in the archive and why, e.g. PostgreSQL databases
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+type Store struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Source string `json:"source"`
+	Files  []File `json:"files"`
+}
+
+// File is one archive entry, Path is relative to the archive root
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type BackupRequest struct {
+	IncludeMedia bool `json:"include_media" query:"include_media"`
+}
+
+type RestoreRequest struct {
+	Archive string `json:"archive"`
+	// Force overwrites storages that already exist on this host
+	Force bool `json:"force"`
+}
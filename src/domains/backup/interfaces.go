@@ -0,0 +1,14 @@
+package backup
+
+import (
+	"context"
+	"io"
+)
+
+// IBackupUsecase snapshots and restores the session, chat and media storages.
+type IBackupUsecase interface {
+	// Backup writes a zip archive of every local storage to w and returns its manifest.
+	Backup(ctx context.Context, request BackupRequest, w io.Writer) (Manifest, error)
+	// Restore validates an archive and puts its storages in place. The storages must not be in use.
+	Restore(ctx context.Context, request RestoreRequest) (Manifest, error)
+}
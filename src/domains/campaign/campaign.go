@@ -0,0 +1,134 @@
+package campaign
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusPaused    Status = "paused"
+	StatusCompleted Status = "completed"
+	StatusCancelled Status = "cancelled"
+)
+
+type RecipientStatus string
+
+const (
+	RecipientQueued        RecipientStatus = "queued"
+	RecipientSending       RecipientStatus = "sending"
+	RecipientSent          RecipientStatus = "sent"
+	RecipientFailed        RecipientStatus = "failed"
+	RecipientNotOnWhatsApp RecipientStatus = "not_on_whatsapp"
+)
+
+// Campaign sends one message template to many recipients of a single device.
+// Message is the JSON body of the matching domainSend request without phone; its string
+// fields may contain {{name}} placeholders filled from each recipient's variables.
+type Campaign struct {
+	ID            string          `json:"id"`
+	DeviceID      string          `json:"device_id"`
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	Message       json.RawMessage `json:"message"`
+	RatePerMinute int             `json:"rate_per_minute"`
+	JitterSeconds int             `json:"jitter_seconds"`
+	DailyCap      int             `json:"daily_cap"`
+	Status        Status          `json:"status"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CompletedAt   *time.Time      `json:"completed_at,omitempty"`
+	Progress      *Progress       `json:"progress,omitempty"`
+}
+
+// Progress counts campaign recipients by status. Queued includes the recipient currently being sent.
+type Progress struct {
+	Total         int64   `json:"total"`
+	Queued        int64   `json:"queued"`
+	Sent          int64   `json:"sent"`
+	Failed        int64   `json:"failed"`
+	NotOnWhatsApp int64   `json:"not_on_whatsapp"`
+	Percent       float64 `json:"percent"`
+}
+
+type Recipient struct {
+	ID         string            `json:"id"`
+	CampaignID string            `json:"campaign_id"`
+	Phone      string            `json:"phone"`
+	Variables  map[string]string `json:"variables,omitempty"`
+	Status     RecipientStatus   `json:"status"`
+	MessageID  string            `json:"message_id,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	SentAt     *time.Time        `json:"sent_at,omitempty"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// Filter narrows campaign listings.
+type Filter struct {
+	DeviceID string
+	Status   Status
+	Limit    int
+	Offset   int
+}
+
+// RecipientFilter narrows recipient listings of one campaign.
+type RecipientFilter struct {
+	CampaignID string
+	Status     RecipientStatus
+	Limit      int
+	Offset     int
+}
+
+type RecipientRequest struct {
+	Phone     string            `json:"phone"`
+	Variables map[string]string `json:"variables"`
+}
+
+type CreateRequest struct {
+	Name          string                `json:"name" form:"name"`
+	Type          string                `json:"type" form:"type"`
+	Message       json.RawMessage       `json:"message" form:"-"`
+	Recipients    []RecipientRequest    `json:"recipients" form:"-"`
+	RecipientsCSV *multipart.FileHeader `json:"-" form:"recipients_csv"`
+	RatePerMinute int                   `json:"rate_per_minute" form:"rate_per_minute"`
+	JitterSeconds *int                  `json:"jitter_seconds" form:"jitter_seconds"`
+	DailyCap      *int                  `json:"daily_cap" form:"daily_cap"`
+}
+
+type ListRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListResponse struct {
+	Data       []Campaign         `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type ListRecipientsRequest struct {
+	CampaignID string `json:"campaign_id" uri:"campaign_id"`
+	Status     string `json:"status" query:"status"`
+	Limit      int    `json:"limit" query:"limit"`
+	Offset     int    `json:"offset" query:"offset"`
+}
+
+type ListRecipientsResponse struct {
+	Data       []Recipient        `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type ProgressResponse struct {
+	CampaignID string `json:"campaign_id"`
+	Status     Status `json:"status"`
+	Progress
+}
+
+type PaginationResponse struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
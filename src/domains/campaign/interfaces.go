@@ -0,0 +1,45 @@
+package campaign
+
+import (
+	"context"
+	"time"
+)
+
+// ICampaignRepository persists campaigns and their recipients.
+type ICampaignRepository interface {
+	CreateCampaign(campaign *Campaign, recipients []*Recipient) error
+	GetCampaign(deviceID, campaignID string) (*Campaign, error)
+	ListCampaigns(filter *Filter) ([]*Campaign, int64, error)
+	GetCampaignProgress(campaignID string) (Progress, error)
+	ListRecipients(filter *RecipientFilter) ([]*Recipient, int64, error)
+	// UpdateCampaignStatus changes the status only while the campaign is still in one of the given states.
+	UpdateCampaignStatus(deviceID, campaignID string, status Status, from ...Status) (bool, error)
+	// CompleteCampaign marks a running campaign completed once it has no queued recipients left.
+	CompleteCampaign(campaignID string) (bool, error)
+
+	// NextRunningCampaign returns the oldest running campaign of a device, or nil when there is none.
+	NextRunningCampaign(deviceID string) (*Campaign, error)
+	NextQueuedRecipient(campaignID string) (*Recipient, error)
+	// ClaimRecipient moves a queued recipient to sending. It returns false when it was already picked up.
+	ClaimRecipient(recipientID string) (bool, error)
+	UpdateRecipientResult(recipientID string, status RecipientStatus, messageID, errMsg string) error
+	// CountSentSince counts campaign messages a device sent after since, across all of its campaigns.
+	CountSentSince(deviceID string, since time.Time) (int64, error)
+	// FailInterruptedRecipients marks recipients left in sending by a crash as failed rather than risking a duplicate send.
+	FailInterruptedRecipients() (int64, error)
+}
+
+// ICampaignUsecase manages broadcast campaigns of the device in ctx.
+type ICampaignUsecase interface {
+	Create(ctx context.Context, request CreateRequest) (Campaign, error)
+	List(ctx context.Context, request ListRequest) (ListResponse, error)
+	Get(ctx context.Context, campaignID string) (Campaign, error)
+	Progress(ctx context.Context, campaignID string) (ProgressResponse, error)
+	ListRecipients(ctx context.Context, request ListRecipientsRequest) (ListRecipientsResponse, error)
+	Pause(ctx context.Context, campaignID string) (Campaign, error)
+	Resume(ctx context.Context, campaignID string) (Campaign, error)
+	Cancel(ctx context.Context, campaignID string) (Campaign, error)
+
+	// Run starts one sending worker per registered device and blocks until ctx is cancelled.
+	Run(ctx context.Context)
+}
@@ -33,6 +33,91 @@ type GetChatMessagesResponse struct {
 	ChatInfo   ChatInfo           `json:"chat_info"`
 }
 
+type SearchMessagesRequest struct {
+	Query     string  `json:"query" query:"query"`
+	ChatJID   string  `json:"chat_jid" query:"chat_jid"`
+	Sender    string  `json:"sender" query:"sender"`
+	MediaType string  `json:"media_type" query:"media_type"`
+	StartTime *string `json:"start_time" query:"start_time"`
+	EndTime   *string `json:"end_time" query:"end_time"`
+	Limit     int     `json:"limit" query:"limit"`
+	Offset    int     `json:"offset" query:"offset"`
+}
+
+type SearchMessagesResponse struct {
+	Data       []SearchMessageResult `json:"data"`
+	Pagination PaginationResponse    `json:"pagination"`
+}
+
+// SearchMessageResult is a matching message with its matched terms wrapped in <mark> tags in Snippet
+type SearchMessageResult struct {
+	MessageInfo
+	Snippet string `json:"snippet"`
+}
+
+// Chat export formats
+const (
+	ExportFormatText = "txt"
+	ExportFormatJSON = "json"
+	ExportFormatHTML = "html"
+)
+
+type ExportChatRequest struct {
+	ChatJID      string  `json:"chat_jid" uri:"chat_jid"`
+	Format       string  `json:"format" query:"format"`
+	IncludeMedia bool    `json:"include_media" query:"include_media"`
+	StartTime    *string `json:"start_time" query:"start_time"`
+	EndTime      *string `json:"end_time" query:"end_time"`
+}
+
+// ExportChatResponse is a finished export file, a zip archive when media is included
+type ExportChatResponse struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// ExportMessage is a message in a JSON export, MediaFile is its path inside the archive
+type ExportMessage struct {
+	MessageInfo
+	MediaFile string `json:"media_file,omitempty"`
+}
+
+// ExportChatDocument is the content of a JSON export
+type ExportChatDocument struct {
+	Chat       ChatInfo        `json:"chat"`
+	ExportedAt string          `json:"exported_at"`
+	Messages   []ExportMessage `json:"messages"`
+}
+
+// Date orders of the timestamps in an imported chat export
+const (
+	ImportDateOrderDMY = "dmy"
+	ImportDateOrderMDY = "mdy"
+)
+
+// ImportChatRequest imports a WhatsApp "Export chat" archive, a zip with the transcript and media or a bare
+// transcript. Senders maps names in the transcript to phone numbers or JIDs.
+type ImportChatRequest struct {
+	ChatJID   string            `json:"chat_jid" uri:"chat_jid"`
+	ChatName  string            `json:"chat_name" form:"chat_name"`
+	SelfName  string            `json:"self_name" form:"self_name"`
+	Senders   map[string]string `json:"senders" form:"-"`
+	Timezone  string            `json:"timezone" form:"timezone"`
+	DateOrder string            `json:"date_order" form:"date_order"`
+	FileName  string            `json:"-" form:"-"`
+	File      []byte            `json:"-" form:"-"`
+}
+
+type ImportChatResponse struct {
+	ChatJID         string   `json:"chat_jid"`
+	Imported        int      `json:"imported"`
+	Duplicates      int      `json:"duplicates"`
+	Skipped         int      `json:"skipped"`
+	MediaFiles      int      `json:"media_files"`
+	UnmappedSenders []string `json:"unmapped_senders"`
+}
+
 // Pin Chat operations
 type PinChatRequest struct {
 	ChatJID string `json:"chat_jid" uri:"chat_jid"`
@@ -57,18 +142,48 @@ type ChatInfo struct {
 }
 
 type MessageInfo struct {
-	ID         string `json:"id"`
-	ChatJID    string `json:"chat_jid"`
-	SenderJID  string `json:"sender_jid"`
-	Content    string `json:"content"`
-	Timestamp  string `json:"timestamp"`
-	IsFromMe   bool   `json:"is_from_me"`
-	MediaType  string `json:"media_type"`
-	Filename   string `json:"filename"`
-	URL        string `json:"url"`
-	FileLength uint64 `json:"file_length"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ID         string         `json:"id"`
+	ChatJID    string         `json:"chat_jid"`
+	SenderJID  string         `json:"sender_jid"`
+	Content    string         `json:"content"`
+	Timestamp  string         `json:"timestamp"`
+	IsFromMe   bool           `json:"is_from_me"`
+	MediaType  string         `json:"media_type"`
+	Filename   string         `json:"filename"`
+	URL        string         `json:"url"`
+	FileLength uint64         `json:"file_length"`
+	Status     string         `json:"status,omitempty"`
+	Receipts   []ReceiptInfo  `json:"receipts,omitempty"`
+	EditedAt   string         `json:"edited_at,omitempty"`
+	Edits      []EditInfo     `json:"edits,omitempty"`
+	Reactions  []ReactionInfo `json:"reactions,omitempty"`
+	RevokedAt  string         `json:"revoked_at,omitempty"`
+	RevokedBy  string         `json:"revoked_by,omitempty"`
+	CreatedAt  string         `json:"created_at"`
+	UpdatedAt  string         `json:"updated_at"`
+}
+
+// ReceiptInfo is the delivery state of an outgoing message for one recipient
+type ReceiptInfo struct {
+	RecipientJID string `json:"recipient_jid"`
+	Status       string `json:"status"`
+	DeliveredAt  string `json:"delivered_at,omitempty"`
+	ReadAt       string `json:"read_at,omitempty"`
+	PlayedAt     string `json:"played_at,omitempty"`
+}
+
+// EditInfo is one revision of an edited message, revision 0 being the original content
+type EditInfo struct {
+	Revision int    `json:"revision"`
+	Content  string `json:"content"`
+	EditedAt string `json:"edited_at"`
+}
+
+// ReactionInfo is the current reaction of one participant to a message
+type ReactionInfo struct {
+	ReactorJID string `json:"reactor_jid"`
+	Emoji      string `json:"emoji"`
+	ReactedAt  string `json:"reacted_at"`
 }
 
 type PaginationResponse struct {
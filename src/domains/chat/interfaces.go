@@ -8,6 +8,9 @@ import (
 type IChatUsecase interface {
 	ListChats(ctx context.Context, request ListChatsRequest) (response ListChatsResponse, err error)
 	GetChatMessages(ctx context.Context, request GetChatMessagesRequest) (response GetChatMessagesResponse, err error)
+	SearchMessages(ctx context.Context, request SearchMessagesRequest) (response SearchMessagesResponse, err error)
+	ExportChat(ctx context.Context, request ExportChatRequest) (response ExportChatResponse, err error)
+	ImportChat(ctx context.Context, request ImportChatRequest) (response ImportChatResponse, err error)
 	PinChat(ctx context.Context, request PinChatRequest) (response PinChatResponse, err error)
 	SetDisappearingTimer(ctx context.Context, request SetDisappearingTimerRequest) (response SetDisappearingTimerResponse, err error)
 	ArchiveChat(ctx context.Context, request ArchiveChatRequest) (response ArchiveChatResponse, err error)
@@ -16,22 +16,25 @@ type Chat struct {
 
 // Message represents a WhatsApp message
 type Message struct {
-	ID            string    `db:"id"`
-	ChatJID       string    `db:"chat_jid"`
-	DeviceID      string    `db:"device_id"`
-	Sender        string    `db:"sender"`
-	Content       string    `db:"content"`
-	Timestamp     time.Time `db:"timestamp"`
-	IsFromMe      bool      `db:"is_from_me"`
-	MediaType     string    `db:"media_type"`
-	Filename      string    `db:"filename"`
-	URL           string    `db:"url"`
-	MediaKey      []byte    `db:"media_key"`
-	FileSHA256    []byte    `db:"file_sha256"`
-	FileEncSHA256 []byte    `db:"file_enc_sha256"`
-	FileLength    uint64    `db:"file_length"`
-	CreatedAt     time.Time `db:"created_at"`
-	UpdatedAt     time.Time `db:"updated_at"`
+	ID            string     `db:"id"`
+	ChatJID       string     `db:"chat_jid"`
+	DeviceID      string     `db:"device_id"`
+	Sender        string     `db:"sender"`
+	Content       string     `db:"content"`
+	Timestamp     time.Time  `db:"timestamp"`
+	IsFromMe      bool       `db:"is_from_me"`
+	MediaType     string     `db:"media_type"`
+	Filename      string     `db:"filename"`
+	URL           string     `db:"url"`
+	MediaKey      []byte     `db:"media_key"`
+	FileSHA256    []byte     `db:"file_sha256"`
+	FileEncSHA256 []byte     `db:"file_enc_sha256"`
+	FileLength    uint64     `db:"file_length"`
+	EditedAt      *time.Time `db:"edited_at"`
+	RevokedAt     *time.Time `db:"revoked_at"`
+	RevokedBy     string     `db:"revoked_by"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
 }
 
 // MediaInfo represents downloadable media information
@@ -58,6 +61,7 @@ type DeviceRecord struct {
 
 // MessageFilter represents query filters for messages
 type MessageFilter struct {
+	DeviceID  string
 	ChatJID   string
 	Limit     int
 	Offset    int
@@ -65,6 +69,16 @@ type MessageFilter struct {
 	EndTime   *time.Time
 	MediaOnly bool
 	IsFromMe  *bool
+	// OldestFirst returns messages in ascending time order, so a limit keeps the earliest ones
+	OldestFirst bool
+	// After skips every message up to and including this one in OldestFirst order
+	After *MessageCursor
+}
+
+// MessageCursor is the position of a message when paging in time order, the ID breaks ties within a timestamp
+type MessageCursor struct {
+	Timestamp time.Time
+	ID        string
 }
 
 // ChatFilter represents query filters for chats
@@ -0,0 +1,25 @@
+package chatstorage
+
+import "time"
+
+// MessageReaction is the current reaction of one reactor to a message. A reactor has at most one reaction
+// per message; removing it deletes the record.
+type MessageReaction struct {
+	DeviceID   string    `db:"device_id"`
+	MessageID  string    `db:"message_id"`
+	ChatJID    string    `db:"chat_jid"`
+	ReactorJID string    `db:"reactor_jid"`
+	Emoji      string    `db:"emoji"`
+	ReactedAt  time.Time `db:"reacted_at"`
+}
+
+// MessageEdit is one revision of a message text. Revision 0 preserves the original content,
+// every edit adds the next revision and the message itself keeps the latest text.
+type MessageEdit struct {
+	DeviceID  string    `db:"device_id"`
+	MessageID string    `db:"message_id"`
+	ChatJID   string    `db:"chat_jid"`
+	Revision  int       `db:"revision"`
+	Content   string    `db:"content"`
+	EditedAt  time.Time `db:"edited_at"`
+}
@@ -26,10 +26,34 @@ type IChatStorageRepository interface {
 	GetMessageByID(id string) (*Message, error) // New method for efficient ID-only search
 	GetMessages(filter *MessageFilter) ([]*Message, error)
 	SearchMessages(chatJID, searchText string, limit int) ([]*Message, error) // Database-level search
+	// SearchAllMessages searches messages across chats, best matches first, and returns the total number of matches
+	SearchAllMessages(filter *MessageSearchFilter) ([]*MessageSearchResult, int64, error)
 	DeleteMessage(id, chatJID string) error
 	DeleteMessageByDevice(deviceID, id, chatJID string) error
 	StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error
 
+	// Receipt operations
+	StoreReceipts(receipts []*MessageReceipt) error
+	// GetMessageReceipts returns the receipts of the given messages keyed by message ID. An empty deviceID matches every device.
+	GetMessageReceipts(deviceID string, messageIDs []string) (map[string][]*MessageReceipt, error)
+
+	// History operations
+	// StoreReaction records the current reaction of a reactor, an empty Emoji removes it
+	StoreReaction(reaction *MessageReaction) error
+	// StoreMessageEdit adds a revision to a stored message and updates its content, ignoring unknown messages
+	StoreMessageEdit(edit *MessageEdit) error
+	MarkMessageRevoked(deviceID, chatJID, messageID, revokedBy string, revokedAt time.Time) error
+	GetMessageReactions(deviceID string, messageIDs []string) (map[string][]*MessageReaction, error)
+	GetMessageEdits(deviceID string, messageIDs []string) (map[string][]*MessageEdit, error)
+
+	// Poll operations
+	StorePoll(poll *Poll) error
+	// GetPoll returns a poll by message ID, or nil when it is unknown to the device
+	GetPoll(deviceID, messageID string) (*Poll, error)
+	// StorePollVote records a vote unless a later vote of the same voter is already stored
+	StorePollVote(vote *PollVote) error
+	GetPollVotes(deviceID, messageID string) ([]*PollVote, error)
+
 	// Statistics
 	GetChatMessageCount(chatJID string) (int64, error)
 	GetChatMessageCountByDevice(deviceID, chatJID string) (int64, error)
@@ -52,4 +76,8 @@ type IChatStorageRepository interface {
 
 	// Schema operations
 	InitializeSchema() error
+
+	// Encryption operations
+	// RewrapEncryptedData seals the stored content under the active encryption key, returning the rows updated
+	RewrapEncryptedData() (int64, error)
 }
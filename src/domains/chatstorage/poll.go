@@ -0,0 +1,25 @@
+package chatstorage
+
+import "time"
+
+// Poll is the definition of a poll sent or received by a device. EncKey is the message secret
+// needed to decrypt the votes.
+type Poll struct {
+	DeviceID        string    `db:"device_id"`
+	MessageID       string    `db:"message_id"`
+	ChatJID         string    `db:"chat_jid"`
+	Question        string    `db:"question"`
+	Options         []string  `db:"options"`
+	SelectableCount int       `db:"selectable_count"`
+	EncKey          []byte    `db:"enc_key"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// PollVote is the latest vote of one voter on a poll. An empty selection means the vote was withdrawn.
+type PollVote struct {
+	DeviceID        string    `db:"device_id"`
+	MessageID       string    `db:"message_id"`
+	VoterJID        string    `db:"voter_jid"`
+	SelectedOptions []string  `db:"selected_options"`
+	VotedAt         time.Time `db:"voted_at"`
+}
@@ -0,0 +1,50 @@
+package chatstorage
+
+import "time"
+
+// Delivery states of an outgoing message, from least to most advanced
+const (
+	ReceiptStatusSent      = "sent"
+	ReceiptStatusDelivered = "delivered"
+	ReceiptStatusRead      = "read"
+	ReceiptStatusPlayed    = "played"
+)
+
+// MessageReceipt records when one recipient of an outgoing message (one participant in groups)
+// received, read and played it. Timestamps stay nil until the matching receipt arrives.
+type MessageReceipt struct {
+	DeviceID     string     `db:"device_id"`
+	MessageID    string     `db:"message_id"`
+	ChatJID      string     `db:"chat_jid"`
+	RecipientJID string     `db:"recipient_jid"`
+	DeliveredAt  *time.Time `db:"delivered_at"`
+	ReadAt       *time.Time `db:"read_at"`
+	PlayedAt     *time.Time `db:"played_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+// Status returns the most advanced state the recipient reached
+func (r *MessageReceipt) Status() string {
+	switch {
+	case r.PlayedAt != nil:
+		return ReceiptStatusPlayed
+	case r.ReadAt != nil:
+		return ReceiptStatusRead
+	case r.DeliveredAt != nil:
+		return ReceiptStatusDelivered
+	default:
+		return ReceiptStatusSent
+	}
+}
+
+// ReceiptStatus returns the most advanced state reached by any recipient, or sent when no receipt arrived yet
+func ReceiptStatus(receipts []*MessageReceipt) string {
+	rank := map[string]int{ReceiptStatusSent: 0, ReceiptStatusDelivered: 1, ReceiptStatusRead: 2, ReceiptStatusPlayed: 3}
+	status := ReceiptStatusSent
+	for _, receipt := range receipts {
+		if current := receipt.Status(); rank[current] > rank[status] {
+			status = current
+		}
+	}
+	return status
+}
@@ -0,0 +1,29 @@
+package chatstorage
+
+import "time"
+
+// Markers placed around matched terms in search snippets
+const (
+	SearchHighlightStart = "<mark>"
+	SearchHighlightEnd   = "</mark>"
+)
+
+// MessageSearchFilter represents a search over the stored messages of every chat
+type MessageSearchFilter struct {
+	DeviceID  string
+	Query     string
+	ChatJID   string
+	Sender    string
+	MediaType string
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int
+	Offset    int
+}
+
+// MessageSearchResult is a message matching a search, with a snippet of its content
+// where the matched terms are wrapped in the highlight markers
+type MessageSearchResult struct {
+	Message *Message
+	Snippet string
+}
@@ -0,0 +1,15 @@
+package idempotency
+
+import "time"
+
+// Record is the outcome of a request sent with an Idempotency-Key header.
+// A record that is not Completed belongs to a request that is still being handled.
+type Record struct {
+	DeviceID   string
+	Key        string
+	Path       string
+	Completed  bool
+	StatusCode int
+	Response   []byte
+	CreatedAt  time.Time
+}
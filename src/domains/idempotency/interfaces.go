@@ -0,0 +1,13 @@
+package idempotency
+
+import "time"
+
+// IIdempotencyRepository persists idempotency keys per device.
+type IIdempotencyRepository interface {
+	// ReserveKey stores a pending record unless the device already holds the key since expiredBefore.
+	// It returns false together with the live record when the key is taken.
+	ReserveKey(record *Record, expiredBefore time.Time) (bool, *Record, error)
+	CompleteKey(deviceID, key string, statusCode int, response []byte) error
+	ReleaseKey(deviceID, key string) error
+	PurgeKeysBefore(before time.Time) (int64, error)
+}
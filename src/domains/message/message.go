@@ -49,5 +49,45 @@ type DownloadMediaResponse struct {
 	MediaType string `json:"media_type"`
 	Filename  string `json:"filename"`
 	FilePath  string `json:"file_path"`
+	FileURL   string `json:"file_url"`
 	FileSize  int64  `json:"file_size"`
 }
+
+type MessageStatusRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+type RecipientStatus struct {
+	RecipientJID string `json:"recipient_jid"`
+	Status       string `json:"status"`
+	DeliveredAt  string `json:"delivered_at,omitempty"`
+	ReadAt       string `json:"read_at,omitempty"`
+	PlayedAt     string `json:"played_at,omitempty"`
+}
+
+type MessageStatusResponse struct {
+	MessageID  string            `json:"message_id"`
+	ChatJID    string            `json:"chat_jid"`
+	Status     string            `json:"status"`
+	SentAt     string            `json:"sent_at"`
+	Recipients []RecipientStatus `json:"recipients"`
+}
+
+type PollResultsRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+type PollOptionResult struct {
+	Option string   `json:"option"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+type PollResultsResponse struct {
+	MessageID       string             `json:"message_id"`
+	ChatJID         string             `json:"chat_jid"`
+	Question        string             `json:"question"`
+	SelectableCount int                `json:"selectable_count"`
+	TotalVoters     int                `json:"total_voters"`
+	Options         []PollOptionResult `json:"options"`
+}
@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// IQueueRepository persists queued send jobs.
+type IQueueRepository interface {
+	EnqueueJob(job *Job) error
+	GetJob(deviceID, jobID string) (*Job, error)
+	// NextQueuedJob returns the oldest queued job of a device, or nil when its queue is empty.
+	NextQueuedJob(deviceID string) (*Job, error)
+	// ClaimJob moves a queued job to sending. It returns false when another worker took it first.
+	ClaimJob(jobID string) (bool, error)
+	// RequeueJob puts a job that could not be sent because its device went offline back in the queue.
+	RequeueJob(jobID string) error
+	MarkJobSent(jobID, messageID string, sentAt time.Time) error
+	MarkJobFailed(jobID, lastError string) error
+	// FailInterruptedJobs marks jobs left in sending by a crash as failed rather than risking a duplicate send.
+	FailInterruptedJobs() (int64, error)
+}
+
+// IQueueUsecase accepts send requests for the device in ctx and flushes them while it is online.
+type IQueueUsecase interface {
+	Enqueue(ctx context.Context, request EnqueueRequest) (Job, error)
+	Get(ctx context.Context, jobID string) (Job, error)
+
+	// Run starts one flush worker per registered device and blocks until ctx is cancelled.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,36 @@
+package queue
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusSending Status = "sending"
+	StatusSent    Status = "sent"
+	StatusFailed  Status = "failed"
+)
+
+// Job is a send request accepted with queue=true. It is sent in order once its device is online.
+// Payload holds the JSON body of the matching domainSend request.
+type Job struct {
+	ID        string          `json:"id"`
+	DeviceID  string          `json:"device_id"`
+	Type      string          `json:"type"`
+	Phone     string          `json:"phone"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    Status          `json:"status"`
+	MessageID string          `json:"message_id,omitempty"`
+	LastError string          `json:"last_error,omitempty"`
+	SentAt    *time.Time      `json:"sent_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type EnqueueRequest struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"-"`
+}
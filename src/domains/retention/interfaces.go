@@ -0,0 +1,26 @@
+package retention
+
+import "context"
+
+// IRetentionRepository persists retention policies.
+type IRetentionRepository interface {
+	SavePolicy(policy *Policy) error
+	GetPolicy(policyID string) (*Policy, error)
+	// GetPolicyByTarget returns the policy of exactly this device and chat, or nil when there is none
+	GetPolicyByTarget(deviceID, chatJID string) (*Policy, error)
+	ListPolicies() ([]*Policy, error)
+	DeletePolicy(policyID string) error
+}
+
+// IRetentionUsecase manages retention policies and enforces them.
+type IRetentionUsecase interface {
+	ListPolicies(ctx context.Context) ([]Policy, error)
+	CreatePolicy(ctx context.Context, request CreatePolicyRequest) (Policy, error)
+	UpdatePolicy(ctx context.Context, request UpdatePolicyRequest) (Policy, error)
+	DeletePolicy(ctx context.Context, policyID string) error
+
+	// Report returns what enforcing the policies right now would delete, without deleting anything.
+	Report(ctx context.Context) (Report, error)
+	// Run enforces the policies periodically and blocks until ctx is cancelled.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,68 @@
+package retention
+
+import "time"
+
+// Scope tells which data a retention policy applies to. The most specific policy wins per setting:
+// chat on a device, chat on every device, device, then global.
+type Scope string
+
+const (
+	ScopeGlobal Scope = "global"
+	ScopeDevice Scope = "device"
+	ScopeChat   Scope = "chat"
+)
+
+// Policy limits how long messages and media files are kept. A nil setting inherits the value of the
+// next broader policy, zero keeps the data forever.
+type Policy struct {
+	ID          string    `json:"id"`
+	Scope       Scope     `json:"scope"`
+	DeviceID    string    `json:"device_id,omitempty"`
+	ChatJID     string    `json:"chat_jid,omitempty"`
+	MessageDays *int      `json:"message_days"`
+	MediaDays   *int      `json:"media_days"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreatePolicyRequest struct {
+	Scope       Scope  `json:"scope" form:"scope"`
+	DeviceID    string `json:"device_id" form:"device_id"`
+	ChatJID     string `json:"chat_jid" form:"chat_jid"`
+	MessageDays *int   `json:"message_days" form:"message_days"`
+	MediaDays   *int   `json:"media_days" form:"media_days"`
+}
+
+// UpdatePolicyRequest replaces both settings of a policy, a missing setting becomes inherited
+type UpdatePolicyRequest struct {
+	PolicyID    string `json:"policy_id" uri:"policy_id"`
+	MessageDays *int   `json:"message_days" form:"message_days"`
+	MediaDays   *int   `json:"media_days" form:"media_days"`
+}
+
+// Report lists what a retention run deleted, or would delete on a dry run
+type Report struct {
+	DryRun            bool          `json:"dry_run"`
+	StartedAt         time.Time     `json:"started_at"`
+	MessagesDeleted   int           `json:"messages_deleted"`
+	MediaFilesDeleted int           `json:"media_files_deleted"`
+	MediaBytesFreed   int64         `json:"media_bytes_freed"`
+	Chats             []ChatReport  `json:"chats"`
+	Media             []MediaReport `json:"media"`
+}
+
+// ChatReport counts the expired messages of a chat
+type ChatReport struct {
+	DeviceID        string    `json:"device_id"`
+	ChatJID         string    `json:"chat_jid"`
+	Cutoff          time.Time `json:"cutoff"`
+	MessagesDeleted int       `json:"messages_deleted"`
+}
+
+// MediaReport counts the expired files of a folder under the media path, one folder per chat
+type MediaReport struct {
+	Folder       string    `json:"folder"`
+	Cutoff       time.Time `json:"cutoff"`
+	FilesDeleted int       `json:"files_deleted"`
+	BytesFreed   int64     `json:"bytes_freed"`
+}
@@ -0,0 +1,37 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// IScheduleRepository persists scheduled messages.
+type IScheduleRepository interface {
+	CreateSchedule(schedule *ScheduledMessage) error
+	GetSchedule(deviceID, scheduleID string) (*ScheduledMessage, error)
+	ListSchedules(filter *Filter) ([]*ScheduledMessage, int64, error)
+	FetchDueSchedules(deviceID string, now time.Time, limit int) ([]*ScheduledMessage, error)
+	// NextSendAt returns the earliest pending send time of a device, or nil when nothing is scheduled.
+	NextSendAt(deviceID string) (*time.Time, error)
+	// ClaimSchedule moves a due schedule to sending. It returns false when it was cancelled or rescheduled meanwhile.
+	ClaimSchedule(scheduleID string) (bool, error)
+	MarkScheduleSent(scheduleID, messageID string, sentAt time.Time) error
+	MarkScheduleFailed(scheduleID, lastError string) error
+	// UpdateScheduleStatus changes the status only while the schedule is still in one of the given states.
+	UpdateScheduleStatus(deviceID, scheduleID string, status Status, from ...Status) (bool, error)
+	RescheduleSchedule(deviceID, scheduleID string, sendAt time.Time) (bool, error)
+	// FailInterruptedSchedules marks schedules left in sending by a crash as failed rather than risking a duplicate send.
+	FailInterruptedSchedules() (int64, error)
+}
+
+// IScheduleUsecase manages scheduled messages of the device in ctx.
+type IScheduleUsecase interface {
+	Schedule(ctx context.Context, request CreateRequest) (ScheduledMessage, error)
+	List(ctx context.Context, request ListRequest) (ListResponse, error)
+	Get(ctx context.Context, scheduleID string) (ScheduledMessage, error)
+	Cancel(ctx context.Context, scheduleID string) (ScheduledMessage, error)
+	Reschedule(ctx context.Context, request RescheduleRequest) (ScheduledMessage, error)
+
+	// Run starts one dispatch worker per registered device and blocks until ctx is cancelled.
+	Run(ctx context.Context)
+}
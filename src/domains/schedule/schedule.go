@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusScheduled Status = "scheduled"
+	StatusSending   Status = "sending"
+	StatusSent      Status = "sent"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// ScheduledMessage is a send request persisted for future delivery.
+// Payload holds the JSON body of the matching domainSend request.
+type ScheduledMessage struct {
+	ID        string          `json:"id"`
+	DeviceID  string          `json:"device_id"`
+	Type      string          `json:"type"`
+	Phone     string          `json:"phone"`
+	Payload   json.RawMessage `json:"payload"`
+	SendAt    time.Time       `json:"send_at"`
+	Status    Status          `json:"status"`
+	MessageID string          `json:"message_id,omitempty"`
+	LastError string          `json:"last_error,omitempty"`
+	SentAt    *time.Time      `json:"sent_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Filter narrows schedule listings.
+type Filter struct {
+	DeviceID string
+	Status   Status
+	Limit    int
+	Offset   int
+}
+
+type CreateRequest struct {
+	Type    string          `json:"type" uri:"type"`
+	SendAt  string          `json:"send_at"`
+	Payload json.RawMessage `json:"-"`
+}
+
+type ListRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ListResponse struct {
+	Data       []ScheduledMessage `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type RescheduleRequest struct {
+	ScheduleID string `json:"schedule_id" uri:"schedule_id"`
+	SendAt     string `json:"send_at" form:"send_at"`
+}
+
+type PaginationResponse struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
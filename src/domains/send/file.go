@@ -4,6 +4,7 @@ import "mime/multipart"
 
 type FileRequest struct {
 	BaseRequest
+	TemplateRequest
 	File      *multipart.FileHeader `json:"-" form:"file"`
 	Caption   string                `json:"caption" form:"caption"`
 	FileName  *string               `json:"file_name" form:"file_name"`
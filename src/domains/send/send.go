@@ -4,3 +4,23 @@ type GenericResponse struct {
 	MessageID string `json:"message_id"`
 	Status    string `json:"status"`
 }
+
+// Request types that can be stored as JSON and sent later, named after their /send endpoints.
+const (
+	TypeMessage  = "message"
+	TypeImage    = "image"
+	TypeFile     = "file"
+	TypeVideo    = "video"
+	TypeAudio    = "audio"
+	TypeSticker  = "sticker"
+	TypeContact  = "contact"
+	TypeLink     = "link"
+	TypeLocation = "location"
+	TypePoll     = "poll"
+)
+
+// StorableTypes lists every request type accepted by deferred sends.
+var StorableTypes = []string{
+	TypeMessage, TypeImage, TypeFile, TypeVideo, TypeAudio,
+	TypeSticker, TypeContact, TypeLink, TypeLocation, TypePoll,
+}
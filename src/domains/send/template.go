@@ -0,0 +1,13 @@
+package send
+
+// TemplateRequest fills a message or caption from a stored template. Variables replace its {{name}}
+// placeholders and also apply to an inline message or caption when no template is referenced.
+type TemplateRequest struct {
+	TemplateID string            `json:"template_id,omitempty" form:"template_id"`
+	Variables  map[string]string `json:"variables,omitempty" form:"-"`
+}
+
+// IsTemplated reports whether the text has to be rendered before sending.
+func (t TemplateRequest) IsTemplated() bool {
+	return t.TemplateID != "" || t.Variables != nil
+}
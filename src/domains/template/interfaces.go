@@ -0,0 +1,21 @@
+package template
+
+import "context"
+
+// ITemplateRepository persists message templates.
+type ITemplateRepository interface {
+	SaveTemplate(template *Template) error
+	GetTemplate(templateID string) (*Template, error)
+	GetTemplateByName(name string) (*Template, error)
+	ListTemplates() ([]*Template, error)
+	DeleteTemplate(templateID string) error
+}
+
+// ITemplateUsecase manages the message templates shared by every device.
+type ITemplateUsecase interface {
+	ListTemplates(ctx context.Context) ([]Template, error)
+	GetTemplate(ctx context.Context, templateID string) (Template, error)
+	CreateTemplate(ctx context.Context, request CreateTemplateRequest) (Template, error)
+	UpdateTemplate(ctx context.Context, request UpdateTemplateRequest) (Template, error)
+	DeleteTemplate(ctx context.Context, templateID string) error
+}
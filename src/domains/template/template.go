@@ -0,0 +1,24 @@
+package template
+
+import "time"
+
+// Template is reusable message text. Placeholders are written as {{name}} and listed in Variables.
+type Template struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Content   string    `json:"content"`
+	Variables []string  `json:"variables"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateTemplateRequest struct {
+	Name    string `json:"name" form:"name"`
+	Content string `json:"content" form:"content"`
+}
+
+type UpdateTemplateRequest struct {
+	TemplateID string  `json:"template_id" uri:"template_id"`
+	Name       *string `json:"name" form:"name"`
+	Content    *string `json:"content" form:"content"`
+}
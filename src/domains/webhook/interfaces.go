@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+// IWebhookRepository persists the webhook outbox.
+type IWebhookRepository interface {
+	EnqueueDelivery(delivery *Delivery) error
+	FetchDueDeliveries(now time.Time, limit int) ([]*Delivery, error)
+	// ClaimDelivery leases a pending delivery for one attempt. It returns false when another worker won the race.
+	ClaimDelivery(id string, attempts int, leaseUntil time.Time) (bool, error)
+	MarkDeliverySucceeded(id string) error
+	MarkDeliveryFailed(id string, lastError string, nextAttemptAt time.Time, dead bool) error
+	GetDelivery(id string) (*Delivery, error)
+	ListDeliveries(filter *DeliveryFilter) ([]*Delivery, int64, error)
+	// RedriveDelivery resets a dead delivery for another round of attempts. It returns false when the delivery is not dead.
+	RedriveDelivery(id string) (bool, error)
+	RedriveDeadDeliveries(deviceID string) (int64, error)
+	PurgeDeliveredBefore(before time.Time) (int64, error)
+	// HasPendingDelivery reports whether the payload of a pending delivery contains text
+	HasPendingDelivery(text string) (bool, error)
+
+	// Per-device subscriptions
+	SaveSubscription(subscription *Subscription) error
+	GetSubscription(deviceID, subscriptionID string) (*Subscription, error)
+	GetSubscriptionByID(subscriptionID string) (*Subscription, error)
+	ListSubscriptions(deviceID string) ([]*Subscription, error)
+	DeleteSubscription(deviceID, subscriptionID string) error
+}
+
+// IWebhookUsecase exposes outbox inspection and re-drive operations.
+type IWebhookUsecase interface {
+	ListDeliveries(ctx context.Context, request ListDeliveriesRequest) (ListDeliveriesResponse, error)
+	GetDelivery(ctx context.Context, deliveryID string) (Delivery, error)
+	RedriveDelivery(ctx context.Context, deliveryID string) (Delivery, error)
+	RedriveDeadDeliveries(ctx context.Context, request RedriveDeliveriesRequest) (RedriveDeliveriesResponse, error)
+
+	ListSubscriptions(ctx context.Context, deviceID string) ([]SubscriptionResponse, error)
+	GetSubscription(ctx context.Context, deviceID, subscriptionID string) (SubscriptionResponse, error)
+	CreateSubscription(ctx context.Context, request CreateSubscriptionRequest) (SubscriptionResponse, error)
+	UpdateSubscription(ctx context.Context, request UpdateSubscriptionRequest) (SubscriptionResponse, error)
+	DeleteSubscription(ctx context.Context, deviceID, subscriptionID string) error
+
+	// Replay re-emits stored messages of the device in ctx to a single URL.
+	Replay(ctx context.Context, request ReplayRequest) (ReplayResponse, error)
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeliveryStatus describes where a webhook delivery sits in the outbox lifecycle.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusDead      DeliveryStatus = "dead"
+)
+
+// Delivery is a single payload queued for a single webhook URL.
+type Delivery struct {
+	ID             string          `json:"id"`
+	DeviceID       string          `json:"device_id"`
+	Event          string          `json:"event"`
+	URL            string          `json:"url"`
+	Payload        json.RawMessage `json:"payload"`
+	SubscriptionID string          `json:"subscription_id,omitempty"`
+	TraceParent    string          `json:"traceparent,omitempty"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// DeliveryFilter narrows outbox listings.
+type DeliveryFilter struct {
+	DeviceID string
+	Status   DeliveryStatus
+	Event    string
+	Limit    int
+	Offset   int
+}
+
+type ListDeliveriesRequest struct {
+	DeviceID string `json:"device_id" query:"device_id"`
+	Status   string `json:"status" query:"status"`
+	Event    string `json:"event" query:"event"`
+	Limit    int    `json:"limit" query:"limit"`
+	Offset   int    `json:"offset" query:"offset"`
+}
+
+type ListDeliveriesResponse struct {
+	Data       []Delivery         `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type RedriveDeliveriesRequest struct {
+	DeviceID string `json:"device_id" query:"device_id"`
+}
+
+type RedriveDeliveriesResponse struct {
+	Redriven int64 `json:"redriven"`
+}
+
+type PaginationResponse struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
+// Subscription is a webhook target owned by a single device.
+// Events is a whitelist of event names; an empty list receives every event.
+type Subscription struct {
+	ID        string    `json:"id"`
+	DeviceID  string    `json:"device_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SubscriptionResponse struct {
+	Subscription
+	HasSecret bool `json:"has_secret"`
+}
+
+type CreateSubscriptionRequest struct {
+	DeviceID string   `json:"device_id" uri:"device_id"`
+	URL      string   `json:"url" form:"url"`
+	Secret   string   `json:"secret" form:"secret"`
+	Events   []string `json:"events" form:"events"`
+	Enabled  *bool    `json:"enabled" form:"enabled"`
+}
+
+type UpdateSubscriptionRequest struct {
+	DeviceID       string    `json:"device_id" uri:"device_id"`
+	SubscriptionID string    `json:"subscription_id" uri:"subscription_id"`
+	URL            *string   `json:"url" form:"url"`
+	Secret         *string   `json:"secret" form:"secret"`
+	Events         *[]string `json:"events" form:"events"`
+	Enabled        *bool     `json:"enabled" form:"enabled"`
+}
+
+// ReplayRequest selects stored messages of the current device to re-emit to a single URL.
+// StartTime and EndTime are RFC3339; at least a chat or a start time is required. Cursor continues a
+// replay that stopped at its limit and is sent together with the same filters.
+type ReplayRequest struct {
+	WebhookURL string `json:"webhook_url" form:"webhook_url"`
+	ChatJID    string `json:"chat_jid" form:"chat_jid"`
+	StartTime  string `json:"start_time" form:"start_time"`
+	EndTime    string `json:"end_time" form:"end_time"`
+	Limit      int    `json:"limit" form:"limit"`
+	Cursor     string `json:"cursor" form:"cursor"`
+}
+
+// ReplayResponse reports the outcome of a replay. NextCursor is set when the limit cut the window short.
+type ReplayResponse struct {
+	Matched    int      `json:"matched"`
+	Replayed   int      `json:"replayed"`
+	Failed     []string `json:"failed"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,146 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/google/uuid"
+)
+
+// APIKeyRepository stores API keys next to the chat storage tables
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates an API key repository on top of the chat storage database
+func NewAPIKeyRepository(db *sql.DB) domainAPIKey.IAPIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// CreateKey inserts a new key, keys are never updated apart from being used or revoked
+func (r *APIKeyRepository) CreateKey(key *domainAPIKey.APIKey) error {
+	if key == nil || key.KeyHash == "" {
+		return fmt.Errorf("api key with hash is required")
+	}
+
+	devices, err := json.Marshal(nonNilStrings(key.Devices))
+	if err != nil {
+		return fmt.Errorf("failed to encode api key devices: %w", err)
+	}
+	permissions, err := json.Marshal(nonNilStrings(key.Permissions))
+	if err != nil {
+		return fmt.Errorf("failed to encode api key permissions: %w", err)
+	}
+
+	if key.ID == "" {
+		key.ID = uuid.NewString()
+	}
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now().UTC()
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO api_keys (id, name, prefix, key_hash, devices, permissions, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, key.ID, key.Name, key.Prefix, key.KeyHash, string(devices), string(permissions), key.CreatedAt)
+	return err
+}
+
+// GetKey fetches a key by id, returning nil when it does not exist
+func (r *APIKeyRepository) GetKey(keyID string) (*domainAPIKey.APIKey, error) {
+	row := r.db.QueryRow(`
+		SELECT id, name, prefix, key_hash, devices, permissions, created_at, last_used_at, revoked_at
+		FROM api_keys WHERE id = ?
+	`, keyID)
+	return r.scanKeyRow(row)
+}
+
+// GetKeyByHash fetches a key by the hash of its plaintext, returning nil when it does not exist
+func (r *APIKeyRepository) GetKeyByHash(keyHash string) (*domainAPIKey.APIKey, error) {
+	row := r.db.QueryRow(`
+		SELECT id, name, prefix, key_hash, devices, permissions, created_at, last_used_at, revoked_at
+		FROM api_keys WHERE key_hash = ?
+	`, keyHash)
+	return r.scanKeyRow(row)
+}
+
+// ListKeys returns every key, revoked ones included, newest first
+func (r *APIKeyRepository) ListKeys() ([]*domainAPIKey.APIKey, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, prefix, key_hash, devices, permissions, created_at, last_used_at, revoked_at
+		FROM api_keys ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domainAPIKey.APIKey
+	for rows.Next() {
+		key, err := r.scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeKey marks a key as revoked, revoking it again keeps the first revocation time
+func (r *APIKeyRepository) RevokeKey(keyID string, revokedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", revokedAt.UTC(), keyID)
+	return err
+}
+
+// TouchKey records when a key was last used
+func (r *APIKeyRepository) TouchKey(keyID string, usedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", usedAt.UTC(), keyID)
+	return err
+}
+
+func (r *APIKeyRepository) scanKeyRow(row *sql.Row) (*domainAPIKey.APIKey, error) {
+	key, err := r.scanKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// scanKey is a private helper for scanning api key rows
+func (r *APIKeyRepository) scanKey(scanner interface{ Scan(...any) error }) (*domainAPIKey.APIKey, error) {
+	key := &domainAPIKey.APIKey{}
+	var devices, permissions string
+	var lastUsedAt, revokedAt sql.NullTime
+	err := scanner.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &devices, &permissions, &key.CreatedAt,
+		&lastUsedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(devices), &key.Devices); err != nil {
+		return nil, fmt.Errorf("failed to decode devices of api key %s: %w", key.ID, err)
+	}
+	if err := json.Unmarshal([]byte(permissions), &key.Permissions); err != nil {
+		return nil, fmt.Errorf("failed to decode permissions of api key %s: %w", key.ID, err)
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
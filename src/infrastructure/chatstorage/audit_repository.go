@@ -0,0 +1,163 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/google/uuid"
+)
+
+const auditColumns = `id, actor_type, actor, api_key_id, device_id, method, route, path, targets, summary,
+	status_code, success, error, duration_ms, remote_ip, created_at`
+
+// AuditRepository stores the audit log next to the chat storage tables
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates an audit log repository on top of the chat storage database
+func NewAuditRepository(db *sql.DB) domainAudit.IAuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// StoreEntry appends an entry, entries are never updated
+func (r *AuditRepository) StoreEntry(entry *domainAudit.Entry) error {
+	if entry == nil {
+		return fmt.Errorf("audit entry is required")
+	}
+
+	targets, err := json.Marshal(nonNilStrings(entry.Targets))
+	if err != nil {
+		return fmt.Errorf("failed to encode audit targets: %w", err)
+	}
+	summary := entry.Summary
+	if summary == nil {
+		summary = map[string]string{}
+	}
+	encodedSummary, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit summary: %w", err)
+	}
+
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	entry.CreatedAt = entry.CreatedAt.UTC()
+
+	_, err = r.db.Exec(`INSERT INTO audit_log (`+auditColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.ActorType, entry.Actor, entry.APIKeyID, entry.DeviceID, entry.Method, entry.Route, entry.Path,
+		string(targets), string(encodedSummary), entry.StatusCode, entry.Success, entry.Error, entry.DurationMs,
+		entry.RemoteIP, entry.CreatedAt)
+	return err
+}
+
+// ListEntries returns entries matching the filter, newest first, together with the total count
+func (r *AuditRepository) ListEntries(filter *domainAudit.Filter) ([]*domainAudit.Entry, int64, error) {
+	if filter == nil {
+		filter = &domainAudit.Filter{}
+	}
+
+	var conditions []string
+	var args []any
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	// LIKE is case-sensitive on PostgreSQL but not on SQLite, lower both sides so both backends match the same rows
+	if filter.Route != "" {
+		conditions = append(conditions, "LOWER(route) LIKE ?")
+		args = append(args, strings.ToLower(filter.Route)+"%")
+	}
+	if filter.Target != "" {
+		// Targets are a JSON array of strings, match one element exactly
+		target, _ := json.Marshal(filter.Target)
+		conditions = append(conditions, "LOWER(targets) LIKE ?")
+		args = append(args, "%"+strings.ToLower(string(target))+"%")
+	}
+	if filter.StartTime != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.StartTime.UTC())
+	}
+	if filter.EndTime != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, filter.EndTime.UTC())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM audit_log"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + auditColumns + ` FROM audit_log` + where + ` ORDER BY created_at DESC`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []*domainAudit.Entry
+	for rows.Next() {
+		entry, err := r.scanEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// PurgeEntriesBefore deletes entries created before the cutoff
+func (r *AuditRepository) PurgeEntriesBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM audit_log WHERE created_at < ?", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// scanEntry is a private helper for scanning audit log rows
+func (r *AuditRepository) scanEntry(scanner interface{ Scan(...any) error }) (*domainAudit.Entry, error) {
+	entry := &domainAudit.Entry{}
+	var targets, summary string
+	err := scanner.Scan(&entry.ID, &entry.ActorType, &entry.Actor, &entry.APIKeyID, &entry.DeviceID, &entry.Method,
+		&entry.Route, &entry.Path, &targets, &summary, &entry.StatusCode, &entry.Success, &entry.Error,
+		&entry.DurationMs, &entry.RemoteIP, &entry.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(targets), &entry.Targets); err != nil {
+		return nil, fmt.Errorf("failed to decode targets of audit entry %s: %w", entry.ID, err)
+	}
+	if err := json.Unmarshal([]byte(summary), &entry.Summary); err != nil {
+		return nil, fmt.Errorf("failed to decode summary of audit entry %s: %w", entry.ID, err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,422 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	"github.com/google/uuid"
+)
+
+// CampaignRepository stores broadcast campaigns and their recipients next to the chat storage tables
+type CampaignRepository struct {
+	db *sql.DB
+}
+
+// NewCampaignRepository creates a campaign repository on top of the chat storage database
+func NewCampaignRepository(db *sql.DB) domainCampaign.ICampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+const (
+	campaignColumns = `id, device_id, name, type, message, rate_per_minute, jitter_seconds, daily_cap,
+	status, completed_at, created_at, updated_at`
+	campaignRecipientColumns = `id, campaign_id, phone, variables, status, message_id, error, sent_at, updated_at`
+)
+
+// CreateCampaign inserts a campaign together with all of its recipients in one transaction
+func (r *CampaignRepository) CreateCampaign(campaign *domainCampaign.Campaign, recipients []*domainCampaign.Recipient) error {
+	if campaign == nil || strings.TrimSpace(campaign.DeviceID) == "" || campaign.Type == "" {
+		return fmt.Errorf("campaign with device id and type is required")
+	}
+
+	now := time.Now().UTC()
+	if campaign.ID == "" {
+		campaign.ID = uuid.NewString()
+	}
+	if campaign.Status == "" {
+		campaign.Status = domainCampaign.StatusRunning
+	}
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO campaigns (
+			id, device_id, name, type, message, rate_per_minute, jitter_seconds, daily_cap,
+			status, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, campaign.ID, campaign.DeviceID, campaign.Name, campaign.Type, string(campaign.Message),
+		campaign.RatePerMinute, campaign.JitterSeconds, campaign.DailyCap,
+		campaign.Status, campaign.CreatedAt, campaign.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO campaign_recipients (
+			id, campaign_id, device_id, position, phone, variables, status, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, recipient := range recipients {
+		variables, err := json.Marshal(recipient.Variables)
+		if err != nil {
+			return fmt.Errorf("failed to encode variables of %s: %w", recipient.Phone, err)
+		}
+
+		recipient.ID = uuid.NewString()
+		recipient.CampaignID = campaign.ID
+		recipient.Status = domainCampaign.RecipientQueued
+		recipient.UpdatedAt = now
+		if _, err := stmt.Exec(recipient.ID, campaign.ID, campaign.DeviceID, i, recipient.Phone,
+			string(variables), recipient.Status, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetCampaign fetches a campaign owned by the given device, returning nil when it does not exist
+func (r *CampaignRepository) GetCampaign(deviceID, campaignID string) (*domainCampaign.Campaign, error) {
+	row := r.db.QueryRow(`SELECT `+campaignColumns+` FROM campaigns WHERE id = ? AND device_id = ?`,
+		campaignID, deviceID)
+	campaign, err := r.scanCampaign(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// ListCampaigns returns campaigns matching the filter, newest first, together with the total count
+func (r *CampaignRepository) ListCampaigns(filter *domainCampaign.Filter) ([]*domainCampaign.Campaign, int64, error) {
+	if filter == nil {
+		filter = &domainCampaign.Filter{}
+	}
+
+	var conditions []string
+	var args []any
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM campaigns"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + campaignColumns + ` FROM campaigns` + where + ` ORDER BY created_at DESC`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var campaigns []*domainCampaign.Campaign
+	for rows.Next() {
+		campaign, err := r.scanCampaign(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	return campaigns, total, rows.Err()
+}
+
+// GetCampaignProgress counts the recipients of a campaign by status
+func (r *CampaignRepository) GetCampaignProgress(campaignID string) (domainCampaign.Progress, error) {
+	var progress domainCampaign.Progress
+
+	rows, err := r.db.Query(`
+		SELECT status, COUNT(*) FROM campaign_recipients WHERE campaign_id = ? GROUP BY status
+	`, campaignID)
+	if err != nil {
+		return progress, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status domainCampaign.RecipientStatus
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return progress, err
+		}
+
+		progress.Total += count
+		switch status {
+		case domainCampaign.RecipientQueued, domainCampaign.RecipientSending:
+			progress.Queued += count
+		case domainCampaign.RecipientSent:
+			progress.Sent += count
+		case domainCampaign.RecipientFailed:
+			progress.Failed += count
+		case domainCampaign.RecipientNotOnWhatsApp:
+			progress.NotOnWhatsApp += count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return progress, err
+	}
+
+	if progress.Total > 0 {
+		done := progress.Total - progress.Queued
+		progress.Percent = float64(done*10000/progress.Total) / 100
+	}
+	return progress, nil
+}
+
+// ListRecipients returns recipients of a campaign in send order together with the total count
+func (r *CampaignRepository) ListRecipients(filter *domainCampaign.RecipientFilter) ([]*domainCampaign.Recipient, int64, error) {
+	if filter == nil || filter.CampaignID == "" {
+		return nil, 0, fmt.Errorf("campaign id is required")
+	}
+
+	where := " WHERE campaign_id = ?"
+	args := []any{filter.CampaignID}
+	if filter.Status != "" {
+		where += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM campaign_recipients"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + campaignRecipientColumns + ` FROM campaign_recipients` + where + ` ORDER BY position ASC`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var recipients []*domainCampaign.Recipient
+	for rows.Next() {
+		recipient, err := r.scanRecipient(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, total, rows.Err()
+}
+
+// UpdateCampaignStatus changes the status of a campaign that is currently in one of the from states
+func (r *CampaignRepository) UpdateCampaignStatus(deviceID, campaignID string, status domainCampaign.Status, from ...domainCampaign.Status) (bool, error) {
+	query := `UPDATE campaigns SET status = ?, updated_at = ? WHERE id = ? AND device_id = ?`
+	args := []any{status, time.Now().UTC(), campaignID, deviceID}
+	if len(from) > 0 {
+		placeholders := make([]string, len(from))
+		for i, state := range from {
+			placeholders[i] = "?"
+			args = append(args, state)
+		}
+		query += " AND status IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// CompleteCampaign marks a running campaign completed when none of its recipients are waiting anymore
+func (r *CampaignRepository) CompleteCampaign(campaignID string) (bool, error) {
+	now := time.Now().UTC()
+	result, err := r.db.Exec(`
+		UPDATE campaigns SET status = ?, completed_at = ?, updated_at = ?
+		WHERE id = ? AND status = ? AND NOT EXISTS (
+			SELECT 1 FROM campaign_recipients WHERE campaign_id = ? AND status IN (?, ?)
+		)
+	`, domainCampaign.StatusCompleted, now, now, campaignID, domainCampaign.StatusRunning,
+		campaignID, domainCampaign.RecipientQueued, domainCampaign.RecipientSending)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// NextRunningCampaign returns the oldest running campaign of a device
+func (r *CampaignRepository) NextRunningCampaign(deviceID string) (*domainCampaign.Campaign, error) {
+	row := r.db.QueryRow(`
+		SELECT `+campaignColumns+` FROM campaigns
+		WHERE device_id = ? AND status = ?
+		ORDER BY created_at ASC
+		LIMIT 1
+	`, deviceID, domainCampaign.StatusRunning)
+	campaign, err := r.scanCampaign(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// NextQueuedRecipient returns the next recipient of a campaign in upload order
+func (r *CampaignRepository) NextQueuedRecipient(campaignID string) (*domainCampaign.Recipient, error) {
+	row := r.db.QueryRow(`
+		SELECT `+campaignRecipientColumns+` FROM campaign_recipients
+		WHERE campaign_id = ? AND status = ?
+		ORDER BY position ASC
+		LIMIT 1
+	`, campaignID, domainCampaign.RecipientQueued)
+	recipient, err := r.scanRecipient(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return recipient, nil
+}
+
+// ClaimRecipient moves a queued recipient to sending
+func (r *CampaignRepository) ClaimRecipient(recipientID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE campaign_recipients SET status = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`, domainCampaign.RecipientSending, time.Now().UTC(), recipientID, domainCampaign.RecipientQueued)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// UpdateRecipientResult records the outcome of sending to a recipient
+func (r *CampaignRepository) UpdateRecipientResult(recipientID string, status domainCampaign.RecipientStatus, messageID, errMsg string) error {
+	now := time.Now().UTC()
+	var sentAt any
+	if status == domainCampaign.RecipientSent {
+		sentAt = now
+	}
+
+	_, err := r.db.Exec(`
+		UPDATE campaign_recipients SET status = ?, message_id = ?, error = ?, sent_at = ?, updated_at = ?
+		WHERE id = ?
+	`, status, messageID, errMsg, sentAt, now, recipientID)
+	return err
+}
+
+// CountSentSince counts campaign messages sent by a device after the given time
+func (r *CampaignRepository) CountSentSince(deviceID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM campaign_recipients
+		WHERE device_id = ? AND status = ? AND sent_at > ?
+	`, deviceID, domainCampaign.RecipientSent, since.UTC()).Scan(&count)
+	return count, err
+}
+
+// FailInterruptedRecipients fails every recipient left in sending by an unclean shutdown
+func (r *CampaignRepository) FailInterruptedRecipients() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE campaign_recipients SET status = ?, error = ?, updated_at = ?
+		WHERE status = ?
+	`, domainCampaign.RecipientFailed, "interrupted while sending", time.Now().UTC(),
+		domainCampaign.RecipientSending)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// scanCampaign is a private helper for scanning campaign rows
+func (r *CampaignRepository) scanCampaign(scanner interface{ Scan(...any) error }) (*domainCampaign.Campaign, error) {
+	campaign := &domainCampaign.Campaign{}
+	var message string
+	var completedAt sql.NullTime
+	err := scanner.Scan(
+		&campaign.ID, &campaign.DeviceID, &campaign.Name, &campaign.Type, &message,
+		&campaign.RatePerMinute, &campaign.JitterSeconds, &campaign.DailyCap,
+		&campaign.Status, &completedAt, &campaign.CreatedAt, &campaign.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.Message = []byte(message)
+	if completedAt.Valid {
+		campaign.CompletedAt = &completedAt.Time
+	}
+	return campaign, nil
+}
+
+// scanRecipient is a private helper for scanning campaign recipient rows
+func (r *CampaignRepository) scanRecipient(scanner interface{ Scan(...any) error }) (*domainCampaign.Recipient, error) {
+	recipient := &domainCampaign.Recipient{}
+	var variables string
+	var sentAt sql.NullTime
+	err := scanner.Scan(
+		&recipient.ID, &recipient.CampaignID, &recipient.Phone, &variables, &recipient.Status,
+		&recipient.MessageID, &recipient.Error, &sentAt, &recipient.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if variables != "" {
+		if err := json.Unmarshal([]byte(variables), &recipient.Variables); err != nil {
+			return nil, fmt.Errorf("invalid variables of recipient %s: %w", recipient.ID, err)
+		}
+	}
+	if sentAt.Valid {
+		recipient.SentAt = &sentAt.Time
+	}
+	return recipient, nil
+}
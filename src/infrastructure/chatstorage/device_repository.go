@@ -118,6 +118,13 @@ func (r *DeviceRepository) SearchMessages(chatJID, searchText string, limit int)
 	return r.base.SearchMessages(chatJID, searchText, limit)
 }
 
+func (r *DeviceRepository) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, int64, error) {
+	if filter != nil && filter.DeviceID == "" {
+		filter.DeviceID = r.deviceID
+	}
+	return r.base.SearchAllMessages(filter)
+}
+
 func (r *DeviceRepository) DeleteMessage(id, chatJID string) error {
 	return r.base.DeleteMessageByDevice(r.deviceID, id, chatJID)
 }
@@ -130,6 +137,76 @@ func (r *DeviceRepository) StoreSentMessageWithContext(ctx context.Context, mess
 	return r.base.StoreSentMessageWithContext(ctx, messageID, senderJID, recipientJID, content, timestamp)
 }
 
+func (r *DeviceRepository) StoreReceipts(receipts []*domainChatStorage.MessageReceipt) error {
+	for _, receipt := range receipts {
+		if receipt != nil && receipt.DeviceID == "" {
+			receipt.DeviceID = r.deviceID
+		}
+	}
+	return r.base.StoreReceipts(receipts)
+}
+
+func (r *DeviceRepository) GetMessageReceipts(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageReceipt, error) {
+	return r.base.GetMessageReceipts(deviceID, messageIDs)
+}
+
+func (r *DeviceRepository) StoreReaction(reaction *domainChatStorage.MessageReaction) error {
+	if reaction != nil && reaction.DeviceID == "" {
+		reaction.DeviceID = r.deviceID
+	}
+	return r.base.StoreReaction(reaction)
+}
+
+func (r *DeviceRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	if edit != nil && edit.DeviceID == "" {
+		edit.DeviceID = r.deviceID
+	}
+	return r.base.StoreMessageEdit(edit)
+}
+
+func (r *DeviceRepository) MarkMessageRevoked(deviceID, chatJID, messageID, revokedBy string, revokedAt time.Time) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.MarkMessageRevoked(deviceID, chatJID, messageID, revokedBy, revokedAt)
+}
+
+func (r *DeviceRepository) GetMessageReactions(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageReaction, error) {
+	return r.base.GetMessageReactions(deviceID, messageIDs)
+}
+
+func (r *DeviceRepository) GetMessageEdits(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageEdit, error) {
+	return r.base.GetMessageEdits(deviceID, messageIDs)
+}
+
+func (r *DeviceRepository) StorePoll(poll *domainChatStorage.Poll) error {
+	if poll != nil && poll.DeviceID == "" {
+		poll.DeviceID = r.deviceID
+	}
+	return r.base.StorePoll(poll)
+}
+
+func (r *DeviceRepository) GetPoll(deviceID, messageID string) (*domainChatStorage.Poll, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetPoll(deviceID, messageID)
+}
+
+func (r *DeviceRepository) StorePollVote(vote *domainChatStorage.PollVote) error {
+	if vote != nil && vote.DeviceID == "" {
+		vote.DeviceID = r.deviceID
+	}
+	return r.base.StorePollVote(vote)
+}
+
+func (r *DeviceRepository) GetPollVotes(deviceID, messageID string) ([]*domainChatStorage.PollVote, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetPollVotes(deviceID, messageID)
+}
+
 func (r *DeviceRepository) GetChatMessageCount(chatJID string) (int64, error) {
 	return r.base.GetChatMessageCountByDevice(r.deviceID, chatJID)
 }
@@ -170,6 +247,10 @@ func (r *DeviceRepository) InitializeSchema() error {
 	return r.base.InitializeSchema()
 }
 
+func (r *DeviceRepository) RewrapEncryptedData() (int64, error) {
+	return r.base.RewrapEncryptedData()
+}
+
 func (r *DeviceRepository) DeleteDeviceData(deviceID string) error {
 	target := deviceID
 	if target == "" {
@@ -0,0 +1,235 @@
+package chatstorage
+
+import (
+	"fmt"
+	"strings"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
+)
+
+// rewrapBatchSize is the number of rows RewrapEncryptedData updates per transaction
+const rewrapBatchSize = 500
+
+// sealMessage returns a copy of a message with its content and media keys sealed for storage, the
+// caller keeps working with the plaintext
+func (r *SQLiteRepository) sealMessage(message *domainChatStorage.Message) (*domainChatStorage.Message, error) {
+	if r.keyring == nil {
+		return message, nil
+	}
+
+	sealed := *message
+	var err error
+	if sealed.Content, err = r.sealText(message.Content); err != nil {
+		return nil, err
+	}
+	for _, field := range []*[]byte{&sealed.MediaKey, &sealed.FileSHA256, &sealed.FileEncSHA256} {
+		if *field, err = r.sealBytes(*field); err != nil {
+			return nil, err
+		}
+	}
+	return &sealed, nil
+}
+
+// openMessage decrypts the content and media keys of a message read from storage in place
+func (r *SQLiteRepository) openMessage(message *domainChatStorage.Message) error {
+	var err error
+	if message.Content, err = r.openText(message.Content); err != nil {
+		return fmt.Errorf("message %s: %w", message.ID, err)
+	}
+	for _, field := range []*[]byte{&message.MediaKey, &message.FileSHA256, &message.FileEncSHA256} {
+		if *field, err = r.openBytes(*field); err != nil {
+			return fmt.Errorf("message %s: %w", message.ID, err)
+		}
+	}
+	return nil
+}
+
+// sealText seals a text column, empty values stay empty so checks like content != ” keep working
+func (r *SQLiteRepository) sealText(value string) (string, error) {
+	if r.keyring == nil || value == "" {
+		return value, nil
+	}
+	sealed, err := r.keyring.Seal([]byte(value))
+	return string(sealed), err
+}
+
+func (r *SQLiteRepository) openText(value string) (string, error) {
+	if !encryption.IsSealed([]byte(value)) {
+		return value, nil
+	}
+	if r.keyring == nil {
+		return "", fmt.Errorf("content is encrypted but no encryption key is configured")
+	}
+	plaintext, err := r.keyring.Open([]byte(value))
+	return string(plaintext), err
+}
+
+func (r *SQLiteRepository) sealBytes(value []byte) ([]byte, error) {
+	if r.keyring == nil || len(value) == 0 {
+		return value, nil
+	}
+	return r.keyring.Seal(value)
+}
+
+func (r *SQLiteRepository) openBytes(value []byte) ([]byte, error) {
+	if !encryption.IsSealed(value) {
+		return value, nil
+	}
+	if r.keyring == nil {
+		return nil, fmt.Errorf("media key is encrypted but no encryption key is configured")
+	}
+	return r.keyring.Open(value)
+}
+
+// matchesAllTerms reports whether content contains every term, ignoring case. With encryption the
+// database only holds ciphertext, so searches filter the decrypted messages instead of using LIKE.
+func matchesAllTerms(content string, terms []string) bool {
+	lowered := strings.ToLower(content)
+	for _, term := range terms {
+		if !strings.Contains(lowered, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// RewrapEncryptedData seals every message content, media key and edit revision under the active key:
+// plaintext written before encryption was enabled is encrypted and values sealed under a retired key
+// get their data key rewrapped. It returns the number of rows updated.
+func (r *SQLiteRepository) RewrapEncryptedData() (int64, error) {
+	if r.keyring == nil {
+		return 0, fmt.Errorf("encryption at rest is not enabled")
+	}
+
+	messages, err := r.rewrapTable("messages", []string{"device_id", "chat_jid", "id"}, []sealedColumn{
+		{name: "content", text: true}, {name: "media_key"}, {name: "file_sha256"}, {name: "file_enc_sha256"},
+	})
+	if err != nil {
+		return messages, fmt.Errorf("failed to rewrap messages: %w", err)
+	}
+
+	edits, err := r.rewrapTable("message_edits", []string{"device_id", "chat_jid", "message_id", "revision"}, []sealedColumn{
+		{name: "content", text: true},
+	})
+	if err != nil {
+		return messages + edits, fmt.Errorf("failed to rewrap message edits: %w", err)
+	}
+	return messages + edits, nil
+}
+
+type sealedColumn struct {
+	name string
+	// text columns are written back as strings, a []byte would change their SQLite storage class to BLOB
+	text bool
+}
+
+type rewrapRow struct {
+	keys   []any
+	values []any
+}
+
+// rewrapTable pages through a table in primary key order, which rewrapping does not change, and
+// updates the rows that hold plaintext or values sealed under a retired key
+func (r *SQLiteRepository) rewrapTable(table string, keyColumns []string, columns []sealedColumn) (int64, error) {
+	names := make([]string, len(columns))
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+		assignments[i] = column.name + " = ?"
+	}
+	selectQuery := fmt.Sprintf("SELECT %s, %s FROM %s ORDER BY %s LIMIT ? OFFSET ?",
+		strings.Join(keyColumns, ", "), strings.Join(names, ", "), table, strings.Join(keyColumns, ", "))
+	updateQuery := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?",
+		table, strings.Join(assignments, ", "), strings.Join(keyColumns, " = ? AND "))
+
+	var updated int64
+	for offset := 0; ; offset += rewrapBatchSize {
+		pending, scanned, err := r.rewrapPage(selectQuery, offset, len(keyColumns), columns)
+		if err != nil {
+			return updated, err
+		}
+		if len(pending) > 0 {
+			if err := r.updateRewrapped(updateQuery, pending); err != nil {
+				return updated, err
+			}
+			updated += int64(len(pending))
+		}
+		if scanned < rewrapBatchSize {
+			return updated, nil
+		}
+	}
+}
+
+func (r *SQLiteRepository) rewrapPage(selectQuery string, offset, keyCount int, columns []sealedColumn) ([]rewrapRow, int, error) {
+	rows, err := r.db.Query(selectQuery, rewrapBatchSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var pending []rewrapRow
+	scanned := 0
+	for rows.Next() {
+		scanned++
+		keys := make([]any, keyCount)
+		values := make([][]byte, len(columns))
+		dest := make([]any, 0, keyCount+len(columns))
+		for i := range keys {
+			dest = append(dest, &keys[i])
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, scanned, err
+		}
+
+		row := rewrapRow{keys: keys, values: make([]any, len(columns))}
+		changed := false
+		for i, value := range values {
+			if value == nil {
+				// Keep NULL columns NULL
+				continue
+			}
+			if len(value) > 0 {
+				rewrapped, ok, err := r.keyring.Rewrap(value)
+				if err != nil {
+					return nil, scanned, fmt.Errorf("row %v: %w", keys, err)
+				}
+				changed = changed || ok
+				value = rewrapped
+			}
+			if columns[i].text {
+				row.values[i] = string(value)
+			} else {
+				row.values[i] = value
+			}
+		}
+		if changed {
+			pending = append(pending, row)
+		}
+	}
+	return pending, scanned, rows.Err()
+}
+
+func (r *SQLiteRepository) updateRewrapped(updateQuery string, rows []rewrapRow) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(updateQuery)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.Exec(append(row.values, row.keys...)...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
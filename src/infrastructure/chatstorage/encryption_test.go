@@ -0,0 +1,102 @@
+package chatstorage
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEncryptedRepository(t *testing.T, keyring *encryption.Keyring) *SQLiteRepository {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	repo := &SQLiteRepository{db: db, keyring: keyring}
+	require.NoError(t, repo.InitializeSchema())
+	return repo
+}
+
+func testEncryptionKeyring(t *testing.T, active string, ids ...string) *encryption.Keyring {
+	t.Helper()
+	keys := make(map[string][]byte, len(ids))
+	for _, id := range ids {
+		keys[id] = bytes.Repeat([]byte(id[:1]), encryption.KeySize)
+	}
+	keyring, err := encryption.NewKeyring(active, keys)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestSQLiteRepository_EncryptedMessages(t *testing.T) {
+	repo := newEncryptedRepository(t, testEncryptionKeyring(t, "a", "a"))
+	require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+		ID: "M1", ChatJID: "628123@s.whatsapp.net", DeviceID: "dev", Sender: "628123@s.whatsapp.net",
+		Content: "Invoice for March", Timestamp: time.Now(), MediaType: "image", MediaKey: []byte("media-key"),
+	}))
+
+	var content string
+	var mediaKey []byte
+	require.NoError(t, repo.db.QueryRow("SELECT content, media_key FROM messages WHERE id = 'M1'").Scan(&content, &mediaKey))
+	assert.True(t, encryption.IsSealed([]byte(content)))
+	assert.True(t, encryption.IsSealed(mediaKey))
+
+	message, err := repo.GetMessageByID("M1")
+	require.NoError(t, err)
+	assert.Equal(t, "Invoice for March", message.Content)
+	assert.Equal(t, []byte("media-key"), message.MediaKey)
+
+	found, err := repo.SearchMessages("628123@s.whatsapp.net", "invoice", 10)
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	results, total, err := repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{Query: "march invoice", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, results, 1)
+	assert.Equal(t, "<mark>Invoice</mark> for <mark>March</mark>", results[0].Snippet)
+
+	require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+		DeviceID: "dev", ChatJID: "628123@s.whatsapp.net", MessageID: "M1", Content: "Invoice for April", EditedAt: time.Now(),
+	}))
+	edits, err := repo.GetMessageEdits("dev", []string{"M1"})
+	require.NoError(t, err)
+	require.Len(t, edits["M1"], 2)
+	assert.Equal(t, "Invoice for March", edits["M1"][0].Content)
+	assert.Equal(t, "Invoice for April", edits["M1"][1].Content)
+}
+
+func TestSQLiteRepository_RewrapEncryptedData(t *testing.T) {
+	plain := newEncryptedRepository(t, nil)
+	require.NoError(t, plain.StoreMessage(&domainChatStorage.Message{
+		ID: "M1", ChatJID: "628123@s.whatsapp.net", DeviceID: "dev", Content: "stored before encryption", Timestamp: time.Now(),
+	}))
+	_, err := plain.RewrapEncryptedData()
+	assert.Error(t, err)
+
+	// Enabling encryption seals the existing rows, rotating rewraps them under the new key
+	plain.keyring = testEncryptionKeyring(t, "a", "a")
+	updated, err := plain.RewrapEncryptedData()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	plain.keyring = testEncryptionKeyring(t, "b", "a", "b")
+	updated, err = plain.RewrapEncryptedData()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	plain.keyring = testEncryptionKeyring(t, "b", "b")
+	updated, err = plain.RewrapEncryptedData()
+	require.NoError(t, err)
+	assert.Zero(t, updated)
+	message, err := plain.GetMessageByID("M1")
+	require.NoError(t, err)
+	assert.Equal(t, "stored before encryption", message.Content)
+}
@@ -0,0 +1,96 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+)
+
+// IdempotencyRepository stores the responses of idempotent send requests
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository creates an idempotency key repository on top of the chat storage database
+func NewIdempotencyRepository(db *sql.DB) domainIdempotency.IIdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// ReserveKey inserts a pending key, taking over a row that expired before expiredBefore
+func (r *IdempotencyRepository) ReserveKey(record *domainIdempotency.Record, expiredBefore time.Time) (bool, *domainIdempotency.Record, error) {
+	if record == nil || strings.TrimSpace(record.DeviceID) == "" || strings.TrimSpace(record.Key) == "" {
+		return false, nil, fmt.Errorf("idempotency record with device id and key is required")
+	}
+
+	record.Completed = false
+	record.StatusCode = 0
+	record.Response = nil
+	record.CreatedAt = time.Now().UTC()
+
+	result, err := r.db.Exec(`
+		INSERT INTO idempotency_keys (device_id, idempotency_key, path, completed, status_code, response, created_at)
+		VALUES (?, ?, ?, FALSE, 0, NULL, ?)
+		ON CONFLICT(device_id, idempotency_key) DO UPDATE SET
+			path = excluded.path,
+			completed = FALSE,
+			status_code = 0,
+			response = NULL,
+			created_at = excluded.created_at
+		WHERE idempotency_keys.created_at < ?
+	`, record.DeviceID, record.Key, record.Path, record.CreatedAt, expiredBefore.UTC())
+	if err != nil {
+		return false, nil, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected > 0 {
+		return true, nil, nil
+	}
+
+	existing, err := r.getKey(record.DeviceID, record.Key)
+	return false, existing, err
+}
+
+// CompleteKey stores the response of the request holding the key
+func (r *IdempotencyRepository) CompleteKey(deviceID, key string, statusCode int, response []byte) error {
+	_, err := r.db.Exec(`
+		UPDATE idempotency_keys SET completed = TRUE, status_code = ?, response = ?
+		WHERE device_id = ? AND idempotency_key = ?
+	`, statusCode, string(response), deviceID, key)
+	return err
+}
+
+// ReleaseKey forgets a key so the request can be retried
+func (r *IdempotencyRepository) ReleaseKey(deviceID, key string) error {
+	_, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE device_id = ? AND idempotency_key = ?`, deviceID, key)
+	return err
+}
+
+// PurgeKeysBefore removes keys created before the given time
+func (r *IdempotencyRepository) PurgeKeysBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE created_at < ?`, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *IdempotencyRepository) getKey(deviceID, key string) (*domainIdempotency.Record, error) {
+	record := &domainIdempotency.Record{}
+	var response sql.NullString
+	err := r.db.QueryRow(`
+		SELECT device_id, idempotency_key, path, completed, status_code, response, created_at
+		FROM idempotency_keys WHERE device_id = ? AND idempotency_key = ?
+	`, deviceID, key).Scan(&record.DeviceID, &record.Key, &record.Path, &record.Completed, &record.StatusCode, &response, &record.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	record.Response = []byte(response.String)
+	return record, nil
+}
@@ -0,0 +1,222 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// StoreReaction records the current reaction of a reactor on a message, an empty emoji removes it
+func (r *SQLiteRepository) StoreReaction(reaction *domainChatStorage.MessageReaction) error {
+	if reaction == nil || reaction.MessageID == "" || reaction.ReactorJID == "" {
+		return nil
+	}
+
+	if reaction.Emoji == "" {
+		_, err := r.db.Exec(`
+			DELETE FROM message_reactions WHERE device_id = ? AND message_id = ? AND reactor_jid = ?
+		`, reaction.DeviceID, reaction.MessageID, reaction.ReactorJID)
+		return err
+	}
+
+	if reaction.ReactedAt.IsZero() {
+		reaction.ReactedAt = time.Now()
+	}
+	reaction.ReactedAt = reaction.ReactedAt.UTC()
+
+	_, err := r.db.Exec(`
+		INSERT INTO message_reactions (device_id, message_id, chat_jid, reactor_jid, emoji, reacted_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, message_id, reactor_jid) DO UPDATE SET
+			chat_jid = excluded.chat_jid,
+			emoji = excluded.emoji,
+			reacted_at = excluded.reacted_at
+	`, reaction.DeviceID, reaction.MessageID, reaction.ChatJID, reaction.ReactorJID, reaction.Emoji, reaction.ReactedAt)
+	return err
+}
+
+// StoreMessageEdit appends a revision to a stored message and updates its content. The first edit also
+// saves the original content as revision 0. Edits of messages that were never stored are ignored.
+func (r *SQLiteRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	if edit == nil || edit.MessageID == "" {
+		return nil
+	}
+	if edit.EditedAt.IsZero() {
+		edit.EditedAt = time.Now()
+	}
+	edit.EditedAt = edit.EditedAt.UTC()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var content sql.NullString
+	var sentAt time.Time
+	err = tx.QueryRow(`
+		SELECT content, timestamp FROM messages WHERE id = ? AND chat_jid = ? AND device_id = ?
+	`, edit.MessageID, edit.ChatJID, edit.DeviceID).Scan(&content, &sentAt)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get edited message: %w", err)
+	}
+	current, err := r.openText(content.String)
+	if err != nil {
+		return fmt.Errorf("failed to read edited message: %w", err)
+	}
+	if current == edit.Content {
+		// Already applied, e.g. recorded by the API before the edit came back from another device
+		return nil
+	}
+
+	var latest int
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX(revision), -1) FROM message_edits WHERE device_id = ? AND chat_jid = ? AND message_id = ?
+	`, edit.DeviceID, edit.ChatJID, edit.MessageID).Scan(&latest)
+	if err != nil {
+		return fmt.Errorf("failed to get latest revision: %w", err)
+	}
+
+	insert := `
+		INSERT INTO message_edits (device_id, chat_jid, message_id, revision, content, edited_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	if latest < 0 {
+		if _, err := tx.Exec(insert, edit.DeviceID, edit.ChatJID, edit.MessageID, 0, content.String, sentAt.UTC()); err != nil {
+			return fmt.Errorf("failed to store original content: %w", err)
+		}
+		latest = 0
+	}
+
+	// The original content is copied as stored, sealed or not
+	sealedContent, err := r.sealText(edit.Content)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt message edit: %w", err)
+	}
+
+	edit.Revision = latest + 1
+	if _, err := tx.Exec(insert, edit.DeviceID, edit.ChatJID, edit.MessageID, edit.Revision, sealedContent, edit.EditedAt); err != nil {
+		return fmt.Errorf("failed to store message edit: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE messages SET content = ?, edited_at = ?, updated_at = ?
+		WHERE id = ? AND chat_jid = ? AND device_id = ?
+	`, sealedContent, edit.EditedAt, time.Now().UTC(), edit.MessageID, edit.ChatJID, edit.DeviceID)
+	if err != nil {
+		return fmt.Errorf("failed to update edited message: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// MarkMessageRevoked flags a stored message as deleted for everyone, keeping its content
+func (r *SQLiteRepository) MarkMessageRevoked(deviceID, chatJID, messageID, revokedBy string, revokedAt time.Time) error {
+	if revokedAt.IsZero() {
+		revokedAt = time.Now()
+	}
+
+	_, err := r.db.Exec(`
+		UPDATE messages SET revoked_at = ?, revoked_by = ?, updated_at = ?
+		WHERE id = ? AND chat_jid = ? AND device_id = ? AND revoked_at IS NULL
+	`, revokedAt.UTC(), revokedBy, time.Now().UTC(), messageID, chatJID, deviceID)
+	return err
+}
+
+// GetMessageReactions returns the reactions of the given messages keyed by message ID, oldest first
+func (r *SQLiteRepository) GetMessageReactions(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageReaction, error) {
+	reactions := make(map[string][]*domainChatStorage.MessageReaction)
+	err := r.queryByMessageIDs(`
+		SELECT device_id, message_id, chat_jid, reactor_jid, emoji, reacted_at
+		FROM message_reactions`, deviceID, messageIDs, "reacted_at ASC", func(rows *sql.Rows) error {
+		reaction := &domainChatStorage.MessageReaction{}
+		err := rows.Scan(&reaction.DeviceID, &reaction.MessageID, &reaction.ChatJID, &reaction.ReactorJID,
+			&reaction.Emoji, &reaction.ReactedAt)
+		if err != nil {
+			return err
+		}
+		reactions[reaction.MessageID] = append(reactions[reaction.MessageID], reaction)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
+
+// GetMessageEdits returns the revision history of the given messages keyed by message ID, by revision
+func (r *SQLiteRepository) GetMessageEdits(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageEdit, error) {
+	edits := make(map[string][]*domainChatStorage.MessageEdit)
+	err := r.queryByMessageIDs(`
+		SELECT device_id, chat_jid, message_id, revision, content, edited_at
+		FROM message_edits`, deviceID, messageIDs, "revision ASC", func(rows *sql.Rows) error {
+		edit := &domainChatStorage.MessageEdit{}
+		err := rows.Scan(&edit.DeviceID, &edit.ChatJID, &edit.MessageID, &edit.Revision, &edit.Content, &edit.EditedAt)
+		if err != nil {
+			return err
+		}
+		if edit.Content, err = r.openText(edit.Content); err != nil {
+			return err
+		}
+		edits[edit.MessageID] = append(edits[edit.MessageID], edit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// storeMessageHistory records reactions, edits and revocations carried by an incoming message event.
+// It reports whether the event was one of them, in which case it is not stored as a message.
+func (r *SQLiteRepository) storeMessageHistory(evt *events.Message, deviceID, chatJID, sender string) (bool, error) {
+	if reactionMessage := evt.Message.GetReactionMessage(); reactionMessage != nil {
+		err := r.StoreReaction(&domainChatStorage.MessageReaction{
+			DeviceID:   deviceID,
+			MessageID:  reactionMessage.GetKey().GetID(),
+			ChatJID:    chatJID,
+			ReactorJID: sender,
+			Emoji:      reactionMessage.GetText(),
+			ReactedAt:  evt.Info.Timestamp,
+		})
+		if err != nil {
+			return true, fmt.Errorf("failed to store reaction: %w", err)
+		}
+		return true, nil
+	}
+
+	protocolMessage := evt.Message.GetProtocolMessage()
+	if protocolMessage == nil {
+		return false, nil
+	}
+
+	messageID := protocolMessage.GetKey().GetID()
+	switch protocolMessage.GetType() {
+	case waE2E.ProtocolMessage_REVOKE:
+		if err := r.MarkMessageRevoked(deviceID, chatJID, messageID, sender, evt.Info.Timestamp); err != nil {
+			return true, fmt.Errorf("failed to mark message revoked: %w", err)
+		}
+		return true, nil
+	case waE2E.ProtocolMessage_MESSAGE_EDIT:
+		err := r.StoreMessageEdit(&domainChatStorage.MessageEdit{
+			DeviceID:  deviceID,
+			MessageID: messageID,
+			ChatJID:   chatJID,
+			Content:   utils.ExtractMessageTextFromProto(protocolMessage.GetEditedMessage()),
+			EditedAt:  evt.Info.Timestamp,
+		})
+		if err != nil {
+			return true, fmt.Errorf("failed to store message edit: %w", err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
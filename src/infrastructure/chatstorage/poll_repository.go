@@ -0,0 +1,176 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+// StorePoll creates or updates a poll definition, keeping a known encryption key when the update has none
+func (r *SQLiteRepository) StorePoll(poll *domainChatStorage.Poll) error {
+	if poll == nil || poll.MessageID == "" {
+		return fmt.Errorf("poll with message id is required")
+	}
+
+	options, err := json.Marshal(poll.Options)
+	if err != nil {
+		return fmt.Errorf("failed to encode poll options: %w", err)
+	}
+	var encKey any
+	if len(poll.EncKey) > 0 {
+		encKey = poll.EncKey
+	}
+	if poll.CreatedAt.IsZero() {
+		poll.CreatedAt = time.Now()
+	}
+	poll.CreatedAt = poll.CreatedAt.UTC()
+
+	_, err = r.db.Exec(`
+		INSERT INTO polls (device_id, message_id, chat_jid, question, options, selectable_count, enc_key, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, message_id) DO UPDATE SET
+			chat_jid = CASE WHEN excluded.chat_jid = '' THEN polls.chat_jid ELSE excluded.chat_jid END,
+			question = excluded.question,
+			options = excluded.options,
+			selectable_count = excluded.selectable_count,
+			enc_key = COALESCE(excluded.enc_key, polls.enc_key)
+	`, poll.DeviceID, poll.MessageID, poll.ChatJID, poll.Question, string(options), poll.SelectableCount, encKey, poll.CreatedAt)
+	return err
+}
+
+// GetPoll returns a poll by message ID, or nil when it does not exist. Polls imported from the legacy
+// poll store have no device and are visible to every device.
+func (r *SQLiteRepository) GetPoll(deviceID, messageID string) (*domainChatStorage.Poll, error) {
+	query := `
+		SELECT device_id, message_id, chat_jid, question, options, selectable_count, enc_key, created_at
+		FROM polls WHERE message_id = ?`
+	args := []any{messageID}
+	if deviceID != "" {
+		query += " AND device_id IN (?, '')"
+		args = append(args, deviceID)
+	}
+	query += " ORDER BY device_id DESC LIMIT 1"
+
+	poll := &domainChatStorage.Poll{}
+	var options string
+	err := r.db.QueryRow(query, args...).Scan(&poll.DeviceID, &poll.MessageID, &poll.ChatJID, &poll.Question,
+		&options, &poll.SelectableCount, &poll.EncKey, &poll.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(options), &poll.Options); err != nil {
+		return nil, fmt.Errorf("failed to decode options of poll %s: %w", poll.MessageID, err)
+	}
+	return poll, nil
+}
+
+// StorePollVote records the vote of a voter. Votes arriving out of order do not replace a later one.
+func (r *SQLiteRepository) StorePollVote(vote *domainChatStorage.PollVote) error {
+	if vote == nil || vote.MessageID == "" || vote.VoterJID == "" {
+		return nil
+	}
+
+	selected := vote.SelectedOptions
+	if selected == nil {
+		selected = []string{}
+	}
+	options, err := json.Marshal(selected)
+	if err != nil {
+		return fmt.Errorf("failed to encode poll vote: %w", err)
+	}
+	if vote.VotedAt.IsZero() {
+		vote.VotedAt = time.Now()
+	}
+	vote.VotedAt = vote.VotedAt.UTC()
+
+	_, err = r.db.Exec(`
+		INSERT INTO poll_votes (device_id, message_id, voter_jid, selected_options, voted_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, message_id, voter_jid) DO UPDATE SET
+			selected_options = excluded.selected_options,
+			voted_at = excluded.voted_at
+		WHERE excluded.voted_at >= poll_votes.voted_at
+	`, vote.DeviceID, vote.MessageID, vote.VoterJID, string(options), vote.VotedAt)
+	return err
+}
+
+// GetPollVotes returns the latest vote of every voter on a poll, oldest first
+func (r *SQLiteRepository) GetPollVotes(deviceID, messageID string) ([]*domainChatStorage.PollVote, error) {
+	query := `
+		SELECT device_id, message_id, voter_jid, selected_options, voted_at
+		FROM poll_votes WHERE message_id = ?`
+	args := []any{messageID}
+	if deviceID != "" {
+		query += " AND device_id = ?"
+		args = append(args, deviceID)
+	}
+	query += " ORDER BY voted_at ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []*domainChatStorage.PollVote
+	for rows.Next() {
+		vote := &domainChatStorage.PollVote{}
+		var selected string
+		if err := rows.Scan(&vote.DeviceID, &vote.MessageID, &vote.VoterJID, &selected, &vote.VotedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(selected), &vote.SelectedOptions); err != nil {
+			return nil, fmt.Errorf("failed to decode poll vote of %s: %w", vote.VoterJID, err)
+		}
+		votes = append(votes, vote)
+	}
+	return votes, rows.Err()
+}
+
+// ImportLegacyPolls moves the polls of the former JSON poll store into the chat storage and renames
+// the file so the import runs once. A missing file is not an error.
+func ImportLegacyPolls(repo domainChatStorage.IChatStorageRepository, filePath string) error {
+	file, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read poll store file: %w", err)
+	}
+
+	var legacy map[string]struct {
+		Question string   `json:"question"`
+		Options  []string `json:"options"`
+		EncKey   []byte   `json:"enc_key"`
+	}
+	if err := json.Unmarshal(file, &legacy); err != nil {
+		return fmt.Errorf("failed to decode poll store file: %w", err)
+	}
+
+	for messageID, data := range legacy {
+		err := repo.StorePoll(&domainChatStorage.Poll{
+			MessageID: messageID,
+			Question:  data.Question,
+			Options:   data.Options,
+			EncKey:    data.EncKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to import poll %s: %w", messageID, err)
+		}
+	}
+
+	if err := os.Rename(filePath, filePath+".imported"); err != nil {
+		return fmt.Errorf("failed to rename poll store file: %w", err)
+	}
+	logrus.Infof("Imported %d polls from %s into chat storage", len(legacy), filePath)
+	return nil
+}
@@ -0,0 +1,154 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/XSAM/otelsql"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/lib/pq"
+)
+
+// PostgresRepository implements the chat storage on PostgreSQL. The queries are shared with
+// SQLiteRepository; only the schema types differ, so it reuses the same migration versions.
+type PostgresRepository struct {
+	*SQLiteRepository
+}
+
+// NewPostgresStorageRepository creates a chat storage repository on a database opened with OpenPostgres
+func NewPostgresStorageRepository(db *sql.DB) domainChatStorage.IChatStorageRepository {
+	return &PostgresRepository{SQLiteRepository: &SQLiteRepository{db: db, keyring: encryption.Active()}}
+}
+
+// IsPostgresURI reports whether a storage URI points to PostgreSQL instead of a SQLite file
+func IsPostgresURI(uri string) bool {
+	return strings.HasPrefix(uri, "postgres:") || strings.HasPrefix(uri, "postgresql:")
+}
+
+// OpenPostgres opens a PostgreSQL database that accepts the ? placeholders used by the repositories
+func OpenPostgres(uri string) (*sql.DB, error) {
+	connector, err := pq.NewConnector(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid postgres uri: %w", err)
+	}
+	return otelsql.OpenDB(&rebindConnector{base: connector}, tracing.SQLOptions("postgresql")...), nil
+}
+
+// InitializeSchema creates or migrates the database schema
+func (r *PostgresRepository) InitializeSchema() error {
+	version, err := r.getSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	migrations := r.getMigrations()
+	for i := version; i < len(migrations); i++ {
+		if err := r.runMigration(postgresMigration(migrations[i]), i+1); err != nil {
+			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+var (
+	sqlTimestampType = regexp.MustCompile(`\bTIMESTAMP\b`)
+	sqlIntegerType   = regexp.MustCompile(`\bINTEGER\b`)
+	sqlBlobType      = regexp.MustCompile(`\bBLOB\b`)
+)
+
+// postgresMigration adapts a shared migration to PostgreSQL types. Timestamps keep their time zone,
+// integers are widened because file sizes can exceed 2 GiB and BLOB becomes BYTEA.
+func postgresMigration(migration string) string {
+	migration = sqlTimestampType.ReplaceAllString(migration, "TIMESTAMPTZ")
+	migration = sqlIntegerType.ReplaceAllString(migration, "BIGINT")
+	return sqlBlobType.ReplaceAllString(migration, "BYTEA")
+}
+
+// rebindQuery turns ? placeholders into PostgreSQL's numbered $n form, leaving quoted literals untouched
+func rebindQuery(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(query) + 16)
+	position := 0
+	inLiteral := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'':
+			inLiteral = !inLiteral
+			builder.WriteByte(ch)
+		case ch == '?' && !inLiteral:
+			position++
+			builder.WriteByte('$')
+			builder.WriteString(strconv.Itoa(position))
+		default:
+			builder.WriteByte(ch)
+		}
+	}
+	return builder.String()
+}
+
+// pqConn is the set of driver interfaces implemented by lib/pq connections
+type pqConn interface {
+	driver.Conn
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+type rebindConnector struct {
+	base driver.Connector
+}
+
+func (c *rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pqc, ok := conn.(pqConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected postgres driver connection %T", conn)
+	}
+	return &rebindConn{pqConn: pqc}, nil
+}
+
+func (c *rebindConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// rebindConn rewrites placeholders of every statement before handing it to lib/pq
+type rebindConn struct {
+	pqConn
+}
+
+func (c *rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.pqConn.Prepare(rebindQuery(query))
+}
+
+func (c *rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.pqConn.PrepareContext(ctx, rebindQuery(query))
+}
+
+func (c *rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.pqConn.ExecContext(ctx, rebindQuery(query), args)
+}
+
+func (c *rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.pqConn.QueryContext(ctx, rebindQuery(query), args)
+}
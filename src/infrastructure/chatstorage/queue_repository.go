@@ -0,0 +1,145 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	"github.com/google/uuid"
+)
+
+// QueueRepository stores queued send jobs next to the chat storage tables
+type QueueRepository struct {
+	db *sql.DB
+}
+
+// NewQueueRepository creates a send queue repository on top of the chat storage database
+func NewQueueRepository(db *sql.DB) domainQueue.IQueueRepository {
+	return &QueueRepository{db: db}
+}
+
+const queuedMessageColumns = `id, device_id, type, phone, payload, status, message_id,
+	last_error, sent_at, created_at, updated_at`
+
+// EnqueueJob inserts a new queued job
+func (r *QueueRepository) EnqueueJob(job *domainQueue.Job) error {
+	if job == nil || strings.TrimSpace(job.DeviceID) == "" || job.Type == "" {
+		return fmt.Errorf("queued job with device id and type is required")
+	}
+
+	now := time.Now().UTC()
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	job.Status = domainQueue.StatusQueued
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	_, err := r.db.Exec(`
+		INSERT INTO queued_messages (
+			id, device_id, type, phone, payload, status, message_id,
+			last_error, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, job.ID, job.DeviceID, job.Type, job.Phone, string(job.Payload),
+		job.Status, job.MessageID, job.LastError, job.CreatedAt, job.UpdatedAt)
+	return err
+}
+
+// GetJob fetches a queued job owned by the given device, returning nil when it does not exist
+func (r *QueueRepository) GetJob(deviceID, jobID string) (*domainQueue.Job, error) {
+	row := r.db.QueryRow(`SELECT `+queuedMessageColumns+` FROM queued_messages WHERE id = ? AND device_id = ?`,
+		jobID, deviceID)
+	return r.scanJobRow(row)
+}
+
+// NextQueuedJob returns the oldest queued job of a device
+func (r *QueueRepository) NextQueuedJob(deviceID string) (*domainQueue.Job, error) {
+	row := r.db.QueryRow(`
+		SELECT `+queuedMessageColumns+`
+		FROM queued_messages
+		WHERE device_id = ? AND status = ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT 1
+	`, deviceID, domainQueue.StatusQueued)
+	return r.scanJobRow(row)
+}
+
+// ClaimJob moves a queued job to sending
+func (r *QueueRepository) ClaimJob(jobID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE queued_messages SET status = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`, domainQueue.StatusSending, time.Now().UTC(), jobID, domainQueue.StatusQueued)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// RequeueJob moves a job that is being sent back to queued
+func (r *QueueRepository) RequeueJob(jobID string) error {
+	_, err := r.db.Exec(`
+		UPDATE queued_messages SET status = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`, domainQueue.StatusQueued, time.Now().UTC(), jobID, domainQueue.StatusSending)
+	return err
+}
+
+// MarkJobSent records the WhatsApp message id of a delivered job
+func (r *QueueRepository) MarkJobSent(jobID, messageID string, sentAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE queued_messages SET status = ?, message_id = ?, last_error = '', sent_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainQueue.StatusSent, messageID, sentAt.UTC(), time.Now().UTC(), jobID)
+	return err
+}
+
+// MarkJobFailed records why a job could not be sent
+func (r *QueueRepository) MarkJobFailed(jobID, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE queued_messages SET status = ?, last_error = ?, updated_at = ?
+		WHERE id = ?
+	`, domainQueue.StatusFailed, lastError, time.Now().UTC(), jobID)
+	return err
+}
+
+// FailInterruptedJobs fails every job left in sending by an unclean shutdown
+func (r *QueueRepository) FailInterruptedJobs() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE queued_messages SET status = ?, last_error = ?, updated_at = ?
+		WHERE status = ?
+	`, domainQueue.StatusFailed, "interrupted while sending; send it again to retry", time.Now().UTC(),
+		domainQueue.StatusSending)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// scanJobRow is a private helper for scanning a single queued job, returning nil when there is no row
+func (r *QueueRepository) scanJobRow(row *sql.Row) (*domainQueue.Job, error) {
+	job := &domainQueue.Job{}
+	var payload string
+	var sentAt sql.NullTime
+	err := row.Scan(
+		&job.ID, &job.DeviceID, &job.Type, &job.Phone, &payload,
+		&job.Status, &job.MessageID, &job.LastError,
+		&sentAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.Payload = []byte(payload)
+	if sentAt.Valid {
+		job.SentAt = &sentAt.Time
+	}
+	return job, nil
+}
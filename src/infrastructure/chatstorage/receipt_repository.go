@@ -0,0 +1,141 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// messageLookupBatchSize keeps IN clauses well below the bound parameter limit
+const messageLookupBatchSize = 500
+
+// StoreReceipts upserts message receipts, keeping the first time each state was reached
+func (r *SQLiteRepository) StoreReceipts(receipts []*domainChatStorage.MessageReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO message_receipts (device_id, message_id, chat_jid, recipient_jid, delivered_at, read_at, played_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, message_id, recipient_jid) DO UPDATE SET
+			delivered_at = COALESCE(message_receipts.delivered_at, excluded.delivered_at),
+			read_at = COALESCE(message_receipts.read_at, excluded.read_at),
+			played_at = COALESCE(message_receipts.played_at, excluded.played_at),
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for _, receipt := range receipts {
+		if receipt == nil || receipt.MessageID == "" || receipt.RecipientJID == "" {
+			continue
+		}
+		receipt.UpdatedAt = now
+		_, err := stmt.Exec(receipt.DeviceID, receipt.MessageID, receipt.ChatJID, receipt.RecipientJID,
+			utcTimePtr(receipt.DeliveredAt), utcTimePtr(receipt.ReadAt), utcTimePtr(receipt.PlayedAt), receipt.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store receipt for message %s: %w", receipt.MessageID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMessageReceipts returns the receipts of the given messages keyed by message ID, ordered by recipient
+func (r *SQLiteRepository) GetMessageReceipts(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageReceipt, error) {
+	receipts := make(map[string][]*domainChatStorage.MessageReceipt)
+	err := r.queryByMessageIDs(`
+		SELECT device_id, message_id, chat_jid, recipient_jid, delivered_at, read_at, played_at, updated_at
+		FROM message_receipts`, deviceID, messageIDs, "recipient_jid ASC", func(rows *sql.Rows) error {
+		receipt, err := scanReceipt(rows)
+		if err != nil {
+			return err
+		}
+		receipts[receipt.MessageID] = append(receipts[receipt.MessageID], receipt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// queryByMessageIDs runs a per-message lookup in batches of message IDs, optionally scoped to a device,
+// and hands every row to scan
+func (r *SQLiteRepository) queryByMessageIDs(query, deviceID string, messageIDs []string, orderBy string, scan func(*sql.Rows) error) error {
+	for start := 0; start < len(messageIDs); start += messageLookupBatchSize {
+		batch := messageIDs[start:min(start+messageLookupBatchSize, len(messageIDs))]
+
+		placeholders := make([]string, len(batch))
+		args := make([]any, 0, len(batch)+1)
+		for i, id := range batch {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		batchQuery := query + `
+			WHERE message_id IN (` + strings.Join(placeholders, ", ") + `)`
+		if deviceID != "" {
+			batchQuery += " AND device_id = ?"
+			args = append(args, deviceID)
+		}
+		batchQuery += " ORDER BY " + orderBy
+
+		rows, err := r.db.Query(batchQuery, args...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanReceipt is a private helper for scanning message receipt rows
+func scanReceipt(rows *sql.Rows) (*domainChatStorage.MessageReceipt, error) {
+	receipt := &domainChatStorage.MessageReceipt{}
+	var deliveredAt, readAt, playedAt sql.NullTime
+	err := rows.Scan(&receipt.DeviceID, &receipt.MessageID, &receipt.ChatJID, &receipt.RecipientJID,
+		&deliveredAt, &readAt, &playedAt, &receipt.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveredAt.Valid {
+		receipt.DeliveredAt = &deliveredAt.Time
+	}
+	if readAt.Valid {
+		receipt.ReadAt = &readAt.Time
+	}
+	if playedAt.Valid {
+		receipt.PlayedAt = &playedAt.Time
+	}
+	return receipt, nil
+}
+
+func utcTimePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
@@ -0,0 +1,152 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainRetention "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/retention"
+	"github.com/google/uuid"
+)
+
+// RetentionRepository stores retention policies next to the chat storage tables
+type RetentionRepository struct {
+	db *sql.DB
+}
+
+// NewRetentionRepository creates a retention policy repository on top of the chat storage database
+func NewRetentionRepository(db *sql.DB) domainRetention.IRetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// SavePolicy creates or updates a policy, its scope follows from the device and chat it targets
+func (r *RetentionRepository) SavePolicy(policy *domainRetention.Policy) error {
+	if policy == nil {
+		return fmt.Errorf("policy is required")
+	}
+
+	now := time.Now().UTC()
+	if policy.ID == "" {
+		policy.ID = uuid.NewString()
+	}
+	if policy.CreatedAt.IsZero() {
+		policy.CreatedAt = now
+	}
+	policy.UpdatedAt = now
+	policy.Scope = policyScope(policy.DeviceID, policy.ChatJID)
+
+	// Try update first, then insert if no rows affected (cross-db compatible)
+	result, err := r.db.Exec(`
+		UPDATE retention_policies SET message_days = ?, media_days = ?, updated_at = ?
+		WHERE id = ?
+	`, nullableDays(policy.MessageDays), nullableDays(policy.MediaDays), policy.UpdatedAt, policy.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO retention_policies (id, device_id, chat_jid, message_days, media_days, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, policy.ID, policy.DeviceID, policy.ChatJID, nullableDays(policy.MessageDays), nullableDays(policy.MediaDays),
+			policy.CreatedAt, policy.UpdatedAt)
+	}
+	return err
+}
+
+// GetPolicy fetches a policy by id, returning nil when it does not exist
+func (r *RetentionRepository) GetPolicy(policyID string) (*domainRetention.Policy, error) {
+	row := r.db.QueryRow(`
+		SELECT id, device_id, chat_jid, message_days, media_days, created_at, updated_at
+		FROM retention_policies WHERE id = ?
+	`, policyID)
+	return r.scanPolicyRow(row)
+}
+
+// GetPolicyByTarget fetches the policy of a device and chat, returning nil when it does not exist
+func (r *RetentionRepository) GetPolicyByTarget(deviceID, chatJID string) (*domainRetention.Policy, error) {
+	row := r.db.QueryRow(`
+		SELECT id, device_id, chat_jid, message_days, media_days, created_at, updated_at
+		FROM retention_policies WHERE device_id = ? AND chat_jid = ?
+	`, deviceID, chatJID)
+	return r.scanPolicyRow(row)
+}
+
+// ListPolicies returns every policy, broadest scope first
+func (r *RetentionRepository) ListPolicies() ([]*domainRetention.Policy, error) {
+	rows, err := r.db.Query(`
+		SELECT id, device_id, chat_jid, message_days, media_days, created_at, updated_at
+		FROM retention_policies ORDER BY chat_jid ASC, device_id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*domainRetention.Policy
+	for rows.Next() {
+		policy, err := r.scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+// DeletePolicy removes a policy
+func (r *RetentionRepository) DeletePolicy(policyID string) error {
+	_, err := r.db.Exec("DELETE FROM retention_policies WHERE id = ?", policyID)
+	return err
+}
+
+func (r *RetentionRepository) scanPolicyRow(row *sql.Row) (*domainRetention.Policy, error) {
+	policy, err := r.scanPolicy(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// scanPolicy is a private helper for scanning policy rows
+func (r *RetentionRepository) scanPolicy(scanner interface{ Scan(...any) error }) (*domainRetention.Policy, error) {
+	policy := &domainRetention.Policy{}
+	var messageDays, mediaDays sql.NullInt64
+	err := scanner.Scan(&policy.ID, &policy.DeviceID, &policy.ChatJID, &messageDays, &mediaDays,
+		&policy.CreatedAt, &policy.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if messageDays.Valid {
+		days := int(messageDays.Int64)
+		policy.MessageDays = &days
+	}
+	if mediaDays.Valid {
+		days := int(mediaDays.Int64)
+		policy.MediaDays = &days
+	}
+	policy.Scope = policyScope(policy.DeviceID, policy.ChatJID)
+	return policy, nil
+}
+
+func policyScope(deviceID, chatJID string) domainRetention.Scope {
+	switch {
+	case chatJID != "":
+		return domainRetention.ScopeChat
+	case deviceID != "":
+		return domainRetention.ScopeDevice
+	}
+	return domainRetention.ScopeGlobal
+}
+
+func nullableDays(days *int) any {
+	if days == nil {
+		return nil
+	}
+	return *days
+}
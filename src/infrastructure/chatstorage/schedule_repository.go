@@ -0,0 +1,270 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	"github.com/google/uuid"
+)
+
+// ScheduleRepository stores scheduled messages next to the chat storage tables
+type ScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewScheduleRepository creates a scheduled message repository on top of the chat storage database
+func NewScheduleRepository(db *sql.DB) domainSchedule.IScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+const scheduledMessageColumns = `id, device_id, type, phone, payload, send_at, status, message_id,
+	last_error, sent_at, created_at, updated_at`
+
+// CreateSchedule inserts a new scheduled message
+func (r *ScheduleRepository) CreateSchedule(schedule *domainSchedule.ScheduledMessage) error {
+	if schedule == nil || strings.TrimSpace(schedule.DeviceID) == "" || schedule.Type == "" {
+		return fmt.Errorf("scheduled message with device id and type is required")
+	}
+
+	now := time.Now().UTC()
+	if schedule.ID == "" {
+		schedule.ID = uuid.NewString()
+	}
+	if schedule.Status == "" {
+		schedule.Status = domainSchedule.StatusScheduled
+	}
+	schedule.SendAt = schedule.SendAt.UTC()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	_, err := r.db.Exec(`
+		INSERT INTO scheduled_messages (
+			id, device_id, type, phone, payload, send_at, status, message_id,
+			last_error, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, schedule.ID, schedule.DeviceID, schedule.Type, schedule.Phone, string(schedule.Payload),
+		schedule.SendAt, schedule.Status, schedule.MessageID, schedule.LastError,
+		schedule.CreatedAt, schedule.UpdatedAt)
+	return err
+}
+
+// GetSchedule fetches a scheduled message owned by the given device, returning nil when it does not exist
+func (r *ScheduleRepository) GetSchedule(deviceID, scheduleID string) (*domainSchedule.ScheduledMessage, error) {
+	row := r.db.QueryRow(`SELECT `+scheduledMessageColumns+` FROM scheduled_messages WHERE id = ? AND device_id = ?`,
+		scheduleID, deviceID)
+	schedule, err := r.scanSchedule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ListSchedules returns scheduled messages matching the filter ordered by send time, together with the total count
+func (r *ScheduleRepository) ListSchedules(filter *domainSchedule.Filter) ([]*domainSchedule.ScheduledMessage, int64, error) {
+	if filter == nil {
+		filter = &domainSchedule.Filter{}
+	}
+
+	var conditions []string
+	var args []any
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM scheduled_messages"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + scheduledMessageColumns + ` FROM scheduled_messages` + where + ` ORDER BY send_at ASC`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	schedules, err := r.scanSchedules(rows)
+	return schedules, total, err
+}
+
+// FetchDueSchedules returns scheduled messages of a device whose send time has passed, oldest first
+func (r *ScheduleRepository) FetchDueSchedules(deviceID string, now time.Time, limit int) ([]*domainSchedule.ScheduledMessage, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+scheduledMessageColumns+`
+		FROM scheduled_messages
+		WHERE device_id = ? AND status = ? AND send_at <= ?
+		ORDER BY send_at ASC, created_at ASC
+		LIMIT ?
+	`, deviceID, domainSchedule.StatusScheduled, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanSchedules(rows)
+}
+
+// NextSendAt returns the earliest pending send time of a device
+func (r *ScheduleRepository) NextSendAt(deviceID string) (*time.Time, error) {
+	row := r.db.QueryRow(`
+		SELECT send_at FROM scheduled_messages
+		WHERE device_id = ? AND status = ?
+		ORDER BY send_at ASC
+		LIMIT 1
+	`, deviceID, domainSchedule.StatusScheduled)
+
+	var sendAt time.Time
+	if err := row.Scan(&sendAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sendAt, nil
+}
+
+// ClaimSchedule moves a scheduled message to sending if nobody changed it since it was fetched
+func (r *ScheduleRepository) ClaimSchedule(scheduleID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE scheduled_messages SET status = ?, updated_at = ?
+		WHERE id = ? AND status = ? AND send_at <= ?
+	`, domainSchedule.StatusSending, time.Now().UTC(), scheduleID, domainSchedule.StatusScheduled, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// MarkScheduleSent records the WhatsApp message id of a delivered schedule
+func (r *ScheduleRepository) MarkScheduleSent(scheduleID, messageID string, sentAt time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages SET status = ?, message_id = ?, last_error = '', sent_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainSchedule.StatusSent, messageID, sentAt.UTC(), time.Now().UTC(), scheduleID)
+	return err
+}
+
+// MarkScheduleFailed records why a schedule could not be sent
+func (r *ScheduleRepository) MarkScheduleFailed(scheduleID, lastError string) error {
+	_, err := r.db.Exec(`
+		UPDATE scheduled_messages SET status = ?, last_error = ?, updated_at = ?
+		WHERE id = ?
+	`, domainSchedule.StatusFailed, lastError, time.Now().UTC(), scheduleID)
+	return err
+}
+
+// UpdateScheduleStatus changes the status of a schedule that is currently in one of the from states
+func (r *ScheduleRepository) UpdateScheduleStatus(deviceID, scheduleID string, status domainSchedule.Status, from ...domainSchedule.Status) (bool, error) {
+	query := `UPDATE scheduled_messages SET status = ?, updated_at = ? WHERE id = ? AND device_id = ?`
+	args := []any{status, time.Now().UTC(), scheduleID, deviceID}
+	if len(from) > 0 {
+		placeholders := make([]string, len(from))
+		for i, state := range from {
+			placeholders[i] = "?"
+			args = append(args, state)
+		}
+		query += " AND status IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// RescheduleSchedule moves a pending or failed schedule to a new send time
+func (r *ScheduleRepository) RescheduleSchedule(deviceID, scheduleID string, sendAt time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE scheduled_messages SET status = ?, send_at = ?, last_error = '', updated_at = ?
+		WHERE id = ? AND device_id = ? AND status IN (?, ?)
+	`, domainSchedule.StatusScheduled, sendAt.UTC(), time.Now().UTC(), scheduleID, deviceID,
+		domainSchedule.StatusScheduled, domainSchedule.StatusFailed)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// FailInterruptedSchedules fails every schedule left in sending by an unclean shutdown
+func (r *ScheduleRepository) FailInterruptedSchedules() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE scheduled_messages SET status = ?, last_error = ?, updated_at = ?
+		WHERE status = ?
+	`, domainSchedule.StatusFailed, "interrupted while sending; reschedule to retry", time.Now().UTC(),
+		domainSchedule.StatusSending)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *ScheduleRepository) scanSchedules(rows *sql.Rows) ([]*domainSchedule.ScheduledMessage, error) {
+	var schedules []*domainSchedule.ScheduledMessage
+	for rows.Next() {
+		schedule, err := r.scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// scanSchedule is a private helper for scanning scheduled message rows
+func (r *ScheduleRepository) scanSchedule(scanner interface{ Scan(...any) error }) (*domainSchedule.ScheduledMessage, error) {
+	schedule := &domainSchedule.ScheduledMessage{}
+	var payload string
+	var sentAt sql.NullTime
+	err := scanner.Scan(
+		&schedule.ID, &schedule.DeviceID, &schedule.Type, &schedule.Phone, &payload,
+		&schedule.SendAt, &schedule.Status, &schedule.MessageID, &schedule.LastError,
+		&sentAt, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Payload = []byte(payload)
+	if sentAt.Valid {
+		schedule.SentAt = &sentAt.Time
+	}
+	return schedule, nil
+}
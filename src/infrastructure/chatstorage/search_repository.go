@@ -0,0 +1,297 @@
+package chatstorage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	searchSnippetTokens = 16
+	searchSnippetRunes  = 120
+)
+
+// messagesFTSTriggers keep messages_fts in sync with every insert, update and delete on messages,
+// so StoreMessage, StoreMessagesBatch, DeleteMessage and the bulk cleanups need no extra writes.
+var messagesFTSTriggers = map[string]string{
+	"messages_fts_insert": `CREATE TRIGGER IF NOT EXISTS messages_fts_insert AFTER INSERT ON messages BEGIN
+			INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+		END`,
+	"messages_fts_delete": `CREATE TRIGGER IF NOT EXISTS messages_fts_delete AFTER DELETE ON messages BEGIN
+			INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+		END`,
+	"messages_fts_update": `CREATE TRIGGER IF NOT EXISTS messages_fts_update AFTER UPDATE OF content ON messages BEGIN
+			INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+			INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+		END`,
+}
+
+// initializeSearchIndex sets up the FTS5 index over message content. It is kept outside the versioned
+// migrations because FTS5 is only compiled in with the sqlite_fts5 build tag; without it the
+// triggers are dropped so message writes keep working and searches fall back to LIKE.
+func (r *SQLiteRepository) initializeSearchIndex() (bool, error) {
+	if r.keyring != nil {
+		// An index over sealed content is useless and indexing the plaintext would leak it
+		if err := r.dropSearchTriggers(); err != nil {
+			return false, err
+		}
+		if _, err := r.db.Exec("DROP TABLE IF EXISTS messages_fts"); err != nil && !strings.Contains(err.Error(), "no such module") {
+			return false, fmt.Errorf("failed to drop message search index: %w", err)
+		}
+		logrus.Info("Chat storage encryption is enabled, message search matches decrypted messages without the full-text index")
+		return false, nil
+	}
+
+	_, err := r.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+		content, content='messages', content_rowid='rowid', tokenize='unicode61 remove_diacritics 2'
+	)`)
+	if err != nil {
+		if !strings.Contains(err.Error(), "no such module") {
+			return false, fmt.Errorf("failed to create message search index: %w", err)
+		}
+		if err := r.dropSearchTriggers(); err != nil {
+			return false, err
+		}
+		logrus.Warn("SQLite was built without FTS5, message search falls back to LIKE queries (build with -tags sqlite_fts5)")
+		return false, nil
+	}
+
+	var existing int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ('messages_fts_insert', 'messages_fts_delete', 'messages_fts_update')
+	`).Scan(&existing); err != nil {
+		return false, err
+	}
+	if existing == len(messagesFTSTriggers) {
+		return true, nil
+	}
+
+	// Missing triggers mean the index is new or went stale while running without FTS5
+	logrus.Info("Building full-text search index for stored messages")
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	for name, trigger := range messagesFTSTriggers {
+		if _, err := tx.Exec(trigger); err != nil {
+			return false, fmt.Errorf("failed to create trigger %s: %w", name, err)
+		}
+	}
+	if _, err := tx.Exec(`INSERT INTO messages_fts(messages_fts) VALUES ('rebuild')`); err != nil {
+		return false, fmt.Errorf("failed to build message search index: %w", err)
+	}
+	return true, tx.Commit()
+}
+
+func (r *SQLiteRepository) dropSearchTriggers() error {
+	for name := range messagesFTSTriggers {
+		if _, err := r.db.Exec("DROP TRIGGER IF EXISTS " + name); err != nil {
+			return fmt.Errorf("failed to drop trigger %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// SearchAllMessages searches message content across chats. With the FTS5 index results are ranked by
+// relevance, otherwise every term is matched with LIKE and the newest messages come first.
+func (r *SQLiteRepository) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, int64, error) {
+	terms := strings.Fields(filter.Query)
+	if len(terms) == 0 {
+		return []*domainChatStorage.MessageSearchResult{}, 0, nil
+	}
+
+	var conditions []string
+	var args []any
+	from := "messages m"
+	if r.fullTextSearch {
+		from = "messages_fts JOIN messages m ON m.rowid = messages_fts.rowid"
+		conditions = append(conditions, "messages_fts MATCH ?")
+		args = append(args, ftsMatchQuery(terms))
+	} else if r.keyring == nil {
+		for _, term := range terms {
+			conditions = append(conditions, "LOWER(m.content) LIKE ?")
+			args = append(args, "%"+strings.ToLower(term)+"%")
+		}
+	}
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "m.device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+	if filter.ChatJID != "" {
+		conditions = append(conditions, "m.chat_jid = ?")
+		args = append(args, filter.ChatJID)
+	}
+	if filter.Sender != "" {
+		conditions = append(conditions, "m.sender = ?")
+		args = append(args, filter.Sender)
+	}
+	if filter.MediaType != "" {
+		conditions = append(conditions, "m.media_type = ?")
+		args = append(args, filter.MediaType)
+	}
+	if filter.StartTime != nil {
+		conditions = append(conditions, "m.timestamp >= ?")
+		args = append(args, *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		conditions = append(conditions, "m.timestamp <= ?")
+		args = append(args, *filter.EndTime)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	columns := `m.id, m.chat_jid, m.device_id, m.sender, m.content, m.timestamp, m.is_from_me,
+		m.media_type, m.filename, m.url, m.media_key, m.file_sha256,
+		m.file_enc_sha256, m.file_length, m.edited_at, m.revoked_at, m.revoked_by, m.created_at, m.updated_at`
+	if r.keyring != nil {
+		return r.searchSealedMessages("SELECT "+columns+" FROM "+from+where+" ORDER BY m.timestamp DESC", args, terms, filter)
+	}
+
+	total, err := r.getCount("SELECT COUNT(*) FROM "+from+where, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	var query string
+	var queryArgs []any
+	if r.fullTextSearch {
+		query = "SELECT " + columns + ", snippet(messages_fts, 0, ?, ?, '…', ?) FROM " + from + where +
+			" ORDER BY bm25(messages_fts), m.timestamp DESC"
+		queryArgs = append([]any{domainChatStorage.SearchHighlightStart, domainChatStorage.SearchHighlightEnd, searchSnippetTokens}, args...)
+	} else {
+		query = "SELECT " + columns + " FROM " + from + where + " ORDER BY m.timestamp DESC"
+		queryArgs = args
+	}
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		queryArgs = append(queryArgs, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			queryArgs = append(queryArgs, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*domainChatStorage.MessageSearchResult{}
+	for rows.Next() {
+		result := &domainChatStorage.MessageSearchResult{}
+		var extra []any
+		if r.fullTextSearch {
+			extra = append(extra, &result.Snippet)
+		}
+		message, err := r.scanMessage(rows, extra...)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan message: %w", err)
+		}
+		result.Message = message
+		if !r.fullTextSearch {
+			result.Snippet = highlightSnippet(message.Content, terms)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating messages: %w", err)
+	}
+	return results, total, nil
+}
+
+// searchSealedMessages runs a search over encrypted content: the candidates matching the other filters
+// are decrypted and matched in Go, newest first, before the page is cut out.
+func (r *SQLiteRepository) searchSealedMessages(query string, args []any, terms []string, filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, int64, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []*domainChatStorage.Message{}
+	for rows.Next() {
+		message, err := r.scanMessage(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan message: %w", err)
+		}
+		if matchesAllTerms(message.Content, terms) {
+			matches = append(matches, message)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating messages: %w", err)
+	}
+
+	total := int64(len(matches))
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		start := min(max(filter.Offset, 0), len(matches))
+		matches = matches[start:min(start+filter.Limit, len(matches))]
+	}
+
+	results := make([]*domainChatStorage.MessageSearchResult, len(matches))
+	for i, message := range matches {
+		results[i] = &domainChatStorage.MessageSearchResult{Message: message, Snippet: highlightSnippet(message.Content, terms)}
+	}
+	return results, total, nil
+}
+
+// ftsMatchQuery quotes every term so user input cannot break the FTS5 query syntax, and lets the
+// terms match as word prefixes. Terms are implicitly AND-ed.
+func ftsMatchQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// highlightSnippet marks the terms in content the way the FTS5 snippet function does,
+// trimming long content to a window around the first match.
+func highlightSnippet(content string, terms []string) string {
+	patterns := make([]string, len(terms))
+	for i, term := range terms {
+		patterns[i] = regexp.QuoteMeta(term)
+	}
+	pattern := regexp.MustCompile("(?i)" + strings.Join(patterns, "|"))
+
+	runes := []rune(content)
+	if len(runes) > searchSnippetRunes {
+		start := 0
+		if loc := pattern.FindStringIndex(content); loc != nil {
+			start = len([]rune(content[:loc[0]])) - searchSnippetRunes/4
+		}
+		start = max(0, min(start, len(runes)-searchSnippetRunes))
+		end := start + searchSnippetRunes
+
+		trimmed := string(runes[start:end])
+		if start > 0 {
+			trimmed = "…" + trimmed
+		}
+		if end < len(runes) {
+			trimmed += "…"
+		}
+		content = trimmed
+	}
+
+	return pattern.ReplaceAllStringFunc(content, func(match string) string {
+		return domainChatStorage.SearchHighlightStart + match + domainChatStorage.SearchHighlightEnd
+	})
+}
@@ -0,0 +1,47 @@
+package chatstorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFtsMatchQuery(t *testing.T) {
+	assert.Equal(t, `"invoice"* "march"*`, ftsMatchQuery([]string{"invoice", "march"}))
+	assert.Equal(t, `"say ""hi"""* "OR"*`, ftsMatchQuery([]string{`say "hi"`, "OR"}))
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		terms   []string
+		want    string
+	}{
+		{
+			name:    "should mark every term case-insensitively",
+			content: "Invoice for March is ready",
+			terms:   []string{"invoice", "march"},
+			want:    "<mark>Invoice</mark> for <mark>March</mark> is ready",
+		},
+		{
+			name:    "should treat terms as plain text",
+			content: "total (USD) 1.5",
+			terms:   []string{"(usd)"},
+			want:    "total <mark>(USD)</mark> 1.5",
+		},
+		{
+			name: "should trim long content around the first match",
+			content: "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore " +
+				"et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud invoice exercitation ullamco laboris.",
+			terms: []string{"invoice"},
+			want:  "…incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud <mark>invoice</mark> exercitation ullamco laboris.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, highlightSnippet(tt.content, tt.terms))
+		})
+	}
+}
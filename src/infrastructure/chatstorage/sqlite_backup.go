@@ -0,0 +1,121 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLitePath returns the file behind a SQLite storage URI, or false when the URI points elsewhere
+func SQLitePath(uri string) (string, bool) {
+	if !strings.HasPrefix(uri, "file:") {
+		return "", false
+	}
+	path := strings.TrimPrefix(uri, "file:")
+	if index := strings.Index(path, "?"); index >= 0 {
+		path = path[:index]
+	}
+	return path, path != ""
+}
+
+// BackupSQLite copies a SQLite database into destPath with the SQLite online backup API. The copy is
+// taken in a single step, so it is a consistent snapshot even while other connections keep writing.
+func BackupSQLite(ctx context.Context, uri, destPath string) error {
+	source, err := sql.Open("sqlite3", uri)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	dest, err := sql.Open("sqlite3", "file:"+destPath)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	sourceConn, err := source.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", uri, err)
+	}
+	defer sourceConn.Close()
+
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", destPath, err)
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destDriver any) error {
+		return sourceConn.Raw(func(sourceDriver any) error {
+			destSQLite, ok := destDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected sqlite driver connection %T", destDriver)
+			}
+			sourceSQLite, ok := sourceDriver.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected sqlite driver connection %T", sourceDriver)
+			}
+
+			backup, err := destSQLite.Backup("main", sourceSQLite, "main")
+			if err != nil {
+				return err
+			}
+			// -1 copies every page while holding the read lock, anything smaller could mix two states
+			if _, err := backup.Step(-1); err != nil {
+				_ = backup.Finish()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// CheckSQLite runs the SQLite integrity check on a database file
+func CheckSQLite(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("%s is not a readable SQLite database: %w", path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%s failed the integrity check: %s", path, result)
+	}
+	return nil
+}
+
+// SQLiteDeviceJIDs lists the devices paired in a whatsmeow session database file
+func SQLiteDeviceJIDs(ctx context.Context, path string) ([]string, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "SELECT jid FROM whatsmeow_device ORDER BY jid")
+	if err != nil {
+		// A database that never paired a device has no whatsmeow tables yet
+		if strings.Contains(err.Error(), "no such table") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jids []string
+	for rows.Next() {
+		var jid string
+		if err := rows.Scan(&jid); err != nil {
+			return nil, err
+		}
+		jids = append(jids, jid)
+	}
+	return jids, rows.Err()
+}
@@ -9,6 +9,7 @@ import (
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
@@ -18,11 +19,15 @@ import (
 // SQLiteRepository implements Repository using SQLite
 type SQLiteRepository struct {
 	db *sql.DB
+	// fullTextSearch is set by InitializeSchema when the FTS5 message index is available
+	fullTextSearch bool
+	// keyring seals message content and media keys at rest, nil stores them in plaintext
+	keyring *encryption.Keyring
 }
 
-// NewSQLiteRepository creates a new SQLite repository
+// NewSQLiteRepository creates a new SQLite repository, encrypting at rest when a keyring is active
 func NewStorageRepository(db *sql.DB) domainChatStorage.IChatStorageRepository {
-	return &SQLiteRepository{db: db}
+	return &SQLiteRepository{db: db, keyring: encryption.Active()}
 }
 
 // StoreChat creates or updates a chat
@@ -182,7 +187,7 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 	query := `
 		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, edited_at, revoked_at, revoked_by, created_at, updated_at
 		FROM messages
 		WHERE id = ?
 		LIMIT 1
@@ -207,8 +212,8 @@ func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*do
 	`
 
 	if filter.SearchName != "" {
-		conditions = append(conditions, "c.name LIKE ?")
-		args = append(args, "%"+filter.SearchName+"%")
+		conditions = append(conditions, "LOWER(c.name) LIKE ?")
+		args = append(args, "%"+strings.ToLower(filter.SearchName)+"%")
 	}
 
 	if filter.HasMedia {
@@ -327,16 +332,21 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 		return nil
 	}
 
+	stored, err := r.sealMessage(message)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt message: %w", err)
+	}
+
 	// Try update first, then insert if no rows affected (cross-db compatible)
 	result, err := r.db.Exec(`
 		UPDATE messages SET sender = ?, content = ?, timestamp = ?, is_from_me = ?,
 			media_type = ?, filename = ?, url = ?, media_key = ?, file_sha256 = ?,
 			file_enc_sha256 = ?, file_length = ?, updated_at = ?
 		WHERE id = ? AND chat_jid = ? AND device_id = ?
-	`, message.Sender, message.Content, message.Timestamp, message.IsFromMe,
-		message.MediaType, message.Filename, message.URL, message.MediaKey, message.FileSHA256,
-		message.FileEncSHA256, message.FileLength, message.UpdatedAt,
-		message.ID, message.ChatJID, message.DeviceID)
+	`, stored.Sender, stored.Content, stored.Timestamp, stored.IsFromMe,
+		stored.MediaType, stored.Filename, stored.URL, stored.MediaKey, stored.FileSHA256,
+		stored.FileEncSHA256, stored.FileLength, stored.UpdatedAt,
+		stored.ID, stored.ChatJID, stored.DeviceID)
 	if err != nil {
 		return err
 	}
@@ -349,10 +359,10 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 				media_type, filename, url, media_key, file_sha256,
 				file_enc_sha256, file_length, created_at, updated_at
 			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, message.ID, message.ChatJID, message.DeviceID, message.Sender, message.Content,
-			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
-			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-			message.FileLength, message.CreatedAt, message.UpdatedAt)
+		`, stored.ID, stored.ChatJID, stored.DeviceID, stored.Sender, stored.Content,
+			stored.Timestamp, stored.IsFromMe, stored.MediaType, stored.Filename,
+			stored.URL, stored.MediaKey, stored.FileSHA256, stored.FileEncSHA256,
+			stored.FileLength, stored.CreatedAt, stored.UpdatedAt)
 	}
 	return err
 }
@@ -402,26 +412,31 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		message.CreatedAt = now
 		message.UpdatedAt = now
 
+		stored, err := r.sealMessage(message)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt message %s: %w", message.ID, err)
+		}
+
 		result, err := updateStmt.Exec(
-			message.Sender, message.Content, message.Timestamp, message.IsFromMe,
-			message.MediaType, message.Filename, message.URL, message.MediaKey, message.FileSHA256,
-			message.FileEncSHA256, message.FileLength, message.UpdatedAt,
-			message.ID, message.ChatJID, message.DeviceID,
+			stored.Sender, stored.Content, stored.Timestamp, stored.IsFromMe,
+			stored.MediaType, stored.Filename, stored.URL, stored.MediaKey, stored.FileSHA256,
+			stored.FileEncSHA256, stored.FileLength, stored.UpdatedAt,
+			stored.ID, stored.ChatJID, stored.DeviceID,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to update message %s: %w", message.ID, err)
+			return fmt.Errorf("failed to update message %s: %w", stored.ID, err)
 		}
 
 		rowsAffected, _ := result.RowsAffected()
 		if rowsAffected == 0 {
 			_, err = insertStmt.Exec(
-				message.ID, message.ChatJID, message.DeviceID, message.Sender, message.Content,
-				message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
-				message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-				message.FileLength, message.CreatedAt, message.UpdatedAt,
+				stored.ID, stored.ChatJID, stored.DeviceID, stored.Sender, stored.Content,
+				stored.Timestamp, stored.IsFromMe, stored.MediaType, stored.Filename,
+				stored.URL, stored.MediaKey, stored.FileSHA256, stored.FileEncSHA256,
+				stored.FileLength, stored.CreatedAt, stored.UpdatedAt,
 			)
 			if err != nil {
-				return fmt.Errorf("failed to insert message %s: %w", message.ID, err)
+				return fmt.Errorf("failed to insert message %s: %w", stored.ID, err)
 			}
 		}
 	}
@@ -434,8 +449,15 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	var conditions []string
 	var args []any
 
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, filter.ChatJID)
+	if filter.ChatJID != "" {
+		conditions = append(conditions, "chat_jid = ?")
+		args = append(args, filter.ChatJID)
+	}
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
 
 	if filter.StartTime != nil {
 		conditions = append(conditions, "timestamp >= ?")
@@ -456,14 +478,24 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 		args = append(args, *filter.IsFromMe)
 	}
 
+	if filter.After != nil {
+		conditions = append(conditions, "(timestamp > ? OR (timestamp = ? AND id > ?))")
+		args = append(args, filter.After.Timestamp, filter.After.Timestamp, filter.After.ID)
+	}
+
 	query := `
 		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
-		FROM messages
-		WHERE ` + strings.Join(conditions, " AND ") + `
-		ORDER BY timestamp DESC
-	`
+			file_enc_sha256, file_length, edited_at, revoked_at, revoked_by, created_at, updated_at
+		FROM messages`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if filter.OldestFirst {
+		query += " ORDER BY timestamp ASC, id ASC"
+	} else {
+		query += " ORDER BY timestamp DESC"
+	}
 
 	// Safely add LIMIT and OFFSET using parameterized values
 	if filter.Limit > 0 {
@@ -512,14 +544,18 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 	conditions = append(conditions, "chat_jid = ?")
 	args = append(args, chatJID)
 
-	// Add search condition using LIKE operator for case-insensitive search
-	conditions = append(conditions, "LOWER(content) LIKE ?")
-	args = append(args, "%"+strings.ToLower(searchText)+"%")
+	// Add search condition using LIKE operator for case-insensitive search, sealed content is
+	// matched after decryption instead
+	sealed := r.keyring != nil
+	if !sealed {
+		conditions = append(conditions, "LOWER(content) LIKE ?")
+		args = append(args, "%"+strings.ToLower(searchText)+"%")
+	}
 
 	query := `
 		SELECT id, chat_jid, device_id, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, edited_at, revoked_at, revoked_by, created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -531,8 +567,10 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 		if limit > 1000 {
 			limit = 1000
 		}
-		query += " LIMIT ?"
-		args = append(args, limit)
+		if !sealed {
+			query += " LIMIT ?"
+			args = append(args, limit)
+		}
 	}
 
 	rows, err := r.db.Query(query, args...)
@@ -547,7 +585,13 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
 		}
+		if sealed && !matchesAllTerms(message.Content, []string{searchText}) {
+			continue
+		}
 		messages = append(messages, message)
+		if limit > 0 && len(messages) == limit {
+			break
+		}
 	}
 
 	if err := rows.Err(); err != nil {
@@ -563,10 +607,26 @@ func (r *SQLiteRepository) DeleteMessage(id, chatJID string) error {
 	return err
 }
 
-// DeleteMessageByDevice deletes a specific message for a specific device
+// DeleteMessageByDevice deletes a specific message for a specific device, with its receipts, reactions, edits and poll
 func (r *SQLiteRepository) DeleteMessageByDevice(deviceID, id, chatJID string) error {
-	_, err := r.db.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID)
-	return err
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"message_receipts", "message_reactions", "poll_votes", "polls"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE message_id = ? AND device_id = ?", id, deviceID); err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM message_edits WHERE message_id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
+		return fmt.Errorf("failed to delete from message_edits: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ? AND device_id = ?", id, chatJID, deviceID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // getCount is a private helper for count queries
@@ -576,16 +636,29 @@ func (r *SQLiteRepository) getCount(query string, args ...any) (int64, error) {
 	return count, err
 }
 
-// scanMessage is a private helper for scanning message rows
-func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.Message, error) {
+// scanMessage is a private helper for scanning message rows, extra receives columns selected after the message ones
+func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error }, extra ...any) (*domainChatStorage.Message, error) {
 	message := &domainChatStorage.Message{}
-	err := scanner.Scan(
+	var editedAt, revokedAt sql.NullTime
+	var revokedBy sql.NullString
+	dest := append([]any{
 		&message.ID, &message.ChatJID, &message.DeviceID, &message.Sender, &message.Content,
 		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
 		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.CreatedAt, &message.UpdatedAt,
-	)
-	return message, err
+		&message.FileLength, &editedAt, &revokedAt, &revokedBy, &message.CreatedAt, &message.UpdatedAt,
+	}, extra...)
+	if err := scanner.Scan(dest...); err != nil {
+		return message, err
+	}
+
+	if editedAt.Valid {
+		message.EditedAt = &editedAt.Time
+	}
+	if revokedAt.Valid {
+		message.RevokedAt = &revokedAt.Time
+	}
+	message.RevokedBy = revokedBy.String
+	return message, r.openMessage(message)
 }
 
 // scanChat is a private helper for scanning chat rows
@@ -627,6 +700,22 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 	}
 	defer tx.Rollback()
 
+	if _, err = tx.Exec("DELETE FROM message_receipts"); err != nil {
+		return fmt.Errorf("failed to delete message receipts: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM message_reactions"); err != nil {
+		return fmt.Errorf("failed to delete message reactions: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM message_edits"); err != nil {
+		return fmt.Errorf("failed to delete message edits: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM poll_votes"); err != nil {
+		return fmt.Errorf("failed to delete poll votes: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM polls"); err != nil {
+		return fmt.Errorf("failed to delete polls: %w", err)
+	}
+
 	// Delete messages first (foreign key constraint)
 	_, err = tx.Exec("DELETE FROM messages")
 	if err != nil {
@@ -655,6 +744,22 @@ func (r *SQLiteRepository) DeleteDeviceData(deviceID string) error {
 	}
 	defer tx.Rollback()
 
+	if _, err := tx.Exec(`DELETE FROM message_receipts WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device message receipts: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM message_reactions WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device message reactions: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM message_edits WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device message edits: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM poll_votes WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device poll votes: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM polls WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("failed to delete device polls: %w", err)
+	}
+
 	// Delete messages first via direct device_id filter
 	if _, err := tx.Exec(`DELETE FROM messages WHERE device_id = ?`, deviceID); err != nil {
 		return fmt.Errorf("failed to delete device messages: %w", err)
@@ -749,6 +854,24 @@ func (r *SQLiteRepository) DeleteDeviceRecord(deviceID string) error {
 	if strings.TrimSpace(deviceID) == "" {
 		return fmt.Errorf("device id is required")
 	}
+	if _, err := r.db.Exec("DELETE FROM device_webhooks WHERE device_id = ?", deviceID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("DELETE FROM idempotency_keys WHERE device_id = ?", deviceID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("UPDATE scheduled_messages SET status = 'cancelled', updated_at = ? WHERE device_id = ? AND status = 'scheduled'",
+		time.Now().UTC(), deviceID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("UPDATE campaigns SET status = 'cancelled', updated_at = ? WHERE device_id = ? AND status IN ('running', 'paused')",
+		time.Now().UTC(), deviceID); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec("UPDATE queued_messages SET status = 'failed', last_error = 'device removed', updated_at = ? WHERE device_id = ? AND status = 'queued'",
+		time.Now().UTC(), deviceID); err != nil {
+		return err
+	}
 	_, err := r.db.Exec("DELETE FROM devices WHERE device_id = ?", deviceID)
 	return err
 }
@@ -839,11 +962,6 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 		return nil
 	}
 
-	if evt.Info.IsFromMe {
-		logrus.Debugf("Skipping storage of echo message %s (IsFromMe=true) - already stored by sender", evt.Info.ID)
-		return nil
-	}
-
 	// Get WhatsApp client for LID resolution (device-scoped if present in context)
 	client := whatsapp.ClientFromContext(ctx)
 	deviceID := ""
@@ -865,6 +983,17 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
 	sender := normalizedSender.ToNonAD().String()
 
+	// Reactions, edits and revocations update the history of an earlier message, including the ones
+	// made from another of our own devices
+	if handled, err := r.storeMessageHistory(evt, deviceID, chatJID, sender); handled {
+		return err
+	}
+
+	if evt.Info.IsFromMe {
+		logrus.Debugf("Skipping storage of echo message %s (IsFromMe=true) - already stored by sender", evt.Info.ID)
+		return nil
+	}
+
 	// Get appropriate chat name using pushname if available
 	chatName := r.GetChatNameWithPushName(normalizedChatJID, chatJID, normalizedSender.User, evt.Info.PushName)
 
@@ -1082,7 +1211,8 @@ func (r *SQLiteRepository) InitializeSchema() error {
 		}
 	}
 
-	return nil
+	r.fullTextSearch, err = r.initializeSearchIndex()
+	return err
 }
 
 // getSchemaVersion returns the current schema version
@@ -1199,5 +1329,281 @@ func (r *SQLiteRepository) getMigrations() []string {
 
 		// Migration 13: Add 'archived' column to chats table
 		`ALTER TABLE chats ADD COLUMN archived BOOLEAN DEFAULT FALSE`,
+
+		// Migration 14: Create webhook outbox table
+		`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id VARCHAR(64) PRIMARY KEY,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			event VARCHAR(100) NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			attempts INTEGER DEFAULT 0,
+			last_error TEXT DEFAULT '',
+			next_attempt_at TIMESTAMP NOT NULL,
+			delivered_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 15: Create indexes for webhook outbox
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at)`,
+
+		// Migration 16
+		`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_device ON webhook_deliveries(device_id)`,
+
+		// Migration 17: Create per-device webhook subscriptions table
+		`CREATE TABLE IF NOT EXISTS device_webhooks (
+			id VARCHAR(64) PRIMARY KEY,
+			device_id VARCHAR(255) NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT DEFAULT '',
+			events TEXT DEFAULT '',
+			enabled BOOLEAN DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 18
+		`CREATE INDEX IF NOT EXISTS idx_device_webhooks_device ON device_webhooks(device_id)`,
+
+		// Migration 19: Track which subscription an outbox delivery belongs to
+		`ALTER TABLE webhook_deliveries ADD COLUMN subscription_id VARCHAR(64) DEFAULT ''`,
+
+		// Migration 20: Create scheduled messages table
+		`CREATE TABLE IF NOT EXISTS scheduled_messages (
+			id VARCHAR(64) PRIMARY KEY,
+			device_id VARCHAR(255) NOT NULL,
+			type VARCHAR(20) NOT NULL,
+			phone VARCHAR(255) NOT NULL DEFAULT '',
+			payload TEXT NOT NULL,
+			send_at TIMESTAMP NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+			message_id VARCHAR(255) DEFAULT '',
+			last_error TEXT DEFAULT '',
+			sent_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 21
+		`CREATE INDEX IF NOT EXISTS idx_scheduled_messages_due ON scheduled_messages(device_id, status, send_at)`,
+
+		// Migration 22: Create broadcast campaigns table
+		`CREATE TABLE IF NOT EXISTS campaigns (
+			id VARCHAR(64) PRIMARY KEY,
+			device_id VARCHAR(255) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			type VARCHAR(20) NOT NULL,
+			message TEXT NOT NULL,
+			rate_per_minute INTEGER NOT NULL,
+			jitter_seconds INTEGER NOT NULL DEFAULT 0,
+			daily_cap INTEGER NOT NULL DEFAULT 0,
+			status VARCHAR(20) NOT NULL DEFAULT 'running',
+			completed_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 23
+		`CREATE INDEX IF NOT EXISTS idx_campaigns_device_status ON campaigns(device_id, status, created_at)`,
+
+		// Migration 24: Create campaign recipients table
+		`CREATE TABLE IF NOT EXISTS campaign_recipients (
+			id VARCHAR(64) PRIMARY KEY,
+			campaign_id VARCHAR(64) NOT NULL,
+			device_id VARCHAR(255) NOT NULL,
+			position INTEGER NOT NULL,
+			phone VARCHAR(255) NOT NULL,
+			variables TEXT NOT NULL DEFAULT '{}',
+			status VARCHAR(20) NOT NULL DEFAULT 'queued',
+			message_id VARCHAR(255) DEFAULT '',
+			error TEXT DEFAULT '',
+			sent_at TIMESTAMP NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 25
+		`CREATE INDEX IF NOT EXISTS idx_campaign_recipients_queue ON campaign_recipients(campaign_id, status, position)`,
+
+		// Migration 26
+		`CREATE INDEX IF NOT EXISTS idx_campaign_recipients_sent ON campaign_recipients(device_id, status, sent_at)`,
+
+		// Migration 27: Create message templates table
+		`CREATE TABLE IF NOT EXISTS message_templates (
+			id VARCHAR(64) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			content TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 28
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_message_templates_name ON message_templates(name)`,
+
+		// Migration 29: Create idempotency keys table for send requests
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			device_id VARCHAR(255) NOT NULL,
+			idempotency_key VARCHAR(255) NOT NULL,
+			path TEXT NOT NULL,
+			completed BOOLEAN NOT NULL DEFAULT FALSE,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			response TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (device_id, idempotency_key)
+		)`,
+
+		// Migration 30
+		`CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created ON idempotency_keys(created_at)`,
+
+		// Migration 31: Create queued messages table for sends held while a device is offline
+		`CREATE TABLE IF NOT EXISTS queued_messages (
+			id VARCHAR(64) PRIMARY KEY,
+			device_id VARCHAR(255) NOT NULL,
+			type VARCHAR(20) NOT NULL,
+			phone VARCHAR(255) NOT NULL DEFAULT '',
+			payload TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'queued',
+			message_id VARCHAR(255) DEFAULT '',
+			last_error TEXT DEFAULT '',
+			sent_at TIMESTAMP NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 32
+		`CREATE INDEX IF NOT EXISTS idx_queued_messages_next ON queued_messages(device_id, status, created_at)`,
+
+		// Migration 33: Create message receipts table for per-recipient delivery status
+		`CREATE TABLE IF NOT EXISTS message_receipts (
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			message_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			recipient_jid VARCHAR(255) NOT NULL,
+			delivered_at TIMESTAMP NULL,
+			read_at TIMESTAMP NULL,
+			played_at TIMESTAMP NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (device_id, message_id, recipient_jid)
+		)`,
+
+		// Migration 34
+		`CREATE INDEX IF NOT EXISTS idx_message_receipts_message ON message_receipts(message_id)`,
+
+		// Migration 35: Create message reactions table, one current reaction per reactor
+		`CREATE TABLE IF NOT EXISTS message_reactions (
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			message_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL,
+			reactor_jid VARCHAR(255) NOT NULL,
+			emoji VARCHAR(64) NOT NULL,
+			reacted_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, message_id, reactor_jid)
+		)`,
+
+		// Migration 36
+		`CREATE INDEX IF NOT EXISTS idx_message_reactions_message ON message_reactions(message_id)`,
+
+		// Migration 37: Create message edits table, revision 0 keeps the original content
+		`CREATE TABLE IF NOT EXISTS message_edits (
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			chat_jid VARCHAR(255) NOT NULL,
+			message_id VARCHAR(255) NOT NULL,
+			revision INTEGER NOT NULL,
+			content TEXT NOT NULL,
+			edited_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, chat_jid, message_id, revision)
+		)`,
+
+		// Migration 38: Track message edits and revocations on the message itself
+		`ALTER TABLE messages ADD COLUMN edited_at TIMESTAMP NULL`,
+
+		// Migration 39
+		`ALTER TABLE messages ADD COLUMN revoked_at TIMESTAMP NULL`,
+
+		// Migration 40
+		`ALTER TABLE messages ADD COLUMN revoked_by VARCHAR(255) DEFAULT ''`,
+
+		// Migration 41: Create polls table, options are stored as a JSON array
+		`CREATE TABLE IF NOT EXISTS polls (
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			message_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL DEFAULT '',
+			question TEXT NOT NULL,
+			options TEXT NOT NULL,
+			selectable_count INTEGER NOT NULL DEFAULT 0,
+			enc_key BLOB,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (device_id, message_id)
+		)`,
+
+		// Migration 42: Create poll votes table, keeping the latest vote per voter
+		`CREATE TABLE IF NOT EXISTS poll_votes (
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			message_id VARCHAR(255) NOT NULL,
+			voter_jid VARCHAR(255) NOT NULL,
+			selected_options TEXT NOT NULL,
+			voted_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, message_id, voter_jid)
+		)`,
+
+		// Migration 43: Create retention policies table, an empty device or chat widens the scope
+		`CREATE TABLE IF NOT EXISTS retention_policies (
+			id VARCHAR(64) PRIMARY KEY,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			chat_jid VARCHAR(255) NOT NULL DEFAULT '',
+			message_days INTEGER,
+			media_days INTEGER,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		// Migration 44
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_retention_policies_target ON retention_policies(device_id, chat_jid)`,
+
+		// Migration 45: Create API keys table, devices and permissions are JSON arrays
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id VARCHAR(64) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			prefix VARCHAR(32) NOT NULL,
+			key_hash VARCHAR(64) NOT NULL,
+			devices TEXT NOT NULL,
+			permissions TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			last_used_at TIMESTAMP,
+			revoked_at TIMESTAMP
+		)`,
+
+		// Migration 46
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_hash ON api_keys(key_hash)`,
+
+		// Migration 47: Create audit log table, targets and summary are JSON
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id VARCHAR(64) PRIMARY KEY,
+			actor_type VARCHAR(32) NOT NULL,
+			actor VARCHAR(255) NOT NULL DEFAULT '',
+			api_key_id VARCHAR(64) NOT NULL DEFAULT '',
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			method VARCHAR(16) NOT NULL,
+			route VARCHAR(255) NOT NULL,
+			path TEXT NOT NULL,
+			targets TEXT NOT NULL,
+			summary TEXT NOT NULL,
+			status_code INTEGER NOT NULL,
+			success BOOLEAN NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			remote_ip VARCHAR(64) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+
+		// Migration 48
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at)`,
+
+		// Migration 49
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_device ON audit_log(device_id, created_at)`,
+
+		// Migration 50: Keep the trace context of the event an outbox delivery was produced by
+		`ALTER TABLE webhook_deliveries ADD COLUMN traceparent VARCHAR(64) DEFAULT ''`,
 	}
 }
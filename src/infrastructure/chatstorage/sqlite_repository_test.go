@@ -0,0 +1,72 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	repo := &SQLiteRepository{db: db}
+	require.NoError(t, repo.InitializeSchema())
+	return repo
+}
+
+func TestSQLiteRepository_GetMessagesOldestFirst(t *testing.T) {
+	repo := newTestRepository(t)
+	start := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID: fmt.Sprintf("M%d", i), ChatJID: "628123@s.whatsapp.net", DeviceID: "dev", Sender: "628123@s.whatsapp.net",
+			Content: "hello", Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	after := start.Add(time.Minute)
+	filter := &domainChatStorage.MessageFilter{DeviceID: "dev", StartTime: &after, Limit: 2}
+
+	newest, err := repo.GetMessages(filter)
+	require.NoError(t, err)
+	require.Len(t, newest, 2)
+	assert.Equal(t, []string{"M4", "M3"}, []string{newest[0].ID, newest[1].ID})
+
+	filter.OldestFirst = true
+	oldest, err := repo.GetMessages(filter)
+	require.NoError(t, err)
+	require.Len(t, oldest, 2)
+	assert.Equal(t, []string{"M1", "M2"}, []string{oldest[0].ID, oldest[1].ID})
+}
+
+func TestSQLiteRepository_GetMessagesAfterCursor(t *testing.T) {
+	repo := newTestRepository(t)
+	sentAt := time.Date(2025, 1, 2, 3, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	for _, id := range []string{"c", "a", "b"} {
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID: id, ChatJID: "628123@s.whatsapp.net", DeviceID: "dev", Sender: "628123@s.whatsapp.net",
+			Content: "hello", Timestamp: sentAt,
+		}))
+	}
+
+	filter := &domainChatStorage.MessageFilter{DeviceID: "dev", Limit: 2, OldestFirst: true}
+	first, err := repo.GetMessages(filter)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	assert.Equal(t, []string{"a", "b"}, []string{first[0].ID, first[1].ID})
+
+	filter.After = &domainChatStorage.MessageCursor{Timestamp: first[1].Timestamp, ID: first[1].ID}
+	rest, err := repo.GetMessages(filter)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, "c", rest[0].ID)
+}
@@ -0,0 +1,123 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// TemplateRepository stores message templates next to the chat storage tables
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository creates a message template repository on top of the chat storage database
+func NewTemplateRepository(db *sql.DB) domainTemplate.ITemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// SaveTemplate creates or updates a template
+func (r *TemplateRepository) SaveTemplate(template *domainTemplate.Template) error {
+	if template == nil || strings.TrimSpace(template.Name) == "" {
+		return fmt.Errorf("template with name is required")
+	}
+
+	now := time.Now().UTC()
+	if template.ID == "" {
+		template.ID = uuid.NewString()
+	}
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = now
+	}
+	template.UpdatedAt = now
+	template.Variables = utils.TemplateVariables(template.Content)
+
+	// Try update first, then insert if no rows affected (cross-db compatible)
+	result, err := r.db.Exec(`
+		UPDATE message_templates SET name = ?, content = ?, updated_at = ?
+		WHERE id = ?
+	`, template.Name, template.Content, template.UpdatedAt, template.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO message_templates (id, name, content, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, template.ID, template.Name, template.Content, template.CreatedAt, template.UpdatedAt)
+	}
+	return err
+}
+
+// GetTemplate fetches a template by id, returning nil when it does not exist
+func (r *TemplateRepository) GetTemplate(templateID string) (*domainTemplate.Template, error) {
+	row := r.db.QueryRow(`
+		SELECT id, name, content, created_at, updated_at FROM message_templates WHERE id = ?
+	`, templateID)
+	return r.scanTemplateRow(row)
+}
+
+// GetTemplateByName fetches a template by its unique name, returning nil when it does not exist
+func (r *TemplateRepository) GetTemplateByName(name string) (*domainTemplate.Template, error) {
+	row := r.db.QueryRow(`
+		SELECT id, name, content, created_at, updated_at FROM message_templates WHERE name = ?
+	`, name)
+	return r.scanTemplateRow(row)
+}
+
+// ListTemplates returns every template ordered by name
+func (r *TemplateRepository) ListTemplates() ([]*domainTemplate.Template, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, content, created_at, updated_at FROM message_templates ORDER BY name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*domainTemplate.Template
+	for rows.Next() {
+		template, err := r.scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// DeleteTemplate removes a template
+func (r *TemplateRepository) DeleteTemplate(templateID string) error {
+	_, err := r.db.Exec("DELETE FROM message_templates WHERE id = ?", templateID)
+	return err
+}
+
+func (r *TemplateRepository) scanTemplateRow(row *sql.Row) (*domainTemplate.Template, error) {
+	template, err := r.scanTemplate(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// scanTemplate is a private helper for scanning template rows
+func (r *TemplateRepository) scanTemplate(scanner interface{ Scan(...any) error }) (*domainTemplate.Template, error) {
+	template := &domainTemplate.Template{}
+	err := scanner.Scan(&template.ID, &template.Name, &template.Content, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Variables = utils.TemplateVariables(template.Content)
+	return template, nil
+}
@@ -0,0 +1,390 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/google/uuid"
+)
+
+// WebhookRepository stores the webhook outbox next to the chat storage tables
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a webhook outbox repository on top of the chat storage database
+func NewWebhookRepository(db *sql.DB) domainWebhook.IWebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookDeliveryColumns = `id, device_id, event, url, payload, status, attempts, last_error,
+	next_attempt_at, delivered_at, created_at, updated_at, subscription_id, traceparent`
+
+// EnqueueDelivery inserts a new pending delivery
+func (r *WebhookRepository) EnqueueDelivery(delivery *domainWebhook.Delivery) error {
+	if delivery == nil || strings.TrimSpace(delivery.URL) == "" {
+		return fmt.Errorf("webhook delivery with url is required")
+	}
+
+	now := time.Now().UTC()
+	if delivery.ID == "" {
+		delivery.ID = uuid.NewString()
+	}
+	if delivery.Status == "" {
+		delivery.Status = domainWebhook.DeliveryStatusPending
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	_, err := r.db.Exec(`
+		INSERT INTO webhook_deliveries (
+			id, device_id, event, url, payload, status, attempts, last_error,
+			next_attempt_at, created_at, updated_at, subscription_id, traceparent
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, delivery.ID, delivery.DeviceID, delivery.Event, delivery.URL, string(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.LastError,
+		delivery.NextAttemptAt.UTC(), delivery.CreatedAt, delivery.UpdatedAt, delivery.SubscriptionID, delivery.TraceParent)
+	return err
+}
+
+// FetchDueDeliveries returns pending deliveries whose next attempt is due, oldest first
+func (r *WebhookRepository) FetchDueDeliveries(now time.Time, limit int) ([]*domainWebhook.Delivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, created_at ASC
+		LIMIT ?
+	`, domainWebhook.DeliveryStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanDeliveries(rows)
+}
+
+// ClaimDelivery bumps the attempt counter and pushes next_attempt_at out so other workers skip the row
+func (r *WebhookRepository) ClaimDelivery(id string, attempts int, leaseUntil time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+		WHERE id = ? AND status = ? AND attempts = ?
+	`, leaseUntil.UTC(), time.Now().UTC(), id, domainWebhook.DeliveryStatusPending, attempts)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// MarkDeliverySucceeded flags a delivery as delivered
+func (r *WebhookRepository) MarkDeliverySucceeded(id string) error {
+	now := time.Now().UTC()
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries SET status = ?, last_error = '', delivered_at = ?, updated_at = ?
+		WHERE id = ?
+	`, domainWebhook.DeliveryStatusDelivered, now, now, id)
+	return err
+}
+
+// MarkDeliveryFailed records a failed attempt and either reschedules or dead-letters the delivery
+func (r *WebhookRepository) MarkDeliveryFailed(id string, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := domainWebhook.DeliveryStatusPending
+	if dead {
+		status = domainWebhook.DeliveryStatusDead
+	}
+
+	_, err := r.db.Exec(`
+		UPDATE webhook_deliveries SET status = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+		WHERE id = ?
+	`, status, lastError, nextAttemptAt.UTC(), time.Now().UTC(), id)
+	return err
+}
+
+// GetDelivery fetches a delivery by id, returning nil when it does not exist
+func (r *WebhookRepository) GetDelivery(id string) (*domainWebhook.Delivery, error) {
+	row := r.db.QueryRow(`SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = ?`, id)
+	delivery, err := r.scanDelivery(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ListDeliveries returns deliveries matching the filter, newest first, together with the total count
+func (r *WebhookRepository) ListDeliveries(filter *domainWebhook.DeliveryFilter) ([]*domainWebhook.Delivery, int64, error) {
+	if filter == nil {
+		filter = &domainWebhook.DeliveryFilter{}
+	}
+
+	var conditions []string
+	var args []any
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, filter.Event)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM webhook_deliveries"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries` + where + ` ORDER BY created_at DESC`
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries, err := r.scanDeliveries(rows)
+	return deliveries, total, err
+}
+
+// RedriveDelivery resets a dead-lettered delivery so the dispatcher picks it up again immediately. It returns
+// false when the delivery is not dead, so a delivered or in-flight row is never sent twice.
+func (r *WebhookRepository) RedriveDelivery(id string) (bool, error) {
+	now := time.Now().UTC()
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`, domainWebhook.DeliveryStatusPending, now, now, id, domainWebhook.DeliveryStatusDead)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// RedriveDeadDeliveries resets every dead-lettered delivery, optionally scoped to one device
+func (r *WebhookRepository) RedriveDeadDeliveries(deviceID string) (int64, error) {
+	now := time.Now().UTC()
+	query := `
+		UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, updated_at = ?
+		WHERE status = ?`
+	args := []any{domainWebhook.DeliveryStatusPending, now, now, domainWebhook.DeliveryStatusDead}
+	if deviceID != "" {
+		query += " AND device_id = ?"
+		args = append(args, deviceID)
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeDeliveredBefore removes delivered rows older than the given time
+func (r *WebhookRepository) PurgeDeliveredBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`
+		DELETE FROM webhook_deliveries WHERE status = ? AND delivered_at < ?
+	`, domainWebhook.DeliveryStatusDelivered, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// HasPendingDelivery reports whether a pending row's payload contains text, e.g. the name of a media file it links to
+func (r *WebhookRepository) HasPendingDelivery(text string) (bool, error) {
+	var found int
+	err := r.db.QueryRow(`
+		SELECT 1 FROM webhook_deliveries WHERE status = ? AND payload LIKE ? LIMIT 1
+	`, domainWebhook.DeliveryStatusPending, "%"+text+"%").Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// SaveSubscription upserts a per-device webhook subscription
+func (r *WebhookRepository) SaveSubscription(subscription *domainWebhook.Subscription) error {
+	if subscription == nil || strings.TrimSpace(subscription.DeviceID) == "" || strings.TrimSpace(subscription.URL) == "" {
+		return fmt.Errorf("webhook subscription with device id and url is required")
+	}
+
+	now := time.Now().UTC()
+	if subscription.ID == "" {
+		subscription.ID = uuid.NewString()
+	}
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = now
+	}
+	subscription.UpdatedAt = now
+	events := strings.Join(subscription.Events, ",")
+
+	// Try update first, then insert if no rows affected (cross-db compatible)
+	result, err := r.db.Exec(`
+		UPDATE device_webhooks SET url = ?, secret = ?, events = ?, enabled = ?, updated_at = ?
+		WHERE id = ? AND device_id = ?
+	`, subscription.URL, subscription.Secret, events, subscription.Enabled, subscription.UpdatedAt,
+		subscription.ID, subscription.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		_, err = r.db.Exec(`
+			INSERT INTO device_webhooks (id, device_id, url, secret, events, enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, subscription.ID, subscription.DeviceID, subscription.URL, subscription.Secret, events,
+			subscription.Enabled, subscription.CreatedAt, subscription.UpdatedAt)
+	}
+	return err
+}
+
+// GetSubscription fetches a subscription owned by the given device, returning nil when it does not exist
+func (r *WebhookRepository) GetSubscription(deviceID, subscriptionID string) (*domainWebhook.Subscription, error) {
+	row := r.db.QueryRow(`
+		SELECT id, device_id, url, secret, events, enabled, created_at, updated_at
+		FROM device_webhooks
+		WHERE id = ? AND device_id = ?
+	`, subscriptionID, deviceID)
+	return r.scanSubscriptionRow(row)
+}
+
+// GetSubscriptionByID fetches a subscription regardless of device, returning nil when it does not exist
+func (r *WebhookRepository) GetSubscriptionByID(subscriptionID string) (*domainWebhook.Subscription, error) {
+	row := r.db.QueryRow(`
+		SELECT id, device_id, url, secret, events, enabled, created_at, updated_at
+		FROM device_webhooks
+		WHERE id = ?
+	`, subscriptionID)
+	return r.scanSubscriptionRow(row)
+}
+
+// ListSubscriptions returns every subscription of a device, oldest first
+func (r *WebhookRepository) ListSubscriptions(deviceID string) ([]*domainWebhook.Subscription, error) {
+	rows, err := r.db.Query(`
+		SELECT id, device_id, url, secret, events, enabled, created_at, updated_at
+		FROM device_webhooks
+		WHERE device_id = ?
+		ORDER BY created_at ASC
+	`, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domainWebhook.Subscription
+	for rows.Next() {
+		subscription, err := r.scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// DeleteSubscription removes a subscription owned by the given device
+func (r *WebhookRepository) DeleteSubscription(deviceID, subscriptionID string) error {
+	_, err := r.db.Exec("DELETE FROM device_webhooks WHERE id = ? AND device_id = ?", subscriptionID, deviceID)
+	return err
+}
+
+func (r *WebhookRepository) scanSubscriptionRow(row *sql.Row) (*domainWebhook.Subscription, error) {
+	subscription, err := r.scanSubscription(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// scanSubscription is a private helper for scanning subscription rows
+func (r *WebhookRepository) scanSubscription(scanner interface{ Scan(...any) error }) (*domainWebhook.Subscription, error) {
+	subscription := &domainWebhook.Subscription{}
+	var events string
+	err := scanner.Scan(
+		&subscription.ID, &subscription.DeviceID, &subscription.URL, &subscription.Secret,
+		&events, &subscription.Enabled, &subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Events = []string{}
+	for _, event := range strings.Split(events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			subscription.Events = append(subscription.Events, event)
+		}
+	}
+	return subscription, nil
+}
+
+func (r *WebhookRepository) scanDeliveries(rows *sql.Rows) ([]*domainWebhook.Delivery, error) {
+	var deliveries []*domainWebhook.Delivery
+	for rows.Next() {
+		delivery, err := r.scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// scanDelivery is a private helper for scanning outbox rows
+func (r *WebhookRepository) scanDelivery(scanner interface{ Scan(...any) error }) (*domainWebhook.Delivery, error) {
+	delivery := &domainWebhook.Delivery{}
+	var payload string
+	var deliveredAt sql.NullTime
+	err := scanner.Scan(
+		&delivery.ID, &delivery.DeviceID, &delivery.Event, &delivery.URL, &payload,
+		&delivery.Status, &delivery.Attempts, &delivery.LastError,
+		&delivery.NextAttemptAt, &deliveredAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+		&delivery.SubscriptionID, &delivery.TraceParent,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = []byte(payload)
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return delivery, nil
+}
@@ -0,0 +1,72 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepository_RedriveDeliveryOnlyResetsDeadRows(t *testing.T) {
+	repo := NewWebhookRepository(newTestRepository(t).db)
+
+	deliveries := map[string]*domainWebhook.Delivery{}
+	for _, name := range []string{"dead", "delivered", "pending"} {
+		delivery := &domainWebhook.Delivery{DeviceID: "dev", Event: "message", URL: "https://example.com/" + name, Payload: []byte(`{}`)}
+		require.NoError(t, repo.EnqueueDelivery(delivery))
+		deliveries[name] = delivery
+	}
+	require.NoError(t, repo.MarkDeliveryFailed(deliveries["dead"].ID, "boom", time.Now(), true))
+	require.NoError(t, repo.MarkDeliverySucceeded(deliveries["delivered"].ID))
+
+	for name, want := range map[string]bool{"dead": true, "delivered": false, "pending": false} {
+		redriven, err := repo.RedriveDelivery(deliveries[name].ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, redriven, name)
+	}
+
+	delivered, err := repo.GetDelivery(deliveries["delivered"].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainWebhook.DeliveryStatusDelivered, delivered.Status)
+
+	redriven, err := repo.GetDelivery(deliveries["dead"].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainWebhook.DeliveryStatusPending, redriven.Status)
+	assert.Equal(t, 0, redriven.Attempts)
+
+	missing, err := repo.RedriveDelivery("missing")
+	require.NoError(t, err)
+	assert.False(t, missing)
+}
+
+func TestWebhookRepository_HasPendingDelivery(t *testing.T) {
+	repo := NewWebhookRepository(newTestRepository(t).db)
+
+	pending := &domainWebhook.Delivery{DeviceID: "dev", Event: "message", URL: "https://example.com",
+		Payload: []byte(`{"image":"statics/media/1717243200-pending.jpg"}`)}
+	delivered := &domainWebhook.Delivery{DeviceID: "dev", Event: "message", URL: "https://example.com",
+		Payload: []byte(`{"image":"statics/media/1717243200-delivered.jpg"}`)}
+	require.NoError(t, repo.EnqueueDelivery(pending))
+	require.NoError(t, repo.EnqueueDelivery(delivered))
+	require.NoError(t, repo.MarkDeliverySucceeded(delivered.ID))
+
+	for text, want := range map[string]bool{"1717243200-pending.jpg": true, "1717243200-delivered.jpg": false, "other.jpg": false} {
+		found, err := repo.HasPendingDelivery(text)
+		require.NoError(t, err)
+		assert.Equal(t, want, found, text)
+	}
+}
+
+func TestWebhookRepository_KeepsTraceParent(t *testing.T) {
+	repo := NewWebhookRepository(newTestRepository(t).db)
+
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	delivery := &domainWebhook.Delivery{DeviceID: "dev", Event: "message", URL: "https://example.com", Payload: []byte(`{}`), TraceParent: traceParent}
+	require.NoError(t, repo.EnqueueDelivery(delivery))
+
+	stored, err := repo.GetDelivery(delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, traceParent, stored.TraceParent)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
@@ -98,6 +99,7 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 		log.Errorf("Failed to send auto-reply message: %v", err)
 		return
 	}
+	metrics.MessagesSent.WithLabelValues(InstanceIDFromContext(ctx), "text").Inc()
 
 	// Store the auto-reply message in chat storage if send was successful
 	if chatStorageRepo != nil {
@@ -123,6 +123,13 @@ func (r *deviceChatStorage) SearchMessages(chatJID, searchText string, limit int
 	return r.base.SearchMessages(chatJID, searchText, limit)
 }
 
+func (r *deviceChatStorage) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, int64, error) {
+	if filter != nil && filter.DeviceID == "" {
+		filter.DeviceID = r.deviceID
+	}
+	return r.base.SearchAllMessages(filter)
+}
+
 func (r *deviceChatStorage) DeleteMessage(id, chatJID string) error {
 	return r.base.DeleteMessageByDevice(r.deviceID, id, chatJID)
 }
@@ -135,6 +142,76 @@ func (r *deviceChatStorage) StoreSentMessageWithContext(ctx context.Context, mes
 	return r.base.StoreSentMessageWithContext(ctx, messageID, senderJID, recipientJID, content, timestamp)
 }
 
+func (r *deviceChatStorage) StoreReceipts(receipts []*domainChatStorage.MessageReceipt) error {
+	for _, receipt := range receipts {
+		if receipt != nil && receipt.DeviceID == "" {
+			receipt.DeviceID = r.deviceID
+		}
+	}
+	return r.base.StoreReceipts(receipts)
+}
+
+func (r *deviceChatStorage) GetMessageReceipts(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageReceipt, error) {
+	return r.base.GetMessageReceipts(deviceID, messageIDs)
+}
+
+func (r *deviceChatStorage) StoreReaction(reaction *domainChatStorage.MessageReaction) error {
+	if reaction != nil && reaction.DeviceID == "" {
+		reaction.DeviceID = r.deviceID
+	}
+	return r.base.StoreReaction(reaction)
+}
+
+func (r *deviceChatStorage) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	if edit != nil && edit.DeviceID == "" {
+		edit.DeviceID = r.deviceID
+	}
+	return r.base.StoreMessageEdit(edit)
+}
+
+func (r *deviceChatStorage) MarkMessageRevoked(deviceID, chatJID, messageID, revokedBy string, revokedAt time.Time) error {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.MarkMessageRevoked(deviceID, chatJID, messageID, revokedBy, revokedAt)
+}
+
+func (r *deviceChatStorage) GetMessageReactions(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageReaction, error) {
+	return r.base.GetMessageReactions(deviceID, messageIDs)
+}
+
+func (r *deviceChatStorage) GetMessageEdits(deviceID string, messageIDs []string) (map[string][]*domainChatStorage.MessageEdit, error) {
+	return r.base.GetMessageEdits(deviceID, messageIDs)
+}
+
+func (r *deviceChatStorage) StorePoll(poll *domainChatStorage.Poll) error {
+	if poll != nil && poll.DeviceID == "" {
+		poll.DeviceID = r.deviceID
+	}
+	return r.base.StorePoll(poll)
+}
+
+func (r *deviceChatStorage) GetPoll(deviceID, messageID string) (*domainChatStorage.Poll, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetPoll(deviceID, messageID)
+}
+
+func (r *deviceChatStorage) StorePollVote(vote *domainChatStorage.PollVote) error {
+	if vote != nil && vote.DeviceID == "" {
+		vote.DeviceID = r.deviceID
+	}
+	return r.base.StorePollVote(vote)
+}
+
+func (r *deviceChatStorage) GetPollVotes(deviceID, messageID string) ([]*domainChatStorage.PollVote, error) {
+	if deviceID == "" {
+		deviceID = r.deviceID
+	}
+	return r.base.GetPollVotes(deviceID, messageID)
+}
+
 func (r *deviceChatStorage) GetChatMessageCount(chatJID string) (int64, error) {
 	return r.base.GetChatMessageCountByDevice(r.deviceID, chatJID)
 }
@@ -175,6 +252,10 @@ func (r *deviceChatStorage) InitializeSchema() error {
 	return r.base.InitializeSchema()
 }
 
+func (r *deviceChatStorage) RewrapEncryptedData() (int64, error) {
+	return r.base.RewrapEncryptedData()
+}
+
 func (r *deviceChatStorage) DeleteDeviceData(deviceID string) error {
 	if r.base == nil {
 		return nil
@@ -0,0 +1,29 @@
+package whatsapp
+
+import "sync"
+
+var (
+	connectedListenersMu sync.RWMutex
+	connectedListeners   []func(deviceID string)
+)
+
+// OnDeviceConnected registers fn to be called with the registry ID of a device every time it (re)connects.
+// Listeners run on their own goroutine so a slow listener never blocks event handling.
+func OnDeviceConnected(fn func(deviceID string)) {
+	if fn == nil {
+		return
+	}
+	connectedListenersMu.Lock()
+	defer connectedListenersMu.Unlock()
+	connectedListeners = append(connectedListeners, fn)
+}
+
+func notifyDeviceConnected(deviceID string) {
+	connectedListenersMu.RLock()
+	listeners := append([]func(string){}, connectedListeners...)
+	connectedListenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		go listener(deviceID)
+	}
+}
@@ -0,0 +1,24 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotifyDeviceConnected_CallsListeners(t *testing.T) {
+	connected := make(chan string, 1)
+	OnDeviceConnected(func(deviceID string) {
+		connected <- deviceID
+	})
+
+	notifyDeviceConnected("sales")
+
+	select {
+	case deviceID := <-connected:
+		if deviceID != "sales" {
+			t.Fatalf("expected listener to receive sales, got %s", deviceID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("listener was not called")
+	}
+}
@@ -51,3 +51,11 @@ func DeviceIDFromContext(ctx context.Context) string {
 	}
 	return ""
 }
+
+// InstanceIDFromContext returns the registry ID of the device in context, the label metrics use.
+func InstanceIDFromContext(ctx context.Context) string {
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		return inst.ID()
+	}
+	return ""
+}
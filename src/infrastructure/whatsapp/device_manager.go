@@ -242,6 +242,18 @@ func (m *DeviceManager) ListDevices() []*DeviceInstance {
 	return result
 }
 
+// States returns the connection state of every device by device ID.
+func (m *DeviceManager) States() map[string]domainDevice.DeviceState {
+	states := make(map[string]domainDevice.DeviceState)
+	if m == nil {
+		return states
+	}
+	for _, instance := range m.ListDevices() {
+		states[instance.ID()] = instance.State()
+	}
+	return states
+}
+
 // LoadExistingDevices registers existing device records in the store container without connecting them.
 // This keeps the registry aware of all device IDs even before their clients are initialized.
 func (m *DeviceManager) LoadExistingDevices(ctx context.Context) error {
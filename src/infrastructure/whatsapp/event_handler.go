@@ -46,7 +46,7 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 	case *events.Message:
 		handleMessage(ctx, evt, chatStorageRepo, client)
 	case *events.Receipt:
-		handleReceipt(ctx, evt, instance.JID(), client)
+		handleReceipt(ctx, evt, instance.JID(), client, chatStorageRepo)
 	case *events.Presence:
 		handlePresence(ctx, evt)
 	case *events.HistorySync:
@@ -87,9 +87,9 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 
 	// Send webhook notification for delete event
-	if len(config.WhatsappWebhook) > 0 {
+	if ctx, ok := withWebhookTargets(ctx); ok {
 		go func(c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			webhookCtx, cancel := webhookContext(ctx)
 			defer cancel()
 			if err := forwardDeleteToWebhook(webhookCtx, evt, message, deviceID, c); err != nil {
 				log.Errorf("Failed to forward delete event to webhook: %v", err)
@@ -157,6 +157,7 @@ func handleConnectionEvents(ctx context.Context, client *whatsmeow.Client, insta
 	if instance != nil {
 		instance.UpdateStateFromClient()
 		instance.StartPeriodicSync()
+		notifyDeviceConnected(instance.ID())
 
 		// Persist updated JID/DisplayName to database after successful connection
 		// Skip if instance.ID looks like a JID (auto-created device) to avoid recreating deleted duplicates
@@ -192,7 +193,15 @@ func handleStreamReplaced(_ context.Context) {
 	os.Exit(0)
 }
 
-func handleReceipt(ctx context.Context, evt *events.Receipt, deviceID string, client *whatsmeow.Client) {
+func handleReceipt(ctx context.Context, evt *events.Receipt, deviceID string, client *whatsmeow.Client, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo != nil {
+		if receipts := receiptsFromEvent(ctx, evt, deviceID, client); len(receipts) > 0 {
+			if err := chatStorageRepo.StoreReceipts(receipts); err != nil {
+				log.Errorf("Failed to store receipts for %v: %v", evt.MessageIDs, err)
+			}
+		}
+	}
+
 	sendReceipt := false
 	switch evt.Type {
 	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
@@ -205,9 +214,9 @@ func handleReceipt(ctx context.Context, evt *events.Receipt, deviceID string, cl
 
 	// Forward receipt (ack) event to webhook if configured
 	// Note: Receipt events are not rate limited as they are critical for message delivery status
-	if len(config.WhatsappWebhook) > 0 && sendReceipt {
+	if ctx, ok := withWebhookTargets(ctx); ok && sendReceipt {
 		go func(e *events.Receipt, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			webhookCtx, cancel := webhookContext(ctx)
 			defer cancel()
 			if err := forwardReceiptToWebhook(webhookCtx, e, deviceID, c); err != nil {
 				logrus.Errorf("Failed to forward ack event to webhook: %v", err)
@@ -256,9 +265,9 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, deviceID string
 	}
 
 	// Forward group info event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
+	if ctx, ok := withWebhookTargets(ctx); ok {
 		go func(e *events.GroupInfo, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			webhookCtx, cancel := webhookContext(ctx)
 			defer cancel()
 			if err := forwardGroupInfoToWebhook(webhookCtx, e, deviceID, c); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
@@ -341,9 +350,9 @@ func handleCallOfferEvent(ctx context.Context, evt *events.CallOffer, deviceID s
 
 	outerBody["payload"] = payload
 
-	if len(config.WhatsappWebhook) > 0 {
+	if ctx, ok := withWebhookTargets(ctx); ok {
 		go func(body map[string]any) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			webhookCtx, cancel := webhookContext(ctx)
 			defer cancel()
 			if err := forwardPayloadToConfiguredWebhooks(webhookCtx, body, "call offer event"); err != nil {
 				log.Errorf("Failed to forward call offer event to webhook: %v", err)
@@ -415,9 +424,9 @@ func handleCallTerminateEvent(ctx context.Context, evt *events.CallTerminate, de
 
 	outerBody["payload"] = payload
 
-	if len(config.WhatsappWebhook) > 0 {
+	if ctx, ok := withWebhookTargets(ctx); ok {
 		go func(body map[string]any) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			webhookCtx, cancel := webhookContext(ctx)
 			defer cancel()
 			if err := forwardPayloadToConfiguredWebhooks(webhookCtx, body, "call terminate event"); err != nil {
 				log.Errorf("Failed to forward call terminate event to webhook: %v", err)
@@ -2,8 +2,6 @@ package whatsapp
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"net/http"
 	"path/filepath"
@@ -18,7 +16,7 @@ import (
 	waE2E "go.mau.fi/whatsmeow/proto/waE2E"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/pollstore"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -32,6 +30,8 @@ const (
 	EventTypeMessageRevoked  = "message.revoked"
 	EventTypeMessageEdited   = "message.edited"
 	EventTypeMessagePollVote = "message.poll_vote"
+
+	EventTypeMessageScheduledSent = "message.scheduled_sent"
 )
 
 // WebhookEvent is the top-level structure for webhook payloads
@@ -143,31 +143,24 @@ func buildEventPayload(ctx context.Context, client *whatsmeow.Client, evt *event
 		payload["Original_Message_ID"] = originalMsgID
 		payload["Type"] = "poll_response_message"
 
-		pollData, found := pollstore.DefaultPollStore.GetPoll(originalMsgID)
-		if !found || pollData.EncKey == nil {
+		var poll *domainChatStorage.Poll
+		if repo := chatStorageFromContext(ctx); repo != nil {
+			var err error
+			if poll, err = repo.GetPoll("", originalMsgID); err != nil {
+				logrus.Errorf("Failed to get poll %s: %v", originalMsgID, err)
+			}
+		}
+		if poll == nil || len(poll.EncKey) == 0 {
 			logrus.Warnf("Original poll message %s or its encKey not found in store, cannot decrypt votes", originalMsgID)
 			payload["Votes"] = "could not decrypt, original poll data not found"
 		} else {
-			decryptedVote, err := manualDecryptPollVote(&evt.Info, pollUpdate, pollData.EncKey)
+			decryptedVotes, err := decryptPollVoteOptions(evt, poll)
 			if err != nil {
 				logrus.Errorf("could not manually decrypt poll vote for message %s: %v", originalMsgID, err)
 				payload["Votes"] = fmt.Sprintf("could not decrypt, decryption failed: %v", err)
 			} else {
-				selectedHashes := make(map[string]struct{})
-				for _, hash := range decryptedVote.GetSelectedOptions() {
-					selectedHashes[hex.EncodeToString(hash)] = struct{}{}
-				}
-
-				var decryptedVotes []string
-				for _, option := range pollData.Options {
-					hash := sha256.Sum256([]byte(option))
-					hashStr := hex.EncodeToString(hash[:])
-					if _, ok := selectedHashes[hashStr]; ok {
-						decryptedVotes = append(decryptedVotes, option)
-					}
-				}
-				payload["Question"] = pollData.Question
-				payload["Options"] = pollData.Options
+				payload["Question"] = poll.Question
+				payload["Options"] = poll.Options
 				payload["Votes"] = decryptedVotes
 			}
 		}
@@ -309,17 +302,10 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, evt *events
 				logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
 				return pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
 			}
-			payload["Audio"] = path.MediaPath
+			payload["Audio"] = path.MediaURL
 			payload["Extension_arq"] = filepath.Ext(path.MediaPath)
 
-			// Start a goroutine to delete the file after 30 seconds
-			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
-					logrus.Errorf("Failed to delete media file %s: %v", mediaPath, err)
-				} else {
-					logrus.Infof("Media file %s deleted from PathMedia after 30 seconds", mediaPath)
-				}
-			}(path.MediaPath)
+			go removeWebhookMedia(path.MediaPath)
 		} else {
 			payload["Audio"] = map[string]any{
 				"url": audioMedia.GetURL(),
@@ -338,19 +324,12 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, evt *events
 				return pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
 			}
 			payload["Document"] = map[string]any{
-				"Path":    path.MediaPath,
+				"Path":    path.MediaURL,
 				"Caption": documentMedia.GetCaption(),
 			}
 			payload["Extension_arq"] = filepath.Ext(path.MediaPath)
 
-			// Start a goroutine to delete the file after 30 seconds
-			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
-					logrus.Errorf("Failed to delete media file %s: %v", mediaPath, err)
-				} else {
-					logrus.Infof("Media file %s deleted from PathMedia after 30 seconds", mediaPath)
-				}
-			}(path.MediaPath)
+			go removeWebhookMedia(path.MediaPath)
 		} else {
 			payload["Document"] = map[string]any{
 				"url":      documentMedia.GetURL(),
@@ -373,19 +352,12 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, evt *events
 				return pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
 			}
 			payload["Image"] = map[string]any{
-				"Path":    path.MediaPath,
+				"Path":    path.MediaURL,
 				"Caption": imageMedia.GetCaption(),
 			}
 			payload["Extension_arq"] = filepath.Ext(path.MediaPath)
 
-			// Start a goroutine to delete the file after 30 seconds
-			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
-					logrus.Errorf("Failed to delete media file %s: %v", mediaPath, err)
-				} else {
-					logrus.Infof("Media file %s deleted from PathMedia after 30 seconds", mediaPath)
-				}
-			}(path.MediaPath)
+			go removeWebhookMedia(path.MediaPath)
 		} else {
 			payload["Image"] = map[string]any{
 				"url":     imageMedia.GetURL(),
@@ -404,17 +376,10 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, evt *events
 				logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
 				return pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
 			}
-			payload["Sticker"] = path.MediaPath
+			payload["Sticker"] = path.MediaURL
 			payload["Extension_arq"] = filepath.Ext(path.MediaPath)
 
-			// Start a goroutine to delete the file after 30 seconds
-			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
-					logrus.Errorf("Failed to delete media file %s: %v", mediaPath, err)
-				} else {
-					logrus.Infof("Media file %s deleted from PathMedia after 30 seconds", mediaPath)
-				}
-			}(path.MediaPath)
+			go removeWebhookMedia(path.MediaPath)
 		} else {
 			payload["Sticker"] = map[string]any{
 				"url": stickerMedia.GetURL(),
@@ -433,19 +398,12 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, evt *events
 				return pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
 			}
 			payload["Video"] = map[string]any{
-				"Path":    path.MediaPath,
+				"Path":    path.MediaURL,
 				"Caption": videoMedia.GetCaption(),
 			}
 			payload["Extension_arq"] = filepath.Ext(path.MediaPath)
 
-			// Start a goroutine to delete the file after 30 seconds
-			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
-					logrus.Errorf("Failed to delete media file %s: %v", mediaPath, err)
-				} else {
-					logrus.Infof("Media file %s deleted from PathMedia after 30 seconds", mediaPath)
-				}
-			}(path.MediaPath)
+			go removeWebhookMedia(path.MediaPath)
 		} else {
 			payload["Video"] = map[string]any{
 				"url":     videoMedia.GetURL(),
@@ -465,19 +423,12 @@ func buildMediaFields(ctx context.Context, client *whatsmeow.Client, evt *events
 				return pkgError.WebhookError(fmt.Sprintf("Failed to download video note: %v", err))
 			}
 			payload["Video_Note"] = map[string]any{
-				"Path":    path.MediaPath,
+				"Path":    path.MediaURL,
 				"Caption": ptvMedia.GetCaption(),
 			}
 			payload["Extension_arq"] = filepath.Ext(path.MediaPath)
 
-			// Start a goroutine to delete the file after 30 seconds
-			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
-					logrus.Errorf("Failed to delete media file %s: %v", mediaPath, err)
-				} else {
-					logrus.Infof("Media file %s deleted from PathMedia after 30 seconds", mediaPath)
-				}
-			}(path.MediaPath)
+			go removeWebhookMedia(path.MediaPath)
 		} else {
 			payload["Video_Note"] = map[string]any{
 				"url":     ptvMedia.GetURL(),
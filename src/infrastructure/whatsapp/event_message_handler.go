@@ -8,7 +8,7 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/pollstore"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
@@ -41,14 +41,16 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 		strings.Join(metaParts, ", "),
 		evt.Message,
 	)
+	metrics.MessagesReceived.WithLabelValues(InstanceIDFromContext(ctx), metrics.MessageType(evt.Message)).Inc()
 
 	if err := chatStorageRepo.CreateMessage(ctx, evt); err != nil {
 		// Log storage errors to avoid silent failures that could lead to data loss
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
 	}
 
-	// Handle poll creation message
-	handlePollCreationMessage(evt)
+	// Handle poll creation and vote messages
+	handlePollCreationMessage(ctx, evt, chatStorageRepo, client)
+	handlePollUpdateMessage(ctx, evt, chatStorageRepo, client)
 
 	// Handle media messages and set up auto-deletion
 	handleImageMessage(ctx, evt, client)
@@ -80,7 +82,7 @@ func handleVideoMessage(ctx context.Context, evt *events.Message, client *whatsm
 		} else {
 			log.Infof("Video downloaded to %s", extractedMedia.MediaPath)
 			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
+				if err := utils.RemoveMedia(30, mediaPath); err != nil {
 					log.Errorf("Failed to delete media file %s: %v", mediaPath, err)
 				} else {
 					log.Infof("Media file %s deleted after 30 seconds", mediaPath)
@@ -104,7 +106,7 @@ func handleAudioMessage(ctx context.Context, evt *events.Message, client *whatsm
 		} else {
 			log.Infof("Audio downloaded to %s", extractedMedia.MediaPath)
 			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
+				if err := utils.RemoveMedia(30, mediaPath); err != nil {
 					log.Errorf("Failed to delete media file %s: %v", mediaPath, err)
 				} else {
 					log.Infof("Media file %s deleted after 30 seconds", mediaPath)
@@ -128,7 +130,7 @@ func handleDocumentMessage(ctx context.Context, evt *events.Message, client *wha
 		} else {
 			log.Infof("Document downloaded to %s", extractedMedia.MediaPath)
 			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
+				if err := utils.RemoveMedia(30, mediaPath); err != nil {
 					log.Errorf("Failed to delete media file %s: %v", mediaPath, err)
 				} else {
 					log.Infof("Media file %s deleted after 30 seconds", mediaPath)
@@ -152,7 +154,7 @@ func handleStickerMessage(ctx context.Context, evt *events.Message, client *what
 		} else {
 			log.Infof("Sticker downloaded to %s", extractedMedia.MediaPath)
 			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
+				if err := utils.RemoveMedia(30, mediaPath); err != nil {
 					log.Errorf("Failed to delete media file %s: %v", mediaPath, err)
 				} else {
 					log.Infof("Media file %s deleted after 30 seconds", mediaPath)
@@ -162,23 +164,6 @@ func handleStickerMessage(ctx context.Context, evt *events.Message, client *what
 	}
 }
 
-func handlePollCreationMessage(evt *events.Message) {
-	if pollCreation := evt.Message.GetPollCreationMessage(); pollCreation != nil {
-		options := make([]string, len(pollCreation.Options))
-		for i, option := range pollCreation.Options {
-			options[i] = option.GetOptionName()
-		}
-
-		pollData := pollstore.PollData{
-			Question: pollCreation.GetName(),
-			Options:  options,
-			EncKey:   pollCreation.GetEncKey(),
-		}
-		pollstore.DefaultPollStore.SavePoll(evt.Info.ID, pollData)
-		logrus.Infof("Stored poll creation message %s: %s", evt.Info.ID, pollCreation.GetName())
-	}
-}
-
 func buildMessageMetaParts(evt *events.Message) []string {
 	metaParts := []string{
 		fmt.Sprintf("pushname: %s", evt.Info.PushName),
@@ -212,7 +197,7 @@ func handleImageMessage(ctx context.Context, evt *events.Message, client *whatsm
 			log.Infof("Image downloaded to %s", extractedMedia.MediaPath)
 			// Start a goroutine to delete the file after 30 seconds
 			go func(mediaPath string) {
-				if err := utils.RemoveFile(30, mediaPath); err != nil {
+				if err := utils.RemoveMedia(30, mediaPath); err != nil {
 					log.Errorf("Failed to delete media file %s: %v", mediaPath, err)
 				} else {
 					log.Infof("Media file %s deleted after 30 seconds", mediaPath)
@@ -273,10 +258,10 @@ func handleWebhookForward(ctx context.Context, evt *events.Message, client *what
 		log.Debugf("Forwarding webhook for incoming message %s (IsFromMe=false)", evt.Info.ID)
 	}
 
-	if len(config.WhatsappWebhook) > 0 &&
+	if ctx, ok := withWebhookTargets(ctx); ok &&
 		!strings.Contains(evt.Info.SourceString(), "broadcast") {
 		go func(e *events.Message, c *whatsmeow.Client) {
-			webhookCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			webhookCtx, cancel := webhookContext(ctx)
 			defer cancel()
 			if err := forwardMessageToWebhook(webhookCtx, c, e); err != nil {
 				logrus.Error("Failed forward to webhook: ", err)
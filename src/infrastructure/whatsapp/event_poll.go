@@ -0,0 +1,118 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// chatStorageFromContext returns the chat storage of the device in context, nil when there is none
+func chatStorageFromContext(ctx context.Context) domainChatStorage.IChatStorageRepository {
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		return inst.GetChatStorage()
+	}
+	return nil
+}
+
+// handlePollCreationMessage stores the definition of a poll so its votes can be decrypted later
+func handlePollCreationMessage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	pollCreation := evt.Message.GetPollCreationMessage()
+	if pollCreation == nil || chatStorageRepo == nil {
+		return
+	}
+
+	options := make([]string, len(pollCreation.Options))
+	for i, option := range pollCreation.Options {
+		options[i] = option.GetOptionName()
+	}
+
+	// Votes are encrypted with the message secret; older clients sent the key on the poll itself
+	encKey := evt.Message.GetMessageContextInfo().GetMessageSecret()
+	if len(encKey) == 0 {
+		encKey = pollCreation.GetEncKey()
+	}
+
+	err := chatStorageRepo.StorePoll(&domainChatStorage.Poll{
+		DeviceID:        DeviceIDFromContext(ctx),
+		MessageID:       evt.Info.ID,
+		ChatJID:         NormalizeJIDFromLID(ctx, evt.Info.Chat, client).String(),
+		Question:        pollCreation.GetName(),
+		Options:         options,
+		SelectableCount: int(pollCreation.GetSelectableOptionsCount()),
+		EncKey:          encKey,
+		CreatedAt:       evt.Info.Timestamp,
+	})
+	if err != nil {
+		log.Errorf("Failed to store poll %s: %v", evt.Info.ID, err)
+		return
+	}
+	log.Infof("Stored poll creation message %s: %s", evt.Info.ID, pollCreation.GetName())
+}
+
+// handlePollUpdateMessage decrypts a poll vote and stores it as the voter's latest vote
+func handlePollUpdateMessage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	pollUpdate := evt.Message.GetPollUpdateMessage()
+	if pollUpdate == nil || chatStorageRepo == nil {
+		return
+	}
+
+	pollID := pollUpdate.GetPollCreationMessageKey().GetID()
+	poll, err := chatStorageRepo.GetPoll("", pollID)
+	if err != nil {
+		log.Errorf("Failed to get poll %s: %v", pollID, err)
+		return
+	}
+	if poll == nil {
+		log.Warnf("Poll %s not found in storage, cannot store vote of %s", pollID, evt.Info.Sender)
+		return
+	}
+
+	selected, err := decryptPollVoteOptions(evt, poll)
+	if err != nil {
+		log.Errorf("Could not decrypt vote on poll %s: %v", pollID, err)
+		return
+	}
+
+	err = chatStorageRepo.StorePollVote(&domainChatStorage.PollVote{
+		DeviceID:        DeviceIDFromContext(ctx),
+		MessageID:       pollID,
+		VoterJID:        NormalizeJIDFromLID(ctx, evt.Info.Sender, client).ToNonAD().String(),
+		SelectedOptions: selected,
+		VotedAt:         evt.Info.Timestamp,
+	})
+	if err != nil {
+		log.Errorf("Failed to store vote on poll %s: %v", pollID, err)
+	}
+}
+
+// decryptPollVoteOptions returns the option names selected by a poll vote. Options are matched by
+// their SHA-256 hash, which is all the vote carries.
+func decryptPollVoteOptions(evt *events.Message, poll *domainChatStorage.Poll) ([]string, error) {
+	if len(poll.EncKey) == 0 {
+		return nil, fmt.Errorf("poll %s has no encryption key", poll.MessageID)
+	}
+
+	vote, err := manualDecryptPollVote(&evt.Info, evt.Message.GetPollUpdateMessage(), poll.EncKey)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedHashes := make(map[string]struct{})
+	for _, hash := range vote.GetSelectedOptions() {
+		selectedHashes[hex.EncodeToString(hash)] = struct{}{}
+	}
+
+	selected := []string{}
+	for _, option := range poll.Options {
+		hash := sha256.Sum256([]byte(option))
+		if _, ok := selectedHashes[hex.EncodeToString(hash[:])]; ok {
+			selected = append(selected, option)
+		}
+	}
+	return selected, nil
+}
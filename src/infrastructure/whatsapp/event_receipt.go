@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/pollstore"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
@@ -46,6 +46,47 @@ func getReceiptTypeDescription(evt types.ReceiptType) string {
 	}
 }
 
+// receiptsFromEvent turns a delivered, read or played receipt sent by a recipient into storage rows.
+// A later state implies the earlier ones, so they are filled in with the same time when missing.
+func receiptsFromEvent(ctx context.Context, evt *events.Receipt, deviceID string, client *whatsmeow.Client) []*domainChatStorage.MessageReceipt {
+	// Receipts from our own devices say nothing about the recipients
+	if evt.IsFromMe || len(evt.MessageIDs) == 0 {
+		return nil
+	}
+
+	var deliveredAt, readAt, playedAt *time.Time
+	at := evt.Timestamp.UTC()
+	switch evt.Type {
+	case types.ReceiptTypePlayed:
+		playedAt = &at
+		fallthrough
+	case types.ReceiptTypeRead:
+		readAt = &at
+		fallthrough
+	case types.ReceiptTypeDelivered:
+		deliveredAt = &at
+	default:
+		return nil
+	}
+
+	chatJID := NormalizeJIDFromLID(ctx, evt.Chat, client).ToNonAD().String()
+	recipientJID := NormalizeJIDFromLID(ctx, evt.Sender, client).ToNonAD().String()
+
+	receipts := make([]*domainChatStorage.MessageReceipt, 0, len(evt.MessageIDs))
+	for _, messageID := range evt.MessageIDs {
+		receipts = append(receipts, &domainChatStorage.MessageReceipt{
+			DeviceID:     deviceID,
+			MessageID:    messageID,
+			ChatJID:      chatJID,
+			RecipientJID: recipientJID,
+			DeliveredAt:  deliveredAt,
+			ReadAt:       readAt,
+			PlayedAt:     playedAt,
+		})
+	}
+	return receipts
+}
+
 // createReceiptPayload creates a webhook payload for message acknowledgement (receipt) events
 func createReceiptPayload(ctx context.Context, evt *events.Receipt, deviceID string, client *whatsmeow.Client) map[string]any {
 	body := make(map[string]any)
@@ -56,10 +97,10 @@ func createReceiptPayload(ctx context.Context, evt *events.Receipt, deviceID str
 		payload["IDs"] = evt.MessageIDs
 
 		// Enrich with original message data if it's a poll
-		if pollData, found := pollstore.DefaultPollStore.GetPoll(evt.MessageIDs[0]); found {
+		if poll := receiptPoll(ctx, evt); poll != nil {
 			payload["Poll"] = map[string]any{
-				"Question": pollData.Question,
-				"Options":  pollData.Options,
+				"Question": poll.Question,
+				"Options":  poll.Options,
 			}
 		}
 	}
@@ -89,12 +130,8 @@ func createReceiptPayload(ctx context.Context, evt *events.Receipt, deviceID str
 	payload["From_Me"] = evt.IsFromMe
 
 	// Determine and add Type field
-	if len(evt.MessageIDs) > 0 { // Check if MessageIDs is not empty before trying to access index 0
-		if _, found := pollstore.DefaultPollStore.GetPoll(evt.MessageIDs[0]); found {
-			payload["Type"] = "poll_message"
-		} else {
-			payload["Type"] = "receipt_message"
-		}
+	if payload["Poll"] != nil {
+		payload["Type"] = "poll_message"
 	} else {
 		payload["Type"] = "receipt_message"
 	}
@@ -147,3 +184,18 @@ func forwardReceiptToWebhook(ctx context.Context, evt *events.Receipt, deviceID
 	payload := createReceiptPayload(ctx, evt, deviceID, client)
 	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message ack event")
 }
+
+// receiptPoll returns the poll the first message of a receipt belongs to, nil when it is not a poll
+func receiptPoll(ctx context.Context, evt *events.Receipt) *domainChatStorage.Poll {
+	repo := chatStorageFromContext(ctx)
+	if repo == nil || len(evt.MessageIDs) == 0 {
+		return nil
+	}
+
+	poll, err := repo.GetPoll("", evt.MessageIDs[0])
+	if err != nil {
+		log.Warnf("Failed to get poll %s: %v", evt.MessageIDs[0], err)
+		return nil
+	}
+	return poll
+}
@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func newTestReceipt(receiptType types.ReceiptType, fromMe bool) *events.Receipt {
+	return &events.Receipt{
+		MessageSource: types.MessageSource{
+			Chat:     types.NewJID("120363025246125486", types.GroupServer),
+			Sender:   types.NewJID("6289685028129", types.DefaultUserServer),
+			IsFromMe: fromMe,
+			IsGroup:  true,
+		},
+		MessageIDs: []string{"3EB0A1", "3EB0A2"},
+		Timestamp:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Type:       receiptType,
+	}
+}
+
+func TestReceiptsFromEvent_ReadImpliesDelivered(t *testing.T) {
+	receipts := receiptsFromEvent(context.Background(), newTestReceipt(types.ReceiptTypeRead, false), "628111@s.whatsapp.net", nil)
+	if len(receipts) != 2 {
+		t.Fatalf("expected one receipt per message id, got %d", len(receipts))
+	}
+
+	receipt := receipts[0]
+	if receipt.RecipientJID != "6289685028129@s.whatsapp.net" || receipt.ChatJID != "120363025246125486@g.us" {
+		t.Fatalf("unexpected recipient %s in chat %s", receipt.RecipientJID, receipt.ChatJID)
+	}
+	if receipt.DeliveredAt == nil || receipt.ReadAt == nil || receipt.PlayedAt != nil {
+		t.Fatalf("expected delivered and read to be set without played, got %+v", receipt)
+	}
+	if receipt.Status() != "read" {
+		t.Fatalf("expected status read, got %s", receipt.Status())
+	}
+}
+
+func TestReceiptsFromEvent_IgnoresOwnAndUntrackedReceipts(t *testing.T) {
+	if receipts := receiptsFromEvent(context.Background(), newTestReceipt(types.ReceiptTypeRead, true), "628111@s.whatsapp.net", nil); receipts != nil {
+		t.Fatalf("expected receipts from own devices to be ignored, got %d", len(receipts))
+	}
+	if receipts := receiptsFromEvent(context.Background(), newTestReceipt(types.ReceiptTypeRetry, false), "628111@s.whatsapp.net", nil); receipts != nil {
+		t.Fatalf("expected retry receipts to be ignored, got %d", len(receipts))
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waHistorySync"
@@ -55,6 +57,7 @@ func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorage
 			log.Errorf("Failed to process history sync to database: %v", err)
 		}
 	}
+	metrics.HistorySyncBatches.WithLabelValues(InstanceIDFromContext(ctx), strings.ToLower(evt.Data.GetSyncType().String())).Inc()
 }
 
 // processHistorySync processes history sync data and stores messages in the database
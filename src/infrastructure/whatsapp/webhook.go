@@ -12,11 +12,75 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
+	return submitSignedWebhook(ctx, payload, url, config.WhatsappWebhookSecret)
+}
+
+// submitSignedWebhook posts the payload inline with in-process retries, signing it with secret.
+func submitSignedWebhook(ctx context.Context, payload map[string]any, url string, secret string) (err error) {
+	ctx, span := tracing.Start(ctx, "webhook.deliver")
+	defer func() { tracing.End(span, err) }()
+
+	postBodyBytes, err := encodeWebhookPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	var attempt int
+	var maxAttempts = 5
+	var sleepDuration = 1 * time.Second
+
+	for attempt = 0; attempt < maxAttempts; attempt++ {
+		err = postWebhook(ctx, postBodyBytes, url, secret)
+		if err == nil {
+			span.SetAttributes(attribute.Int("webhook.attempts", attempt+1))
+			logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
+			return nil
+		}
+		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
+		if attempt < maxAttempts-1 {
+			time.Sleep(sleepDuration)
+			sleepDuration *= 2
+		}
+	}
+
+	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
+}
+
+// encodeWebhookPayload serializes the payload exactly as it is signed and sent.
+func encodeWebhookPayload(payload map[string]any) ([]byte, error) {
+	var postBodyBuffer bytes.Buffer
+	encoder := json.NewEncoder(&postBodyBuffer)
+	encoder.SetEscapeHTML(false) // Desabilitar o escape de HTML
+	if err := encoder.Encode(payload); err != nil {
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+	}
+	return postBodyBuffer.Bytes(), nil
+}
+
+// postWebhook performs a single signed POST of an already encoded body, with the trace context in traceparent.
+// Both the inline retries and the outbox dispatcher go through it, so every attempt is counted here.
+func postWebhook(ctx context.Context, body []byte, url string, secret string) (err error) {
+	ctx, span := tracing.Start(ctx, "webhook POST", trace.WithSpanKind(trace.SpanKindClient))
+	started := time.Now()
+	defer func() {
+		metrics.WebhookAttempts.Inc()
+		metrics.WebhookDuration.Observe(time.Since(started).Seconds())
+		if err != nil {
+			metrics.WebhookFailures.Inc()
+		}
+		tracing.End(span, err)
+	}()
+
 	// Configure HTTP client with optional TLS skip verification
 	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
@@ -28,53 +92,31 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string) erro
 		Transport: transport,
 	}
 
-	var postBodyBuffer bytes.Buffer
-	encoder := json.NewEncoder(&postBodyBuffer)
-	encoder.SetEscapeHTML(false) // Desabilitar o escape de HTML
-	var err error // Declarar 'err' aqui
-	err = encoder.Encode(payload)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
-	}
-
-	postBodyBytes := postBodyBuffer.Bytes() // Obter os bytes do buffer
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
 	}
 
-	secretKey := []byte(config.WhatsappWebhookSecret)
-	signature, err := utils.GetMessageDigestOrSignature(postBodyBytes, secretKey)
+	signature, err := utils.GetMessageDigestOrSignature(body, []byte(secret))
 	if err != nil {
 		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+	tracing.Inject(ctx, req.Header)
+	span.SetAttributes(semconv.HTTPRequestMethodKey.String(http.MethodPost), semconv.ServerAddress(req.URL.Hostname()))
 
-	var attempt int
-	var maxAttempts = 5
-	var sleepDuration = 1 * time.Second
-
-	for attempt = 0; attempt < maxAttempts; attempt++ {
-		// Create new request body for each attempt
-		req.Body = io.NopCloser(bytes.NewBuffer(postBodyBytes))
-		resp, err := client.Do(req)
-		if err == nil {
-			defer resp.Body.Close()
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
-				return nil
-			}
-			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
-		}
-		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
-		if attempt < maxAttempts-1 {
-			time.Sleep(sleepDuration)
-			sleepDuration *= 2
-		}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
 
-	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
 }
@@ -4,27 +4,42 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/sirupsen/logrus"
 )
 
 var submitWebhookFn = submitWebhook
 
-// forwardPayloadToConfiguredWebhooks attempts to deliver the provided payload to every configured webhook URL.
-// It only returns an error when all webhook deliveries fail. Partial failures are logged and suppressed so
-// successful targets still receive the event.
+// webhookSubscriptionsKey carries the subscriptions already loaded for an event, so the gate in the event
+// handlers and the forwarding itself share one lookup.
+type webhookSubscriptionsKey struct{}
+
+type resolvedWebhookSubscriptions struct {
+	deviceID      string
+	subscriptions []*domainWebhook.Subscription
+}
+
+// webhookTarget is one URL an event should be delivered to, either global or owned by a device subscription.
+type webhookTarget struct {
+	url            string
+	secret         string
+	subscriptionID string
+}
+
+// forwardPayloadToConfiguredWebhooks attempts to deliver the provided payload to every configured webhook URL
+// plus the enabled subscriptions of the device that produced it.
+// When the outbox is configured the payload is persisted per URL and delivered by the dispatcher; otherwise
+// it is posted inline. It only returns an error when all webhook deliveries fail. Partial failures are logged
+// and suppressed so successful targets still receive the event.
 func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string) error {
-	// Check if event is whitelisted (if whitelist is configured)
-	if len(config.WhatsappWebhookEvents) > 0 {
-		if !isEventWhitelisted(eventName) {
-			logrus.Debugf("Skipping event %s - not in webhook events whitelist", eventName)
-			return nil
-		}
-	}
+	event := webhookEventName(payload, eventName)
+	targets := resolveWebhookTargets(ctx, payload, event)
 
-	total := len(config.WhatsappWebhook)
+	total := len(targets)
 	logrus.Infof("Forwarding %s to %d configured webhook(s)", eventName, total)
 
 	if total == 0 {
@@ -36,10 +51,19 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 		failed    []string
 		successes int
 	)
-	for _, url := range config.WhatsappWebhook {
-		if err := submitWebhookFn(ctx, payload, url); err != nil {
-			failed = append(failed, fmt.Sprintf("%s: %v", url, err))
-			logrus.Warnf("Failed forwarding %s to %s: %v", eventName, url, err)
+	for _, target := range targets {
+		var err error
+		switch {
+		case webhookStore != nil:
+			err = enqueueWebhookDelivery(ctx, payload, target, event)
+		case target.subscriptionID == "":
+			err = submitWebhookFn(ctx, payload, target.url)
+		default:
+			err = submitSignedWebhook(ctx, payload, target.url, target.secret)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", target.url, err))
+			logrus.Warnf("Failed forwarding %s to %s: %v", eventName, target.url, err)
 			continue
 		}
 		successes++
@@ -58,6 +82,143 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 	return nil
 }
 
+// ForwardEventToWebhooks wraps payload in the standard envelope for the device in ctx and delivers it to every
+// webhook that accepts event. It is a no-op when nothing would receive it.
+func ForwardEventToWebhooks(ctx context.Context, event string, payload map[string]any) error {
+	ctx, ok := withWebhookTargets(ctx)
+	if !ok {
+		return nil
+	}
+
+	deviceID := DeviceIDFromContext(ctx)
+	if client := ClientFromContext(ctx); client != nil && client.Store != nil && client.Store.ID != nil {
+		deviceID = NormalizeJIDFromLID(ctx, client.Store.ID.ToNonAD(), client).ToNonAD().String()
+	}
+
+	envelope := map[string]any{
+		"event":     event,
+		"device_id": deviceID,
+		"payload":   payload,
+	}
+	return forwardPayloadToConfiguredWebhooks(ctx, envelope, event+" event")
+}
+
+// resolveWebhookTargets applies the global whitelist to the global URLs and each subscription's own
+// whitelist to the device subscriptions.
+func resolveWebhookTargets(ctx context.Context, payload map[string]any, event string) []webhookTarget {
+	var targets []webhookTarget
+
+	if len(config.WhatsappWebhookEvents) > 0 && !isEventWhitelisted(event) {
+		logrus.Debugf("Skipping global webhooks for event %s - not in webhook events whitelist", event)
+	} else {
+		for _, url := range config.WhatsappWebhook {
+			targets = append(targets, webhookTarget{url: url, secret: config.WhatsappWebhookSecret})
+		}
+	}
+
+	for _, subscription := range deviceWebhookSubscriptions(ctx, payload) {
+		if !subscription.Enabled || !subscriptionWantsEvent(subscription, event) {
+			continue
+		}
+		targets = append(targets, webhookTarget{
+			url:            subscription.URL,
+			secret:         subscriptionSecret(subscription),
+			subscriptionID: subscription.ID,
+		})
+	}
+
+	return targets
+}
+
+// withWebhookTargets reports whether any webhook could receive events from the device in ctx. Event handlers
+// use it to skip building payloads nobody will receive, and forward with the returned context so the device
+// subscriptions are only loaded once per event.
+func withWebhookTargets(ctx context.Context) (context.Context, bool) {
+	subscriptions := deviceWebhookSubscriptions(ctx, nil)
+	if deviceID := webhookDeviceID(ctx, nil); deviceID != "" && webhookStore != nil {
+		ctx = context.WithValue(ctx, webhookSubscriptionsKey{}, resolvedWebhookSubscriptions{
+			deviceID:      deviceID,
+			subscriptions: subscriptions,
+		})
+	}
+
+	if len(config.WhatsappWebhook) > 0 {
+		return ctx, true
+	}
+	for _, subscription := range subscriptions {
+		if subscription.Enabled {
+			return ctx, true
+		}
+	}
+	return ctx, false
+}
+
+// webhookContext detaches webhook work from the caller's cancellation while keeping the device scope.
+func webhookContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+}
+
+func deviceWebhookSubscriptions(ctx context.Context, payload map[string]any) []*domainWebhook.Subscription {
+	if webhookStore == nil {
+		return nil
+	}
+
+	deviceID := webhookDeviceID(ctx, payload)
+	if deviceID == "" {
+		return nil
+	}
+	if resolved, ok := ctx.Value(webhookSubscriptionsKey{}).(resolvedWebhookSubscriptions); ok && resolved.deviceID == deviceID {
+		return resolved.subscriptions
+	}
+
+	subscriptions, err := webhookStore.ListSubscriptions(deviceID)
+	if err != nil {
+		logrus.Warnf("Failed to load webhook subscriptions for device %s: %v", deviceID, err)
+		return nil
+	}
+	return subscriptions
+}
+
+// webhookDeviceID resolves the registry device ID from ctx, falling back to the JID carried in the payload.
+func webhookDeviceID(ctx context.Context, payload map[string]any) string {
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		return inst.ID()
+	}
+
+	jid, _ := payload["device_id"].(string)
+	if jid == "" {
+		return ""
+	}
+	if dm := GetDeviceManager(); dm != nil {
+		for _, inst := range dm.ListDevices() {
+			if inst.ID() == jid || inst.JID() == jid {
+				return inst.ID()
+			}
+		}
+	}
+	return ""
+}
+
+func subscriptionWantsEvent(subscription *domainWebhook.Subscription, event string) bool {
+	if len(subscription.Events) == 0 {
+		return true
+	}
+	for _, allowed := range subscription.Events {
+		if strings.EqualFold(strings.TrimSpace(allowed), event) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriptionSecret falls back to the global secret when a subscription has none of its own.
+func subscriptionSecret(subscription *domainWebhook.Subscription) string {
+	if subscription.Secret != "" {
+		return subscription.Secret
+	}
+	return config.WhatsappWebhookSecret
+}
+
 // isEventWhitelisted checks if the given event name is in the configured whitelist
 func isEventWhitelisted(eventName string) bool {
 	for _, allowed := range config.WhatsappWebhookEvents {
@@ -0,0 +1,248 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookDispatchInterval   = 2 * time.Second
+	webhookDispatchBatchSize  = 50
+	webhookDispatchWorkers    = 8
+	webhookDeliveryTimeout    = 15 * time.Second
+	webhookDeliveryLease      = 2 * time.Minute
+	webhookRetryBaseDelay     = 5 * time.Second
+	webhookRetryMaxDelay      = time.Hour
+	webhookDeliveredRetention = 7 * 24 * time.Hour
+)
+
+var (
+	webhookStore      domainWebhook.IWebhookRepository
+	webhookOutboxWake = make(chan struct{}, 1)
+	postWebhookFn     = postWebhook
+
+	// Media downloaded for a webhook payload is removed after webhookMediaDelay, or once no pending
+	// delivery links to it, checked every webhookMediaPollInterval
+	webhookMediaDelay        = 30 * time.Second
+	webhookMediaPollInterval = time.Minute
+)
+
+// SetWebhookStore routes webhook deliveries through the persistent outbox instead of in-process retries
+// and enables per-device subscriptions.
+func SetWebhookStore(repo domainWebhook.IWebhookRepository) {
+	webhookStore = repo
+}
+
+// StartWebhookDispatcher drains the outbox in the background until ctx is cancelled.
+// Pending rows left over from a previous run are picked up on the first tick.
+func StartWebhookDispatcher(ctx context.Context) {
+	if webhookStore == nil {
+		logrus.Warn("webhook outbox not configured; dispatcher not started")
+		return
+	}
+	go runWebhookDispatcher(ctx)
+}
+
+// enqueueWebhookDelivery stores the payload for a single target and nudges the dispatcher. The trace context
+// of ctx is kept with it, so the delivery continues the trace of the event.
+func enqueueWebhookDelivery(ctx context.Context, payload map[string]any, target webhookTarget, eventName string) error {
+	body, err := encodeWebhookPayload(payload)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	tracing.Inject(ctx, header)
+
+	deviceID, _ := payload["device_id"].(string)
+	delivery := &domainWebhook.Delivery{
+		DeviceID:       deviceID,
+		Event:          eventName,
+		URL:            target.url,
+		Payload:        body,
+		SubscriptionID: target.subscriptionID,
+		TraceParent:    header.Get("traceparent"),
+	}
+	if err := webhookStore.EnqueueDelivery(delivery); err != nil {
+		return pkgError.WebhookError("failed to enqueue webhook delivery: " + err.Error())
+	}
+
+	select {
+	case webhookOutboxWake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// removeWebhookMedia deletes media a webhook payload links to. With the outbox the file is kept until no pending
+// delivery carries the link, so a retried delivery does not hand the receiver a dead URL.
+func removeWebhookMedia(mediaPath string) {
+	time.Sleep(webhookMediaDelay)
+	for webhookStore != nil {
+		pending, err := webhookStore.HasPendingDelivery(path.Base(mediaPath))
+		if err != nil {
+			logrus.Warnf("Failed to check webhook deliveries for media %s: %v", mediaPath, err)
+		}
+		if err != nil || !pending {
+			break
+		}
+		time.Sleep(webhookMediaPollInterval)
+	}
+
+	if err := utils.RemoveMedia(0, mediaPath); err != nil {
+		logrus.Errorf("Failed to delete media file %s: %v", mediaPath, err)
+	} else {
+		logrus.Infof("Media file %s deleted from PathMedia after its webhooks were sent", mediaPath)
+	}
+}
+
+// webhookEventName prefers the event type carried by the payload over the caller's description.
+func webhookEventName(payload map[string]any, fallback string) string {
+	for _, key := range []string{"event", "action"} {
+		if name, ok := payload[key].(string); ok && name != "" {
+			return name
+		}
+	}
+	return fallback
+}
+
+func runWebhookDispatcher(ctx context.Context) {
+	ticker := time.NewTicker(webhookDispatchInterval)
+	defer ticker.Stop()
+	purgeTicker := time.NewTicker(time.Hour)
+	defer purgeTicker.Stop()
+
+	logrus.Info("Webhook outbox dispatcher started")
+	for {
+		dispatchDueWebhooks(ctx)
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Webhook outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+		case <-webhookOutboxWake:
+		case <-purgeTicker.C:
+			purged, err := webhookStore.PurgeDeliveredBefore(time.Now().Add(-webhookDeliveredRetention))
+			if err != nil {
+				logrus.Warnf("Failed to purge delivered webhooks: %v", err)
+			} else if purged > 0 {
+				logrus.Infof("Purged %d delivered webhook(s) from outbox", purged)
+			}
+		}
+	}
+}
+
+// dispatchDueWebhooks delivers one batch of due rows with bounded concurrency. A row is only claimed once a
+// worker is free, so its lease starts with the attempt instead of running out while it waits for a slot.
+func dispatchDueWebhooks(ctx context.Context) {
+	deliveries, err := webhookStore.FetchDueDeliveries(time.Now(), webhookDispatchBatchSize)
+	if err != nil {
+		logrus.Errorf("Failed to fetch due webhook deliveries: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, webhookDispatchWorkers)
+	for _, delivery := range deliveries {
+		sem <- struct{}{}
+		claimed, err := webhookStore.ClaimDelivery(delivery.ID, delivery.Attempts, time.Now().Add(webhookDeliveryLease))
+		if err != nil {
+			logrus.Errorf("Failed to claim webhook delivery %s: %v", delivery.ID, err)
+		}
+		if err != nil || !claimed {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func(d *domainWebhook.Delivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			deliverOutboxEntry(ctx, d)
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+func deliverOutboxEntry(ctx context.Context, delivery *domainWebhook.Delivery) {
+	attempt := delivery.Attempts + 1
+
+	secret, err := deliverySecret(delivery)
+	if err != nil {
+		if markErr := webhookStore.MarkDeliveryFailed(delivery.ID, err.Error(), time.Now(), true); markErr != nil {
+			logrus.Errorf("Failed to record webhook delivery %s failure: %v", delivery.ID, markErr)
+		}
+		logrus.Warnf("Webhook delivery %s dead-lettered: %v", delivery.ID, err)
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, webhookDeliveryTimeout)
+	if delivery.TraceParent != "" {
+		reqCtx = tracing.Extract(reqCtx, http.Header{"Traceparent": {delivery.TraceParent}})
+	}
+	err = postWebhookFn(reqCtx, delivery.Payload, delivery.URL, secret)
+	cancel()
+
+	if err == nil {
+		if markErr := webhookStore.MarkDeliverySucceeded(delivery.ID); markErr != nil {
+			logrus.Errorf("Failed to mark webhook delivery %s as delivered: %v", delivery.ID, markErr)
+		}
+		logrus.Infof("Delivered %s webhook %s to %s on attempt %d", delivery.Event, delivery.ID, delivery.URL, attempt)
+		return
+	}
+
+	dead := attempt >= config.WhatsappWebhookMaxAttempts
+	nextAttemptAt := time.Now().Add(webhookRetryDelay(attempt))
+	if markErr := webhookStore.MarkDeliveryFailed(delivery.ID, err.Error(), nextAttemptAt, dead); markErr != nil {
+		logrus.Errorf("Failed to record webhook delivery %s failure: %v", delivery.ID, markErr)
+	}
+
+	if dead {
+		logrus.Errorf("Webhook delivery %s to %s dead-lettered after %d attempts: %v", delivery.ID, delivery.URL, attempt, err)
+		return
+	}
+	logrus.Warnf("Attempt %d of webhook delivery %s to %s failed, retrying at %s: %v",
+		attempt, delivery.ID, delivery.URL, nextAttemptAt.Format(time.RFC3339), err)
+}
+
+// deliverySecret looks up the signing secret at send time so rotated subscription secrets apply to retries.
+func deliverySecret(delivery *domainWebhook.Delivery) (string, error) {
+	if delivery.SubscriptionID == "" {
+		return config.WhatsappWebhookSecret, nil
+	}
+
+	subscription, err := webhookStore.GetSubscriptionByID(delivery.SubscriptionID)
+	if err != nil {
+		return "", err
+	}
+	if subscription == nil {
+		return "", fmt.Errorf("webhook subscription %s no longer exists", delivery.SubscriptionID)
+	}
+	if !subscription.Enabled {
+		return "", fmt.Errorf("webhook subscription %s is disabled", delivery.SubscriptionID)
+	}
+	return subscriptionSecret(subscription), nil
+}
+
+// webhookRetryDelay returns the exponential backoff before the next attempt, capped at webhookRetryMaxDelay.
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,477 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+type fakeWebhookOutbox struct {
+	domainWebhook.IWebhookRepository
+	enqueued      []*domainWebhook.Delivery
+	succeeded     []string
+	failed        map[string]bool
+	subscriptions []*domainWebhook.Subscription
+	// listed counts ListSubscriptions queries
+	listed int
+	// notify, when set, receives every delivery enqueued from a background goroutine
+	notify chan *domainWebhook.Delivery
+}
+
+func (f *fakeWebhookOutbox) ListSubscriptions(deviceID string) ([]*domainWebhook.Subscription, error) {
+	f.listed++
+	var result []*domainWebhook.Subscription
+	for _, subscription := range f.subscriptions {
+		if subscription.DeviceID == deviceID {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeWebhookOutbox) GetSubscriptionByID(id string) (*domainWebhook.Subscription, error) {
+	for _, subscription := range f.subscriptions {
+		if subscription.ID == id {
+			return subscription, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeWebhookOutbox) EnqueueDelivery(delivery *domainWebhook.Delivery) error {
+	f.enqueued = append(f.enqueued, delivery)
+	if f.notify != nil {
+		f.notify <- delivery
+	}
+	return nil
+}
+
+func (f *fakeWebhookOutbox) MarkDeliverySucceeded(id string) error {
+	f.succeeded = append(f.succeeded, id)
+	return nil
+}
+
+func (f *fakeWebhookOutbox) MarkDeliveryFailed(id string, _ string, _ time.Time, dead bool) error {
+	if f.failed == nil {
+		f.failed = map[string]bool{}
+	}
+	f.failed[id] = dead
+	return nil
+}
+
+func useFakeWebhookOutbox(t *testing.T) *fakeWebhookOutbox {
+	t.Helper()
+	fake := &fakeWebhookOutbox{}
+	original := webhookStore
+	webhookStore = fake
+	t.Cleanup(func() { webhookStore = original })
+	return fake
+}
+
+func TestForwardPayloadToConfiguredWebhooks_EnqueuesIntoOutbox(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://one", "https://two"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(context.Context, map[string]any, string) error {
+		t.Fatal("submitWebhookFn should not be invoked when the outbox is configured")
+		return nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	payload := map[string]any{"event": "message", "device_id": "628123@s.whatsapp.net", "payload": map[string]any{"body": "<hi>"}}
+	if err := forwardPayloadToConfiguredWebhooks(context.Background(), payload, "message event"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(fake.enqueued) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(fake.enqueued))
+	}
+	for _, delivery := range fake.enqueued {
+		if delivery.Event != "message" {
+			t.Errorf("expected event from payload, got %q", delivery.Event)
+		}
+		if delivery.DeviceID != "628123@s.whatsapp.net" {
+			t.Errorf("unexpected device id %q", delivery.DeviceID)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(delivery.Payload, &decoded); err != nil {
+			t.Fatalf("payload is not valid JSON: %v", err)
+		}
+	}
+}
+
+func TestForwardPayloadToConfiguredWebhooks_DeviceSubscriptions(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+	fake.subscriptions = []*domainWebhook.Subscription{
+		{ID: "all", DeviceID: "sales", URL: "https://all", Enabled: true},
+		{ID: "acks", DeviceID: "sales", URL: "https://acks", Events: []string{"message.ack"}, Enabled: true},
+		{ID: "off", DeviceID: "sales", URL: "https://off", Enabled: false},
+		{ID: "other", DeviceID: "support", URL: "https://other", Enabled: true},
+	}
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = nil
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	ctx := ContextWithDevice(context.Background(), NewDeviceInstance("sales", nil, nil))
+	ctx, ok := withWebhookTargets(ctx)
+	if !ok {
+		t.Fatal("expected device subscriptions to count as webhook targets")
+	}
+
+	payload := map[string]any{"event": "message", "payload": map[string]any{}}
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "message event"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(fake.enqueued) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(fake.enqueued))
+	}
+	if fake.enqueued[0].URL != "https://all" || fake.enqueued[0].SubscriptionID != "all" {
+		t.Errorf("unexpected delivery %+v", fake.enqueued[0])
+	}
+	if fake.listed != 1 {
+		t.Errorf("expected subscriptions to be loaded once per event, got %d queries", fake.listed)
+	}
+}
+
+func TestWithWebhookTargets_NoTargets(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+	fake.subscriptions = []*domainWebhook.Subscription{
+		{ID: "off", DeviceID: "sales", URL: "https://off", Enabled: false},
+	}
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = nil
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	ctx := ContextWithDevice(context.Background(), NewDeviceInstance("sales", nil, nil))
+	if _, ok := withWebhookTargets(ctx); ok {
+		t.Fatal("expected a disabled subscription not to count as a webhook target")
+	}
+	if _, ok := withWebhookTargets(context.Background()); ok {
+		t.Fatal("expected no webhook targets without a device or global webhook")
+	}
+}
+
+func TestHandleWebhookForward_DeviceSubscriptionWithoutGlobalWebhook(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+	fake.notify = make(chan *domainWebhook.Delivery, 1)
+	fake.subscriptions = []*domainWebhook.Subscription{
+		{ID: "all", DeviceID: "sales", URL: "https://all", Enabled: true},
+	}
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = nil
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	originalLog := log
+	log = waLog.Noop
+	defer func() { log = originalLog }()
+
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:   types.NewJID("6289685028129", types.DefaultUserServer),
+				Sender: types.NewJID("6289685028129", types.DefaultUserServer),
+			},
+			ID:        "3EB0A1",
+			Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Message: &waE2E.Message{Conversation: proto.String("hello")},
+	}
+	ctx := ContextWithDevice(context.Background(), NewDeviceInstance("sales", nil, nil))
+	handleWebhookForward(ctx, evt, nil)
+
+	select {
+	case delivery := <-fake.notify:
+		if delivery.Event != "message" || delivery.SubscriptionID != "all" {
+			t.Errorf("unexpected delivery %+v", delivery)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the message event to be enqueued for the device subscription")
+	}
+}
+
+func TestForwardPayloadToConfiguredWebhooks_GlobalWhitelistUsesPayloadEvent(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+
+	originalWebhooks, originalEvents := config.WhatsappWebhook, config.WhatsappWebhookEvents
+	config.WhatsappWebhook = []string{"https://one"}
+	config.WhatsappWebhookEvents = []string{"message.ack"}
+	defer func() {
+		config.WhatsappWebhook, config.WhatsappWebhookEvents = originalWebhooks, originalEvents
+	}()
+
+	for _, event := range []string{"message", "message.ack"} {
+		payload := map[string]any{"event": event}
+		if err := forwardPayloadToConfiguredWebhooks(context.Background(), payload, event+" event"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if len(fake.enqueued) != 1 || fake.enqueued[0].Event != "message.ack" {
+		t.Fatalf("expected only the whitelisted event to be enqueued, got %+v", fake.enqueued)
+	}
+}
+
+func TestDeliverOutboxEntry_DeadLettersRemovedSubscription(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+
+	originalPost := postWebhookFn
+	postWebhookFn = func(context.Context, []byte, string, string) error {
+		t.Fatal("postWebhookFn should not be invoked for a removed subscription")
+		return nil
+	}
+	defer func() { postWebhookFn = originalPost }()
+
+	deliverOutboxEntry(context.Background(), &domainWebhook.Delivery{ID: "orphan", SubscriptionID: "gone"})
+
+	if !fake.failed["orphan"] {
+		t.Error("expected delivery for removed subscription to be dead-lettered")
+	}
+}
+
+func TestDeliverOutboxEntry_DeadLettersAfterMaxAttempts(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+
+	originalMax := config.WhatsappWebhookMaxAttempts
+	config.WhatsappWebhookMaxAttempts = 3
+	defer func() { config.WhatsappWebhookMaxAttempts = originalMax }()
+
+	originalPost := postWebhookFn
+	postWebhookFn = func(context.Context, []byte, string, string) error { return errors.New("boom") }
+	defer func() { postWebhookFn = originalPost }()
+
+	deliverOutboxEntry(context.Background(), &domainWebhook.Delivery{ID: "retry", Attempts: 1})
+	deliverOutboxEntry(context.Background(), &domainWebhook.Delivery{ID: "dead", Attempts: 2})
+
+	if fake.failed["retry"] {
+		t.Error("expected second attempt to be rescheduled, not dead-lettered")
+	}
+	if !fake.failed["dead"] {
+		t.Error("expected third attempt to be dead-lettered")
+	}
+}
+
+func TestDeliverOutboxEntry_MarksSuccess(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+
+	originalPost := postWebhookFn
+	postWebhookFn = func(context.Context, []byte, string, string) error { return nil }
+	defer func() { postWebhookFn = originalPost }()
+
+	deliverOutboxEntry(context.Background(), &domainWebhook.Delivery{ID: "ok"})
+
+	if len(fake.succeeded) != 1 || fake.succeeded[0] != "ok" {
+		t.Fatalf("expected delivery to be marked delivered, got %v", fake.succeeded)
+	}
+}
+
+func TestDeliverOutboxEntry_ContinuesTraceOfProducingEvent(t *testing.T) {
+	if err := tracing.Setup(context.Background(), ""); err != nil {
+		t.Fatalf("tracing setup: %v", err)
+	}
+	fake := useFakeWebhookOutbox(t)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	eventCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	payload := map[string]any{"event": "message", "device_id": "628123@s.whatsapp.net"}
+	if err := enqueueWebhookDelivery(eventCtx, payload, webhookTarget{url: "https://one"}, "message"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(fake.enqueued) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(fake.enqueued))
+	}
+	delivery := fake.enqueued[0]
+	if want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; delivery.TraceParent != want {
+		t.Fatalf("expected traceparent %q, got %q", want, delivery.TraceParent)
+	}
+
+	var delivered trace.SpanContext
+	originalPost := postWebhookFn
+	postWebhookFn = func(ctx context.Context, _ []byte, _ string, _ string) error {
+		delivered = trace.SpanContextFromContext(ctx)
+		return nil
+	}
+	defer func() { postWebhookFn = originalPost }()
+
+	deliverOutboxEntry(context.Background(), delivery)
+
+	if delivered.TraceID() != traceID || delivered.SpanID() != spanID {
+		t.Fatalf("expected delivery to continue trace %s/%s, got %s/%s", traceID, spanID, delivered.TraceID(), delivered.SpanID())
+	}
+}
+
+// leaseTrackingOutbox counts deliveries that were claimed but not finished yet
+type leaseTrackingOutbox struct {
+	domainWebhook.IWebhookRepository
+	due []*domainWebhook.Delivery
+
+	mu         sync.Mutex
+	inFlight   int
+	maxClaimed int
+	delivered  int
+}
+
+func (o *leaseTrackingOutbox) FetchDueDeliveries(time.Time, int) ([]*domainWebhook.Delivery, error) {
+	return o.due, nil
+}
+
+func (o *leaseTrackingOutbox) ClaimDelivery(string, int, time.Time) (bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.inFlight++
+	o.maxClaimed = max(o.maxClaimed, o.inFlight)
+	return true, nil
+}
+
+func (o *leaseTrackingOutbox) MarkDeliverySucceeded(string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.inFlight--
+	o.delivered++
+	return nil
+}
+
+func TestDispatchDueWebhooks_ClaimsOnlyWhenAWorkerIsFree(t *testing.T) {
+	outbox := &leaseTrackingOutbox{}
+	for i := range webhookDispatchWorkers * 3 {
+		outbox.due = append(outbox.due, &domainWebhook.Delivery{ID: fmt.Sprintf("d%d", i)})
+	}
+	original := webhookStore
+	webhookStore = outbox
+	defer func() { webhookStore = original }()
+
+	originalPost := postWebhookFn
+	postWebhookFn = func(context.Context, []byte, string, string) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	defer func() { postWebhookFn = originalPost }()
+
+	dispatchDueWebhooks(context.Background())
+
+	if outbox.delivered != len(outbox.due) {
+		t.Fatalf("expected %d deliveries, got %d", len(outbox.due), outbox.delivered)
+	}
+	if outbox.maxClaimed > webhookDispatchWorkers {
+		t.Fatalf("expected at most %d leased rows at a time, got %d", webhookDispatchWorkers, outbox.maxClaimed)
+	}
+}
+
+func TestDeliverOutboxEntry_RecordsWebhookMetrics(t *testing.T) {
+	useFakeWebhookOutbox(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	attempts := testutil.ToFloat64(metrics.WebhookAttempts)
+	failures := testutil.ToFloat64(metrics.WebhookFailures)
+
+	deliverOutboxEntry(context.Background(), &domainWebhook.Delivery{ID: "ok", URL: server.URL + "/ok", Payload: []byte(`{}`)})
+	deliverOutboxEntry(context.Background(), &domainWebhook.Delivery{ID: "fail", URL: server.URL + "/fail", Payload: []byte(`{}`)})
+
+	if got := testutil.ToFloat64(metrics.WebhookAttempts) - attempts; got != 2 {
+		t.Errorf("expected 2 attempts to be counted, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.WebhookFailures) - failures; got != 1 {
+		t.Errorf("expected 1 failure to be counted, got %v", got)
+	}
+}
+
+// pendingMediaOutbox reports a pending delivery for the first checks
+type pendingMediaOutbox struct {
+	domainWebhook.IWebhookRepository
+	pendingChecks int
+	checked       []string
+}
+
+func (o *pendingMediaOutbox) HasPendingDelivery(text string) (bool, error) {
+	o.checked = append(o.checked, text)
+	return len(o.checked) <= o.pendingChecks, nil
+}
+
+func TestRemoveWebhookMedia_WaitsForPendingDeliveries(t *testing.T) {
+	storage := mediastorage.NewLocal(t.TempDir())
+	originalStorage, originalDelay, originalPoll := mediastorage.Active(), webhookMediaDelay, webhookMediaPollInterval
+	mediastorage.SetActive(storage)
+	webhookMediaDelay, webhookMediaPollInterval = 0, 0
+	t.Cleanup(func() {
+		mediastorage.SetActive(originalStorage)
+		webhookMediaDelay, webhookMediaPollInterval = originalDelay, originalPoll
+	})
+
+	outbox := &pendingMediaOutbox{pendingChecks: 2}
+	original := webhookStore
+	webhookStore = outbox
+	t.Cleanup(func() { webhookStore = original })
+
+	key := "statics/media/1717243200-photo.jpg"
+	if err := storage.Put(context.Background(), key, strings.NewReader("jpeg"), 4, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+
+	removeWebhookMedia(key)
+	if len(outbox.checked) != 3 || outbox.checked[0] != "1717243200-photo.jpg" {
+		t.Fatalf("expected the media to be kept while deliveries were pending, got checks %v", outbox.checked)
+	}
+	if _, err := storage.Open(context.Background(), key); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected media to be deleted once delivered, got %v", err)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 5 * time.Second},
+		{attempt: 2, want: 10 * time.Second},
+		{attempt: 4, want: 40 * time.Second},
+		{attempt: 20, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := webhookRetryDelay(tt.attempt); got != tt.want {
+			t.Errorf("webhookRetryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
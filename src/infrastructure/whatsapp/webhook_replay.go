@@ -0,0 +1,135 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+	waE2E "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReplayStoredMessage rebuilds the webhook payload of a stored message with the live payload builder
+// and delivers it to url only. The payload carries "replayed": true so consumers can de-duplicate.
+func ReplayStoredMessage(ctx context.Context, client *whatsmeow.Client, message *domainChatStorage.Message, url string) error {
+	evt, err := storedMessageToEvent(message)
+	if err != nil {
+		return err
+	}
+
+	webhookEvent, err := createWebhookEvent(ctx, client, evt)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"event":     webhookEvent.Event,
+		"device_id": webhookEvent.DeviceID,
+		"payload":   webhookEvent.Payload,
+		"replayed":  true,
+	}
+
+	if webhookStore != nil {
+		return enqueueWebhookDelivery(ctx, payload, webhookTarget{url: url, secret: config.WhatsappWebhookSecret}, webhookEvent.Event)
+	}
+	return submitWebhookFn(ctx, payload, url)
+}
+
+// storedMessageToEvent reconstructs the parts of events.Message that buildEventPayload reads.
+// Only what the messages table keeps is restored, so quoted replies and link previews are not replayed.
+func storedMessageToEvent(message *domainChatStorage.Message) (*events.Message, error) {
+	chatJID, err := types.ParseJID(message.ChatJID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chat jid %q: %w", message.ChatJID, err)
+	}
+
+	senderJID := chatJID
+	if message.Sender != "" {
+		if senderJID, err = types.ParseJID(message.Sender); err != nil {
+			return nil, fmt.Errorf("invalid sender jid %q: %w", message.Sender, err)
+		}
+	}
+
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     chatJID,
+				Sender:   senderJID,
+				IsFromMe: message.IsFromMe,
+				IsGroup:  chatJID.Server == types.GroupServer,
+			},
+			ID:        message.ID,
+			Timestamp: message.Timestamp,
+		},
+		Message: storedMessageProto(message),
+	}, nil
+}
+
+func storedMessageProto(message *domainChatStorage.Message) *waE2E.Message {
+	var caption *string
+	if message.Content != "" {
+		caption = proto.String(message.Content)
+	}
+
+	switch message.MediaType {
+	case "image":
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			URL:           proto.String(message.URL),
+			Caption:       caption,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "video":
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			URL:           proto.String(message.URL),
+			Caption:       caption,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "video_note":
+		return &waE2E.Message{PtvMessage: &waE2E.VideoMessage{
+			URL:           proto.String(message.URL),
+			Caption:       caption,
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "audio":
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			URL:           proto.String(message.URL),
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "document":
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			URL:           proto.String(message.URL),
+			Caption:       caption,
+			FileName:      proto.String(message.Filename),
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	case "sticker":
+		return &waE2E.Message{StickerMessage: &waE2E.StickerMessage{
+			URL:           proto.String(message.URL),
+			MediaKey:      message.MediaKey,
+			FileSHA256:    message.FileSHA256,
+			FileEncSHA256: message.FileEncSHA256,
+			FileLength:    proto.Uint64(message.FileLength),
+		}}
+	default:
+		return &waE2E.Message{Conversation: proto.String(message.Content)}
+	}
+}
@@ -0,0 +1,67 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+func TestReplayStoredMessage_EnqueuesReplayedPayload(t *testing.T) {
+	fake := useFakeWebhookOutbox(t)
+
+	message := &domainChatStorage.Message{
+		ID:        "3EB0ABC",
+		ChatJID:   "628123@s.whatsapp.net",
+		Sender:    "628123@s.whatsapp.net",
+		Content:   "hello again",
+		Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if err := ReplayStoredMessage(context.Background(), nil, message, "https://replay"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(fake.enqueued) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(fake.enqueued))
+	}
+	delivery := fake.enqueued[0]
+	if delivery.URL != "https://replay" || delivery.Event != EventTypeMessage {
+		t.Fatalf("unexpected delivery %+v", delivery)
+	}
+
+	var decoded struct {
+		Replayed bool           `json:"replayed"`
+		Payload  map[string]any `json:"payload"`
+	}
+	if err := json.Unmarshal(delivery.Payload, &decoded); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if !decoded.Replayed {
+		t.Error("expected replayed flag to be set")
+	}
+	if decoded.Payload["Message"] != "hello again" || decoded.Payload["ID"] != "3EB0ABC" {
+		t.Errorf("unexpected payload %v", decoded.Payload)
+	}
+}
+
+func TestStoredMessageToEvent_Media(t *testing.T) {
+	evt, err := storedMessageToEvent(&domainChatStorage.Message{
+		ID:        "media",
+		ChatJID:   "120363025246125486@g.us",
+		Sender:    "628123@s.whatsapp.net",
+		Content:   "caption",
+		MediaType: "image",
+		URL:       "https://mmg.whatsapp.net/image.enc",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !evt.Info.IsGroup {
+		t.Error("expected group chat to be detected")
+	}
+	if evt.Message.GetImageMessage().GetCaption() != "caption" {
+		t.Errorf("expected image caption to be restored, got %+v", evt.Message)
+	}
+}
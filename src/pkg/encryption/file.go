@@ -0,0 +1,387 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Sealed files start with fileMagic, the key id, the wrapped data key and a base nonce, followed by
+// the content in chunks of fileChunkSize, each sealed with AES-GCM on its own. The chunk counter is
+// mixed into the nonce and the last chunk is flagged, so chunks cannot be reordered or cut off.
+const (
+	fileMagic     = "WAENC1"
+	fileChunkSize = 64 * 1024
+)
+
+// NewWriter returns a writer that seals everything written to it into w. Close must be called to
+// write the last chunk, it does not close w.
+func (k *Keyring) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	dataKey, wrapped, err := k.newDataKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := fileHeader(k.active, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	baseNonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(baseNonce); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(header, baseNonce...)); err != nil {
+		return nil, err
+	}
+
+	return &sealWriter{w: w, aead: aead, baseNonce: baseNonce, buffer: make([]byte, 0, fileChunkSize)}, nil
+}
+
+// NewReader returns a reader that opens a sealed stream. A stream that was never sealed is passed
+// through unchanged.
+func (k *Keyring) NewReader(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReaderSize(r, fileChunkSize+64)
+	if !hasFileMagic(buffered) {
+		return buffered, nil
+	}
+
+	keyID, wrapped, err := readFileHeader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := k.unwrapDataKey(keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	baseNonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(buffered, baseNonce); err != nil {
+		return nil, fmt.Errorf("truncated encrypted file: %w", err)
+	}
+
+	return &openReader{r: buffered, aead: aead, baseNonce: baseNonce}, nil
+}
+
+// RewrapFile makes a file sealed under the active key: a plain file is sealed, a file sealed under an
+// older key gets a new header with the rewrapped data key while its chunks are copied as they are.
+// It reports false when the file was already up to date.
+func (k *Keyring) RewrapFile(name string) (bool, error) {
+	input, err := os.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer input.Close()
+
+	buffered := bufio.NewReader(input)
+	if !hasFileMagic(buffered) {
+		return true, replaceFile(name, func(output io.Writer) error {
+			writer, err := k.NewWriter(output)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(writer, buffered); err != nil {
+				return err
+			}
+			return writer.Close()
+		})
+	}
+
+	keyID, wrapped, err := readFileHeader(buffered)
+	if err != nil {
+		return false, err
+	}
+	if keyID == k.active {
+		return false, nil
+	}
+	dataKey, err := k.unwrapDataKey(keyID, wrapped)
+	if err != nil {
+		return false, err
+	}
+	if wrapped, err = k.wrapDataKey(dataKey); err != nil {
+		return false, err
+	}
+	header, err := fileHeader(k.active, wrapped)
+	if err != nil {
+		return false, err
+	}
+
+	return true, replaceFile(name, func(output io.Writer) error {
+		if _, err := output.Write(header); err != nil {
+			return err
+		}
+		_, err := io.Copy(output, buffered)
+		return err
+	})
+}
+
+func fileHeader(keyID string, wrapped []byte) ([]byte, error) {
+	if len(keyID) > 255 || len(wrapped) > 255 {
+		return nil, fmt.Errorf("key id or wrapped key too long")
+	}
+	header := append([]byte(fileMagic), byte(len(keyID)))
+	header = append(header, keyID...)
+	header = append(header, byte(len(wrapped)))
+	return append(header, wrapped...), nil
+}
+
+func hasFileMagic(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(fileMagic))
+	return err == nil && string(magic) == fileMagic
+}
+
+func readFileHeader(r *bufio.Reader) (keyID string, wrapped []byte, err error) {
+	if _, err := r.Discard(len(fileMagic)); err != nil {
+		return "", nil, err
+	}
+	keyIDLength, err := r.ReadByte()
+	if err != nil {
+		return "", nil, fmt.Errorf("truncated encrypted file: %w", err)
+	}
+	rawKeyID := make([]byte, keyIDLength)
+	if _, err := io.ReadFull(r, rawKeyID); err != nil {
+		return "", nil, fmt.Errorf("truncated encrypted file: %w", err)
+	}
+	wrappedLength, err := r.ReadByte()
+	if err != nil {
+		return "", nil, fmt.Errorf("truncated encrypted file: %w", err)
+	}
+	wrapped = make([]byte, wrappedLength)
+	if _, err := io.ReadFull(r, wrapped); err != nil {
+		return "", nil, fmt.Errorf("truncated encrypted file: %w", err)
+	}
+	return string(rawKeyID), wrapped, nil
+}
+
+// chunkNonce derives the nonce of a chunk by mixing its counter into the last bytes of the base nonce
+func chunkNonce(baseNonce []byte, counter uint64) []byte {
+	nonce := bytes.Clone(baseNonce)
+	tail := nonce[len(nonce)-8:]
+	binary.BigEndian.PutUint64(tail, binary.BigEndian.Uint64(tail)^counter)
+	return nonce
+}
+
+func chunkAdditionalData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+type sealWriter struct {
+	w         io.Writer
+	aead      cipher.AEAD
+	baseNonce []byte
+	counter   uint64
+	buffer    []byte
+	closed    bool
+}
+
+func (s *sealWriter) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, errors.New("write to closed encrypted writer")
+	}
+	written := 0
+	for len(p) > 0 {
+		// A full chunk is only flushed once more data arrives, the last chunk has to be flagged on Close
+		if len(s.buffer) == fileChunkSize {
+			if err := s.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := min(len(p), fileChunkSize-len(s.buffer))
+		s.buffer = append(s.buffer, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (s *sealWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.flush(true)
+}
+
+func (s *sealWriter) flush(last bool) error {
+	sealed := s.aead.Seal(nil, chunkNonce(s.baseNonce, s.counter), s.buffer, chunkAdditionalData(last))
+	s.counter++
+	s.buffer = s.buffer[:0]
+	_, err := s.w.Write(sealed)
+	return err
+}
+
+type openReader struct {
+	r         *bufio.Reader
+	aead      cipher.AEAD
+	baseNonce []byte
+	counter   uint64
+	plain     []byte
+	done      bool
+}
+
+func (o *openReader) Read(p []byte) (int, error) {
+	for len(o.plain) == 0 {
+		if o.done {
+			return 0, io.EOF
+		}
+		if err := o.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, o.plain)
+	o.plain = o.plain[n:]
+	return n, nil
+}
+
+func (o *openReader) next() error {
+	chunk := make([]byte, fileChunkSize+o.aead.Overhead())
+	n, err := io.ReadFull(o.r, chunk)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("encrypted file is truncated")
+		}
+		return err
+	}
+	chunk = chunk[:n]
+
+	// The chunk is the last one when nothing follows it
+	_, peekErr := o.r.Peek(1)
+	last := errors.Is(peekErr, io.EOF)
+
+	plain, err := o.aead.Open(chunk[:0], chunkNonce(o.baseNonce, o.counter), chunk, chunkAdditionalData(last))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt file: %w", err)
+	}
+	o.counter++
+	o.plain = plain
+	o.done = last
+	return nil
+}
+
+// replaceFile writes a new version of a file next to it and moves it in place, keeping the permissions
+func replaceFile(name string, write func(io.Writer) error) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	output, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(output.Name())
+
+	if err := write(output); err != nil {
+		_ = output.Close()
+		return err
+	}
+	if err := output.Chmod(info.Mode().Perm()); err != nil {
+		_ = output.Close()
+		return err
+	}
+	if err := output.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(output.Name(), name); err != nil {
+		return err
+	}
+	// Keep the modification time, the retention policies expire media by it
+	return os.Chtimes(name, info.ModTime(), info.ModTime())
+}
+
+// WriteFile writes data like os.WriteFile, sealed when encryption at rest is enabled
+func WriteFile(name string, data []byte, perm os.FileMode) error {
+	output, err := CreateFile(name, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := output.Write(data); err != nil {
+		_ = output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// CreateFile creates or truncates a file whose content is sealed when encryption at rest is enabled
+func CreateFile(name string, perm os.FileMode) (io.WriteCloser, error) {
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, err
+	}
+	keyring := Active()
+	if keyring == nil {
+		return file, nil
+	}
+
+	writer, err := keyring.NewWriter(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &sealedFile{WriteCloser: writer, file: file}, nil
+}
+
+type sealedFile struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (s *sealedFile) Close() error {
+	err := s.WriteCloser.Close()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ReadFile reads a whole file like os.ReadFile, decrypting it when it was sealed
+func ReadFile(name string) ([]byte, error) {
+	file, err := OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// OpenFile opens a file for reading, decrypting it when it was sealed
+func OpenFile(name string) (io.ReadCloser, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := Active()
+	if keyring == nil {
+		if hasFileMagic(bufio.NewReaderSize(io.NewSectionReader(file, 0, int64(len(fileMagic))), 16)) {
+			_ = file.Close()
+			return nil, fmt.Errorf("%s is encrypted but no encryption key is configured", name)
+		}
+		return file, nil
+	}
+
+	reader, err := keyring.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{reader, file}, nil
+}
@@ -0,0 +1,290 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// KeySize is the length of the key encryption keys and of the per-value data keys (AES-256)
+const KeySize = 32
+
+// valuePrefix marks a sealed value: enc:v1:<key id>:<wrapped data key>:<nonce and ciphertext>
+const valuePrefix = "enc:v1:"
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Keyring holds the key encryption keys. Every value is sealed with a fresh data key that is wrapped
+// with the active key, so rotating to a new key only has to rewrap the data keys, not re-encrypt data.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// keyringFile is the layout of a key file, the keys are base64 encoded:
+//
+//	{"active_key": "2024-06", "keys": {"2024-06": "…", "2023-11": "…"}}
+type keyringFile struct {
+	ActiveKey string            `json:"active_key"`
+	Keys      map[string]string `json:"keys"`
+}
+
+// NewKeyring creates a keyring that seals with the active key and opens with any of the keys
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the keyring", active)
+	}
+	keyring := &Keyring{active: active, keys: make(map[string][]byte, len(keys))}
+	for id, key := range keys {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid key id %q, use letters, digits, dot, dash and underscore", id)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key %q must be %d bytes, got %d", id, KeySize, len(key))
+		}
+		keyring.keys[id] = bytes.Clone(key)
+	}
+	return keyring, nil
+}
+
+// ParseKey creates a single-key keyring from a base64 encoded key, as given in the configuration
+func ParseKey(id, encoded string) (*Keyring, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+	}
+	return NewKeyring(id, map[string][]byte{id: key})
+}
+
+// LoadKeyring reads a key file, see keyringFile for its layout
+func LoadKeyring(path string) (*Keyring, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("key %q in %s is not valid base64: %w", id, path, err)
+		}
+		keys[id] = key
+	}
+	return NewKeyring(file.ActiveKey, keys)
+}
+
+// GenerateKey returns a random base64 encoded key for a key file
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ActiveKeyID returns the id of the key new values are sealed with
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// KeyIDs returns the ids of every key in the keyring, sorted
+func (k *Keyring) KeyIDs() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Seal encrypts a value with a fresh data key wrapped by the active key
+func (k *Keyring) Seal(plaintext []byte) ([]byte, error) {
+	dataKey, wrapped, err := k.newDataKey()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := sealWithKey(dataKey, plaintext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return envelope{keyID: k.active, wrapped: wrapped, sealed: sealed}.format(), nil
+}
+
+// Open decrypts a sealed value. Values that were never sealed, e.g. written before encryption was
+// enabled, are returned unchanged.
+func (k *Keyring) Open(value []byte) ([]byte, error) {
+	envelope, ok := parseEnvelope(value)
+	if !ok {
+		return value, nil
+	}
+	dataKey, err := k.unwrapDataKey(envelope.keyID, envelope.wrapped)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := openWithKey(dataKey, envelope.sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Rewrap makes a value sealed under the active key: plaintext is sealed, a value sealed under an older
+// key gets its data key rewrapped. It reports false when the value was already up to date.
+func (k *Keyring) Rewrap(value []byte) ([]byte, bool, error) {
+	envelope, ok := parseEnvelope(value)
+	if !ok {
+		sealed, err := k.Seal(value)
+		return sealed, err == nil, err
+	}
+	if envelope.keyID == k.active {
+		return value, false, nil
+	}
+
+	dataKey, err := k.unwrapDataKey(envelope.keyID, envelope.wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	wrapped, err := k.wrapDataKey(dataKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	envelope.keyID, envelope.wrapped = k.active, wrapped
+	return envelope.format(), true, nil
+}
+
+// IsSealed reports whether a value was sealed by a keyring
+func IsSealed(value []byte) bool {
+	_, ok := parseEnvelope(value)
+	return ok
+}
+
+type envelope struct {
+	keyID   string
+	wrapped []byte
+	sealed  []byte
+}
+
+func (e envelope) format() []byte {
+	encoding := base64.RawStdEncoding
+	value := make([]byte, 0, len(valuePrefix)+len(e.keyID)+encoding.EncodedLen(len(e.wrapped))+encoding.EncodedLen(len(e.sealed))+2)
+	value = append(value, valuePrefix...)
+	value = append(value, e.keyID...)
+	value = append(value, ':')
+	value = encoding.AppendEncode(value, e.wrapped)
+	value = append(value, ':')
+	return encoding.AppendEncode(value, e.sealed)
+}
+
+// parseEnvelope splits a sealed value, anything that does not parse is treated as plaintext
+func parseEnvelope(value []byte) (envelope, bool) {
+	if !bytes.HasPrefix(value, []byte(valuePrefix)) {
+		return envelope{}, false
+	}
+	parts := strings.Split(string(value[len(valuePrefix):]), ":")
+	if len(parts) != 3 || !keyIDPattern.MatchString(parts[0]) {
+		return envelope{}, false
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return envelope{}, false
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return envelope{}, false
+	}
+	return envelope{keyID: parts[0], wrapped: wrapped, sealed: sealed}, true
+}
+
+func (k *Keyring) newDataKey() (dataKey, wrapped []byte, err error) {
+	dataKey = make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+	wrapped, err = k.wrapDataKey(dataKey)
+	return dataKey, wrapped, err
+}
+
+// wrapDataKey encrypts a data key with the active key, binding the key id so it cannot be swapped
+func (k *Keyring) wrapDataKey(dataKey []byte) ([]byte, error) {
+	return sealWithKey(k.keys[k.active], dataKey, []byte(k.active))
+}
+
+func (k *Keyring) unwrapDataKey(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("value is sealed with key %q, which is not in the keyring", keyID)
+	}
+	dataKey, err := openWithKey(key, wrapped, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with key %q: %w", keyID, err)
+	}
+	return dataKey, nil
+}
+
+// sealWithKey encrypts with AES-GCM and returns the random nonce followed by the ciphertext
+func sealWithKey(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func openWithKey(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var (
+	activeMu      sync.RWMutex
+	activeKeyring *Keyring
+)
+
+// SetActive sets the keyring used for the chat storage and media files, nil disables encryption
+func SetActive(keyring *Keyring) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	activeKeyring = keyring
+}
+
+// Active returns the configured keyring, or nil when encryption at rest is disabled
+func Active() *Keyring {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	return activeKeyring
+}
@@ -0,0 +1,162 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T, active string, ids ...string) *Keyring {
+	t.Helper()
+	keys := make(map[string][]byte, len(ids))
+	for _, id := range ids {
+		keys[id] = bytes.Repeat([]byte(id[:1]), KeySize)
+	}
+	keyring, err := NewKeyring(active, keys)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	keyring := testKeyring(t, "a", "a")
+
+	sealed, err := keyring.Seal([]byte("hello"))
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "hello")
+
+	opened, err := keyring.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(opened))
+
+	plain, err := keyring.Open([]byte("written before encryption"))
+	require.NoError(t, err)
+	assert.Equal(t, "written before encryption", string(plain))
+
+	_, err = testKeyring(t, "b", "b").Open(sealed)
+	assert.ErrorContains(t, err, `key "a"`)
+}
+
+func TestKeyring_Rewrap(t *testing.T) {
+	sealed, err := testKeyring(t, "a", "a").Seal([]byte("hello"))
+	require.NoError(t, err)
+
+	rotated := testKeyring(t, "b", "a", "b")
+	rewrapped, changed, err := rotated.Rewrap(sealed)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// Once rewrapped the retired key is no longer needed
+	opened, err := testKeyring(t, "b", "b").Open(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(opened))
+
+	_, changed, err = rotated.Rewrap(rewrapped)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	sealedPlain, changed, err := rotated.Rewrap([]byte("plain"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, IsSealed(sealedPlain))
+}
+
+func TestNewKeyring_Validation(t *testing.T) {
+	_, err := NewKeyring("missing", map[string][]byte{"a": make([]byte, KeySize)})
+	assert.Error(t, err)
+	_, err = NewKeyring("a", map[string][]byte{"a": make([]byte, 16)})
+	assert.Error(t, err)
+	_, err = ParseKey("default", "not base64!")
+	assert.Error(t, err)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	first, err := GenerateKey()
+	require.NoError(t, err)
+	second, err := GenerateKey()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	content := `{"active_key": "2024-06", "keys": {"2024-06": "` + first + `", "2023-11": "` + second + `"}}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	keyring, err := LoadKeyring(path)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06", keyring.ActiveKeyID())
+	assert.Equal(t, []string{"2023-11", "2024-06"}, keyring.KeyIDs())
+}
+
+func TestKeyring_FileRoundTrip(t *testing.T) {
+	keyring := testKeyring(t, "a", "a")
+	for _, size := range []int{0, 10, fileChunkSize, 3*fileChunkSize + 17} {
+		content := make([]byte, size)
+		_, _ = rand.Read(content)
+
+		var sealed bytes.Buffer
+		writer, err := keyring.NewWriter(&sealed)
+		require.NoError(t, err)
+		_, err = writer.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		if size > 0 {
+			assert.NotContains(t, sealed.String(), string(content[:min(size, 64)]))
+		}
+
+		reader, err := keyring.NewReader(&sealed)
+		require.NoError(t, err)
+		opened, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, content, opened, "size %d", size)
+	}
+}
+
+func TestKeyring_FileDetectsTruncation(t *testing.T) {
+	keyring := testKeyring(t, "a", "a")
+	var sealed bytes.Buffer
+	writer, err := keyring.NewWriter(&sealed)
+	require.NoError(t, err)
+	_, err = writer.Write(make([]byte, 2*fileChunkSize+5))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	// Cut off the last chunk, the chunk before it was not flagged as the last one
+	truncated := sealed.Bytes()[:sealed.Len()-5-16]
+	reader, err := keyring.NewReader(bytes.NewReader(truncated))
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Error(t, err)
+}
+
+func TestKeyring_RewrapFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("jpeg"), 0640))
+
+	old := testKeyring(t, "a", "a")
+	changed, err := old.RewrapFile(path)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	rotated := testKeyring(t, "b", "a", "b")
+	changed, err = rotated.RewrapFile(path)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = rotated.RewrapFile(path)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	SetActive(testKeyring(t, "b", "b"))
+	t.Cleanup(func() { SetActive(nil) })
+	content, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(content))
+}
@@ -0,0 +1,119 @@
+package mediastorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
+)
+
+// Local stores objects as files below a root folder, keys are paths relative to it. Files are sealed
+// when encryption at rest is enabled.
+type Local struct {
+	root   string
+	signer *URLSigner
+}
+
+// NewLocal creates a local storage, an empty root means the working directory
+func NewLocal(root string) *Local {
+	return &Local{root: root}
+}
+
+// NewSignedLocal creates a local storage whose URLs are signed and expire
+func NewSignedLocal(root string, signer *URLSigner) *Local {
+	return &Local{root: root, signer: signer}
+}
+
+func (l *Local) path(key string) (string, error) {
+	name := filepath.FromSlash(path.Clean(key))
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(l.root, name), nil
+}
+
+func (l *Local) Put(_ context.Context, key string, content io.Reader, _ int64, _ string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	file, err := encryption.CreateFile(name, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (l *Local) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.OpenFile(name)
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) List(_ context.Context, prefix string) ([]Object, error) {
+	folder, err := l.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	err = filepath.WalkDir(folder, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Removed while walking
+			return nil
+		}
+		relative, err := filepath.Rel(filepath.Clean(l.root), name)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: filepath.ToSlash(relative), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+// URL returns the key itself, local files are served by the /statics route below the base path. With a
+// signer the key carries the expires and signature parameters the route checks.
+func (l *Local) URL(_ context.Context, key string) (string, error) {
+	if l.signer != nil {
+		return l.signer.Sign(key), nil
+	}
+	return path.Clean(key), nil
+}
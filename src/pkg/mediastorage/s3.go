@@ -0,0 +1,334 @@
+package mediastorage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+// S3Config configures an S3 compatible object storage such as AWS S3 or MinIO
+type S3Config struct {
+	// Endpoint is the base URL of the service, empty means AWS S3 in Region
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// Prefix is prepended to every object key, e.g. "whatsapp/"
+	Prefix string
+	// PathStyle addresses the bucket as a path (endpoint/bucket/key) instead of a subdomain, MinIO needs it
+	PathStyle bool
+	// URLExpiry is how long the signed URLs returned by URL stay valid
+	URLExpiry time.Duration
+}
+
+// S3 stores objects in an S3 compatible bucket, requests are signed with AWS signature version 4
+type S3 struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3 creates an S3 storage, it does not contact the service
+func NewS3(config S3Config) (*S3, error) {
+	if config.Bucket == "" || config.AccessKey == "" || config.SecretKey == "" {
+		return nil, fmt.Errorf("s3 bucket, access key and secret key are required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	if config.URLExpiry <= 0 {
+		config.URLExpiry = time.Hour
+	}
+	// Signed URLs cannot be valid for longer than a week
+	config.URLExpiry = min(config.URLExpiry, 7*24*time.Hour)
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+
+	return &S3{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	request, err := s.newRequest(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	request.ContentLength = size
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	response, err := s.do(request)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	request, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.do(request)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	request, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	response, err := s.do(request)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// s3ListResult is the part of a ListObjectsV2 response List reads
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List pages through ListObjectsV2, the keys it returns have the configured prefix removed
+func (s *S3) List(ctx context.Context, prefix string) ([]Object, error) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var objects []Object
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", s.config.Prefix+prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		target := s.bucketURL()
+		target.RawQuery = canonicalQueryString(query)
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		s.sign(request)
+		response, err := s.do(request)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(response.Body).Decode(&result)
+		_ = response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("s3 list %s: %w", prefix, err)
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, Object{
+				Key:     strings.TrimPrefix(content.Key, s.config.Prefix),
+				Size:    content.Size,
+				ModTime: content.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// URL returns a presigned GET URL, anyone holding it can download the object until it expires
+func (s *S3) URL(_ context.Context, key string) (string, error) {
+	target := s.objectURL(key)
+	now := s.now().UTC()
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.config.AccessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(s.config.URLExpiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := canonicalQueryString(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		target.EscapedPath(),
+		canonicalQuery,
+		"host:" + target.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	target.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(now, canonicalRequest)
+	return target.String(), nil
+}
+
+// objectURL returns the URL of an object with its path escaped the way signature version 4 expects
+func (s *S3) objectURL(key string) *url.URL {
+	target := s.bucketURL()
+	objectPath := s.config.Prefix + strings.TrimPrefix(key, "/")
+	target.Path += objectPath
+	target.RawPath += uriEncode(objectPath, false)
+	return target
+}
+
+// bucketURL returns the URL of the bucket, ending in a slash
+func (s *S3) bucketURL() *url.URL {
+	target := *s.endpoint
+	basePath := strings.TrimSuffix(target.Path, "/")
+	if s.config.PathStyle {
+		basePath += "/" + s.config.Bucket
+	} else {
+		target.Host = s.config.Bucket + "." + target.Host
+	}
+
+	target.Path = basePath + "/"
+	target.RawPath = uriEncode(basePath, false) + "/"
+	target.RawQuery = ""
+	return &target
+}
+
+func (s *S3) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(request)
+	return request, nil
+}
+
+// sign adds the Authorization header. The payload is not hashed, so uploads can be streamed.
+func (s *S3) sign(request *http.Request) {
+	now := s.now().UTC()
+	request.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	request.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		canonicalQueryString(request.URL.Query()),
+		"host:" + request.URL.Host + "\n" +
+			"x-amz-content-sha256:" + s3UnsignedPayload + "\n" +
+			"x-amz-date:" + now.Format(s3TimeFormat) + "\n",
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKey, s.scope(now), signedHeaders, s.signature(now, canonicalRequest)))
+}
+
+func (s *S3) do(request *http.Request) (*http.Response, error) {
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", request.Method, request.URL.Path, err)
+	}
+	if response.StatusCode < 300 {
+		return response, nil
+	}
+
+	defer response.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("s3 object %s: %w", request.URL.Path, fs.ErrNotExist)
+	}
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", request.Method, request.URL.Path, response.Status, strings.TrimSpace(string(detail)))
+}
+
+func (s *S3) scope(now time.Time) string {
+	return now.Format(s3DateFormat) + "/" + s.config.Region + "/s3/aws4_request"
+}
+
+func (s *S3) signature(now time.Time, canonicalRequest string) string {
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := s3Algorithm + "\n" + now.Format(s3TimeFormat) + "\n" + s.scope(now) + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQueryString sorts and escapes query parameters as signature version 4 requires
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but the unreserved characters, and the slash unless encodeSlash is set
+func uriEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
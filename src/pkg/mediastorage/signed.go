@@ -0,0 +1,58 @@
+package mediastorage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidSignature is returned for links that were not signed with the server's secret or have expired
+var ErrInvalidSignature = errors.New("invalid or expired link")
+
+// URLSigner signs the links of local files with HMAC-SHA256, so they can be served without basic auth
+// to whoever received them, until they expire
+type URLSigner struct {
+	secret []byte
+	expiry time.Duration
+	now    func() time.Time
+}
+
+// NewURLSigner creates a signer whose links stay valid for expiry
+func NewURLSigner(secret []byte, expiry time.Duration) *URLSigner {
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	return &URLSigner{secret: secret, expiry: expiry, now: time.Now}
+}
+
+// Sign returns the key with the expires and signature query parameters appended
+func (s *URLSigner) Sign(key string) string {
+	key = path.Clean(key)
+	expires := strconv.FormatInt(s.now().Add(s.expiry).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.signature(key, expires)}}
+	return key + "?" + query.Encode()
+}
+
+// Verify checks the expires and signature parameters of a signed link to key
+func (s *URLSigner) Verify(key, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+	expected := s.signature(path.Clean(key), expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *URLSigner) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package mediastorage
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Storage keeps the files the API hands out: downloaded media and QR codes. Keys are slash separated
+// paths such as statics/media/6289685028129/2024-06-01/photo.jpg.
+type Storage interface {
+	// Put stores content under key, replacing an existing object
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	// Open returns the content stored under key, an error matching fs.ErrNotExist when there is none
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object, deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// List returns the objects below the folder prefix, in no particular order
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// URL returns where clients can fetch the object: a signed URL for object storage, or the path
+	// below the server's base path for local files
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// Object describes a stored object as returned by List
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+var (
+	activeMu      sync.RWMutex
+	activeStorage Storage = NewLocal("")
+)
+
+// SetActive sets the storage used for media and QR codes
+func SetActive(storage Storage) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	activeStorage = storage
+}
+
+// Active returns the configured storage, the local disk unless another one was set
+func Active() Storage {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	return activeStorage
+}
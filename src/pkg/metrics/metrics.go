@@ -0,0 +1,173 @@
+// Package metrics holds the Prometheus collectors exposed on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strings"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+const namespace = "gowa"
+
+// Registry holds every collector of this package together with the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	MessagesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_sent_total",
+		Help:      "Messages sent through WhatsApp, by device and message type.",
+	}, []string{"device", "type"})
+
+	MessagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_received_total",
+		Help:      "Messages received from WhatsApp, by device and message type.",
+	}, []string{"device", "type"})
+
+	WebhookAttempts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_attempts_total",
+		Help:      "Webhook POST attempts, retries included.",
+	})
+
+	WebhookFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_failures_total",
+		Help:      "Webhook POST attempts that failed or got a non-2xx response.",
+	})
+
+	WebhookDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "webhook_duration_seconds",
+		Help:      "Duration of a single webhook POST attempt.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	HistorySyncBatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "history_sync_batches_total",
+		Help:      "History sync batches processed, by device and sync type.",
+	}, []string{"device", "type"})
+
+	MediaDownloadBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "media_download_bytes",
+		Help:      "Size of downloaded media files, by MIME type family.",
+		// 16 KiB up to 256 MiB
+		Buckets: prometheus.ExponentialBuckets(16*1024, 4, 8),
+	}, []string{"type"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of REST requests, by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		MessagesSent,
+		MessagesReceived,
+		WebhookAttempts,
+		WebhookFailures,
+		WebhookDuration,
+		HistorySyncBatches,
+		MediaDownloadBytes,
+		HTTPRequestDuration,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// MessageType names the content of a message for the type label
+func MessageType(msg *waE2E.Message) string {
+	switch {
+	case msg == nil:
+		return "unknown"
+	case msg.GetConversation() != "" || msg.GetExtendedTextMessage() != nil:
+		return "text"
+	case msg.GetImageMessage() != nil:
+		return "image"
+	case msg.GetVideoMessage() != nil:
+		return "video"
+	case msg.GetPtvMessage() != nil:
+		return "video_note"
+	case msg.GetAudioMessage() != nil:
+		return "audio"
+	case msg.GetDocumentMessage() != nil:
+		return "document"
+	case msg.GetStickerMessage() != nil:
+		return "sticker"
+	case msg.GetContactMessage() != nil:
+		return "contact"
+	case msg.GetLocationMessage() != nil, msg.GetLiveLocationMessage() != nil:
+		return "location"
+	case msg.GetPollCreationMessage() != nil, msg.GetPollCreationMessageV3() != nil:
+		return "poll"
+	case msg.GetReactionMessage() != nil:
+		return "reaction"
+	case msg.GetProtocolMessage() != nil:
+		return "protocol"
+	default:
+		return "other"
+	}
+}
+
+// MediaType reduces a MIME type to its family, image/jpeg becomes image, to keep the label set small
+func MediaType(mimeType string) string {
+	if family, _, ok := strings.Cut(mimeType, "/"); ok && family != "" {
+		return family
+	}
+	return "unknown"
+}
+
+// deviceStateCollector reports the connection state of every device at scrape time
+type deviceStateCollector struct {
+	desc   *prometheus.Desc
+	states func() map[string]domainDevice.DeviceState
+}
+
+var deviceStates = []domainDevice.DeviceState{
+	domainDevice.DeviceStateDisconnected,
+	domainDevice.DeviceStateConnecting,
+	domainDevice.DeviceStateConnected,
+	domainDevice.DeviceStateLoggedIn,
+}
+
+// RegisterDeviceStates exposes gowa_device_state, set to 1 for the current state of each device and 0 for
+// the others. states is called on every scrape and returns the state by device ID.
+func RegisterDeviceStates(states func() map[string]domainDevice.DeviceState) error {
+	return Registry.Register(&deviceStateCollector{
+		desc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "device_state"),
+			"Connection state of each device, 1 for the current state.", []string{"device", "state"}, nil),
+		states: states,
+	})
+}
+
+func (c *deviceStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *deviceStateCollector) Collect(ch chan<- prometheus.Metric) {
+	for device, current := range c.states() {
+		for _, state := range deviceStates {
+			value := 0.0
+			if state == current {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, value, device, string(state))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMessageType(t *testing.T) {
+	tests := []struct {
+		msg  *waE2E.Message
+		want string
+	}{
+		{nil, "unknown"},
+		{&waE2E.Message{Conversation: proto.String("hi")}, "text"},
+		{&waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String("https://example.com")}}, "text"},
+		{&waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}, "image"},
+		{&waE2E.Message{PtvMessage: &waE2E.VideoMessage{}}, "video_note"},
+		{&waE2E.Message{LiveLocationMessage: &waE2E.LiveLocationMessage{}}, "location"},
+		{&waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{}}, "reaction"},
+		{&waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{}}, "protocol"},
+		{&waE2E.Message{}, "other"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MessageType(tt.msg))
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	assert.Equal(t, "image", MediaType("image/jpeg"))
+	assert.Equal(t, "application", MediaType("application/pdf"))
+	assert.Equal(t, "unknown", MediaType(""))
+	assert.Equal(t, "unknown", MediaType("/pdf"))
+}
+
+func TestDeviceStateCollector(t *testing.T) {
+	collector := &deviceStateCollector{
+		desc: prometheus.NewDesc("gowa_device_state", "Connection state of each device, 1 for the current state.",
+			[]string{"device", "state"}, nil),
+		states: func() map[string]domainDevice.DeviceState {
+			return map[string]domainDevice.DeviceState{"sales": domainDevice.DeviceStateLoggedIn}
+		},
+	}
+
+	expected := `
+# HELP gowa_device_state Connection state of each device, 1 for the current state.
+# TYPE gowa_device_state gauge
+gowa_device_state{device="sales",state="connected"} 0
+gowa_device_state{device="sales",state="connecting"} 0
+gowa_device_state{device="sales",state="disconnected"} 0
+gowa_device_state{device="sales",state="logged_in"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
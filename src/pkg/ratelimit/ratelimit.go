@@ -0,0 +1,117 @@
+// Package ratelimit implements in-memory token buckets keyed by string.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets that refilled completely are dropped
+const sweepInterval = 10 * time.Minute
+
+// Request asks for one token from the bucket under Key. The bucket holds PerMinute tokens and refills at
+// PerMinute tokens a minute, so a full minute's budget can be spent at once. PerMinute <= 0 means unlimited.
+type Request struct {
+	Key       string
+	PerMinute int
+}
+
+// Result describes the tightest bucket of a Take: the one that rejected the request, or the one with the
+// fewest tokens left
+type Result struct {
+	Allowed   bool
+	Key       string
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the rejecting bucket holds a token again
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens    float64
+	perMinute int
+	updated   time.Time
+}
+
+// Limiter holds the buckets, it is safe for concurrent use
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New creates an empty limiter
+func New() *Limiter {
+	return &Limiter{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// Take takes a token from every requested bucket, or from none of them when any bucket is empty
+func (l *Limiter) Take(requests ...Request) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	result := Result{Allowed: true, Remaining: math.MaxInt}
+	buckets := make([]*bucket, 0, len(requests))
+	for _, request := range requests {
+		if request.PerMinute <= 0 {
+			continue
+		}
+		b := l.refill(request, now)
+		buckets = append(buckets, b)
+
+		if b.tokens < 1 {
+			wait := time.Duration((1 - b.tokens) * float64(time.Minute) / float64(b.perMinute))
+			if result.Allowed || wait > result.RetryAfter {
+				result = Result{Key: request.Key, Limit: b.perMinute, RetryAfter: wait}
+			}
+			continue
+		}
+		if remaining := int(b.tokens) - 1; result.Allowed && remaining < result.Remaining {
+			result.Key, result.Limit, result.Remaining = request.Key, b.perMinute, remaining
+		}
+	}
+
+	if !result.Allowed {
+		return result
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	if result.Remaining == math.MaxInt {
+		result.Remaining = 0
+	}
+	return result
+}
+
+// refill returns the bucket of a request with the tokens earned since it was last used
+func (l *Limiter) refill(request Request, now time.Time) *bucket {
+	b, ok := l.buckets[request.Key]
+	if !ok || b.perMinute != request.PerMinute {
+		b = &bucket{tokens: float64(request.PerMinute), perMinute: request.PerMinute, updated: now}
+		l.buckets[request.Key] = b
+		return b
+	}
+
+	elapsed := now.Sub(b.updated).Minutes()
+	b.tokens = min(float64(b.perMinute), b.tokens+elapsed*float64(b.perMinute))
+	b.updated = now
+	return b
+}
+
+// sweep drops buckets that are full again, they behave exactly like new ones
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Minutes()*float64(b.perMinute) >= float64(b.perMinute) {
+			delete(l.buckets, key)
+		}
+	}
+}
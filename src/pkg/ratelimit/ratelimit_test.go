@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(now *time.Time) *Limiter {
+	limiter := New()
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestLimiter_SpendsBurstThenRefills(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(&now)
+	device := Request{Key: "device:sales", PerMinute: 6}
+
+	for i := 5; i >= 0; i-- {
+		result := limiter.Take(device)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+		assert.Equal(t, 6, result.Limit)
+	}
+
+	result := limiter.Take(device)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 10*time.Second, result.RetryAfter)
+
+	now = now.Add(10 * time.Second)
+	assert.True(t, limiter.Take(device).Allowed)
+	assert.False(t, limiter.Take(device).Allowed)
+}
+
+func TestLimiter_RejectsWithoutSpendingOtherBuckets(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(&now)
+	device := Request{Key: "device:sales", PerMinute: 60}
+	recipient := Request{Key: "recipient:sales/6289685028129", PerMinute: 1}
+
+	result := limiter.Take(device, recipient)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, recipient.Key, result.Key, "the recipient bucket is the tightest")
+	assert.Equal(t, 0, result.Remaining)
+
+	result = limiter.Take(device, recipient)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, recipient.Key, result.Key)
+	assert.Equal(t, time.Minute, result.RetryAfter)
+
+	// The rejected request did not take a token from the device bucket
+	result = limiter.Take(device)
+	assert.Equal(t, 58, result.Remaining)
+}
+
+func TestLimiter_UnlimitedAndChangedLimits(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newTestLimiter(&now)
+
+	for range 100 {
+		assert.True(t, limiter.Take(Request{Key: "key:1", PerMinute: 0}).Allowed)
+	}
+
+	assert.True(t, limiter.Take(Request{Key: "key:1", PerMinute: 1}).Allowed)
+	assert.False(t, limiter.Take(Request{Key: "key:1", PerMinute: 1}).Allowed)
+	// A new limit starts a full bucket
+	assert.True(t, limiter.Take(Request{Key: "key:1", PerMinute: 2}).Allowed)
+
+	now = now.Add(sweepInterval)
+	limiter.Take()
+	assert.Empty(t, limiter.buckets)
+}
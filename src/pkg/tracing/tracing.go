@@ -0,0 +1,127 @@
+// Package tracing sets up OpenTelemetry tracing and holds the span helpers used across the app. Until Setup is
+// called with an endpoint every span is a no-op.
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/XSAM/otelsql"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/aldinokemal/go-whatsapp-web-multidevice"
+	serviceName         = "gowa"
+	defaultTracesPath   = "/v1/traces"
+)
+
+// Setup exports spans over OTLP/HTTP to endpoint, e.g. http://localhost:4318. An empty endpoint leaves tracing
+// disabled. The W3C trace context propagator is installed either way so incoming traceparent headers are kept.
+// Spans are exported in batches every few seconds.
+func Setup(ctx context.Context, endpoint string) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if endpoint == "" {
+		return nil
+	}
+
+	target, err := url.Parse(endpoint)
+	if err != nil || target.Host == "" {
+		return fmt.Errorf("invalid tracing endpoint %q, expected a URL such as http://localhost:4318", endpoint)
+	}
+	if target.Path == "" || target.Path == "/" {
+		target.Path = defaultTracesPath
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(target.String()))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(serviceName), semconv.ServiceVersion(config.AppVersion)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to describe tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return nil
+}
+
+// Start opens a span as a child of the span in ctx, if any
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End marks the span failed when err is set and ends it. Call it from a deferred closure so it sees the named
+// error result of the function.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Call runs fn in a client span named after the remote call, such as whatsmeow.SendMessage
+func Call[T any](ctx context.Context, name string, fn func(context.Context) (T, error)) (T, error) {
+	ctx, span := Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	result, err := fn(ctx)
+	End(span, err)
+	return result, err
+}
+
+// Run is Call for remote calls that only return an error
+func Run(ctx context.Context, name string, fn func(context.Context) error) error {
+	_, err := Call(ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// Extract continues the trace of an incoming request that carries a traceparent header
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject writes the trace context of ctx into outgoing HTTP headers as traceparent and tracestate
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Detach keeps the span of ctx but drops its deadline and cancellation, for work that outlives a request
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+// SQLOptions instrument a chat storage connection to a database such as sqlite or postgresql. Only queries run
+// with the context of a traced request get a span, background polling without a parent span is not recorded.
+func SQLOptions(system string) []otelsql.Option {
+	return []otelsql.Option{
+		otelsql.WithAttributes(semconv.DBSystemNameKey.String(system)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+			OmitConnectorConnect: true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	}
+}
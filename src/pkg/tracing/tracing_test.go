@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	require.NoError(t, Setup(context.Background(), ""))
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestCall_RecordsChildSpans(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, parent := Start(context.Background(), "send.SendText")
+	result, err := Call(ctx, "whatsmeow.SendMessage", func(context.Context) (string, error) {
+		return "sent", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "sent", result)
+
+	failure := errors.New("not connected")
+	assert.ErrorIs(t, Run(ctx, "whatsmeow.MarkRead", func(context.Context) error { return failure }), failure)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	for _, span := range spans[:2] {
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "whatsmeow.MarkRead", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "not connected", spans[1].Status().Description)
+}
+
+func TestInjectExtract_PropagatesTraceparent(t *testing.T) {
+	recordSpans(t)
+
+	ctx, span := Start(context.Background(), "webhook POST")
+	defer span.End()
+
+	header := http.Header{}
+	Inject(ctx, header)
+	require.NotEmpty(t, header.Get("traceparent"))
+
+	remote := trace.SpanContextFromContext(Extract(context.Background(), header))
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.True(t, remote.IsRemote())
+}
+
+func TestDetach_KeepsSpanWithoutDeadline(t *testing.T) {
+	recordSpans(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := Start(ctx, "audit")
+	defer span.End()
+	cancel()
+
+	detached := Detach(ctx)
+	assert.NoError(t, detached.Err())
+	assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(detached))
+}
+
+func TestSetup_RejectsInvalidEndpoint(t *testing.T) {
+	assert.Error(t, Setup(context.Background(), "localhost:4318"))
+}
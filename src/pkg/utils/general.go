@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	_ "image/gif"  // Register GIF format
@@ -20,6 +21,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
 	"github.com/sirupsen/logrus"
 	_ "golang.org/x/image/webp" // Register WebP format
 )
@@ -44,6 +46,23 @@ func RemoveFile(delaySecond int, paths ...string) error {
 	return nil
 }
 
+// RemoveMedia deletes files from the media storage after a delay, like RemoveFile does for local files
+func RemoveMedia(delaySecond int, keys ...string) error {
+	if delaySecond > 0 {
+		time.Sleep(time.Duration(delaySecond) * time.Second)
+	}
+
+	for _, key := range keys {
+		if key != "" {
+			if err := mediastorage.Active().Delete(context.Background(), key); err != nil {
+				logrus.Warnf("Failed to auto-delete media %s: %v", key, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // CreateFolder create new folder and sub folder if not exist
 func CreateFolder(folderPath ...string) error {
 	for _, folder := range folderPath {
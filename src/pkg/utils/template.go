@@ -0,0 +1,30 @@
+package utils
+
+import "regexp"
+
+// templatePlaceholder matches {{name}} placeholders, allowing spaces inside the braces
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// TemplateVariables returns the distinct placeholder names used in text, in order of first appearance
+func TemplateVariables(text string) []string {
+	var names []string
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+		names = append(names, match[1])
+	}
+	return UniqueStrings(names)
+}
+
+// RenderTemplate replaces {{name}} placeholders with values from variables.
+// Placeholders without a value are left untouched and their names are returned as missing.
+func RenderTemplate(text string, variables map[string]string) (string, []string) {
+	var missing []string
+	rendered := templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		missing = append(missing, name)
+		return placeholder
+	})
+	return rendered, UniqueStrings(missing)
+}
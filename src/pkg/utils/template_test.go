@@ -0,0 +1,51 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		variables map[string]string
+		want      string
+		missing   []string
+	}{
+		{
+			name:      "should replace every placeholder",
+			text:      "Hi {{name}}, order {{ order_id }} for {{name}} shipped",
+			variables: map[string]string{"name": "Ana", "order_id": "A-1"},
+			want:      "Hi Ana, order A-1 for Ana shipped",
+			missing:   []string{},
+		},
+		{
+			name:      "should keep and report missing placeholders",
+			text:      "Hi {{name}}, your code is {{code}}",
+			variables: map[string]string{"name": "Ana"},
+			want:      "Hi Ana, your code is {{code}}",
+			missing:   []string{"code"},
+		},
+		{
+			name:    "should leave text without placeholders unchanged",
+			text:    "Hello {name}",
+			want:    "Hello {name}",
+			missing: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := utils.RenderTemplate(tt.text, tt.variables)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.missing, missing)
+		})
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	assert.Equal(t, []string{"name", "order_id"}, utils.TemplateVariables("{{name}} {{order_id}} {{ name }}"))
+}
@@ -1,13 +1,13 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"mime"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -21,6 +21,9 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"go.mau.fi/whatsmeow"
 )
 
@@ -555,9 +558,13 @@ func FormatJID(jid string) types.JID {
 
 // ExtractedMedia represents extracted media information
 type ExtractedMedia struct {
+	// MediaPath is the key of the file in the media storage, a path relative to the working directory for local disk
 	MediaPath string `json:"media_path"`
-	MimeType  string `json:"mime_type"`
-	Caption   string `json:"caption"`
+	// MediaURL is where clients fetch the file: a signed URL for object storage, MediaPath for local disk
+	MediaURL string `json:"media_url"`
+	MimeType string `json:"mime_type"`
+	Caption  string `json:"caption"`
+	FileSize int64  `json:"file_size"`
 }
 
 // ExtractMedia is a helper function to extract media from whatsapp
@@ -567,7 +574,9 @@ func ExtractMedia(ctx context.Context, client *whatsmeow.Client, storageLocation
 		return extractedMedia, nil
 	}
 
-	data, err := client.Download(ctx, mediaFile)
+	data, err := tracing.Call(ctx, "whatsmeow.Download", func(ctx context.Context) ([]byte, error) {
+		return client.Download(ctx, mediaFile)
+	})
 	if err != nil {
 		return extractedMedia, err
 	}
@@ -600,11 +609,15 @@ func ExtractMedia(ctx context.Context, client *whatsmeow.Client, storageLocation
 	extension := determineMediaExtension(originalFilename, extractedMedia.MimeType)
 
 	extractedMedia.MediaPath = fmt.Sprintf("%s/%d-%s%s", storageLocation, time.Now().Unix(), uuid.NewString(), extension)
-	err = os.WriteFile(extractedMedia.MediaPath, data, 0600)
+	extractedMedia.FileSize = int64(len(data))
+	metrics.MediaDownloadBytes.WithLabelValues(metrics.MediaType(extractedMedia.MimeType)).Observe(float64(extractedMedia.FileSize))
+	storage := mediastorage.Active()
+	err = storage.Put(ctx, extractedMedia.MediaPath, bytes.NewReader(data), extractedMedia.FileSize, extractedMedia.MimeType)
 	if err != nil {
 		return extractedMedia, err
 	}
-	return extractedMedia, nil
+	extractedMedia.MediaURL, err = storage.URL(ctx, extractedMedia.MediaPath)
+	return extractedMedia, err
 }
 
 // SanitizePhone sanitizes phone number by adding appropriate WhatsApp suffix
@@ -621,7 +634,7 @@ func SanitizePhone(phone *string) {
 }
 
 // IsOnWhatsapp checks if a number is registered on WhatsApp
-func IsOnWhatsapp(client *whatsmeow.Client, jid string) bool {
+func IsOnWhatsapp(ctx context.Context, client *whatsmeow.Client, jid string) bool {
 	// only check if the jid is a user with @s.whatsapp.net
 	if strings.Contains(jid, "@s.whatsapp.net") {
 		// Extract phone number from JID and add + prefix for international format
@@ -636,10 +649,12 @@ func IsOnWhatsapp(client *whatsmeow.Client, jid string) bool {
 		}
 
 		// Add timeout to prevent indefinite blocking
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
-		data, err := client.IsOnWhatsApp(ctx, []string{phone})
+		data, err := tracing.Call(ctx, "whatsmeow.IsOnWhatsApp", func(ctx context.Context) ([]types.IsOnWhatsAppResponse, error) {
+			return client.IsOnWhatsApp(ctx, []string{phone})
+		})
 		if err != nil {
 			logrus.Error("Failed to check if user is on whatsapp: ", err)
 			return false
@@ -665,10 +680,10 @@ func IsOnWhatsapp(client *whatsmeow.Client, jid string) bool {
 }
 
 // ValidateJidWithLogin validates JID with login check
-func ValidateJidWithLogin(client *whatsmeow.Client, jid string) (types.JID, error) {
+func ValidateJidWithLogin(ctx context.Context, client *whatsmeow.Client, jid string) (types.JID, error) {
 	MustLogin(client)
 
-	if config.WhatsappAccountValidation && !IsOnWhatsapp(client, jid) {
+	if config.WhatsappAccountValidation && !IsOnWhatsapp(ctx, client, jid) {
 		return types.JID{}, pkgError.InvalidJID(fmt.Sprintf("Phone %s is not on whatsapp", jid))
 	}
 
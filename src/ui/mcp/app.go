@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -90,7 +91,7 @@ func (h *AppHandler) handleLoginWithQR(ctx context.Context, _ mcp.CallToolReques
 		"expires_in":    int(resp.Duration.Seconds()),
 	}
 
-	qrBytes, readErr := os.ReadFile(resp.ImagePath)
+	qrBytes, readErr := readQRImage(ctx, resp.ImagePath)
 	if readErr != nil {
 		return mcp.NewToolResultStructured(structured, fallback), nil
 	}
@@ -101,6 +102,15 @@ func (h *AppHandler) handleLoginWithQR(ctx context.Context, _ mcp.CallToolReques
 	return result, nil
 }
 
+func readQRImage(ctx context.Context, key string) ([]byte, error) {
+	file, err := mediastorage.Active().Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
 func (h *AppHandler) toolLoginWithCode() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_login_with_code",
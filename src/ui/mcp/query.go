@@ -32,6 +32,7 @@ func (h *QueryHandler) AddQueryTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(h.toolListContacts(), h.handleListContacts)
 	mcpServer.AddTool(h.toolListChats(), h.handleListChats)
 	mcpServer.AddTool(h.toolGetChatMessages(), h.handleGetChatMessages)
+	mcpServer.AddTool(h.toolSearchMessages(), h.handleSearchMessages)
 	mcpServer.AddTool(h.toolDownloadMedia(), h.handleDownloadMedia)
 	mcpServer.AddTool(h.toolArchiveChat(), h.handleArchiveChat)
 }
@@ -225,6 +226,74 @@ func (h *QueryHandler) handleGetChatMessages(ctx context.Context, request mcp.Ca
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
 
+func (h *QueryHandler) toolSearchMessages() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_search_messages",
+		mcp.WithDescription("Search stored messages across all chats, best matches first, with matched words wrapped in <mark> tags in each snippet."),
+		mcp.WithTitleAnnotation("Search Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Description("Words to search for; every word must match, also as the start of a longer word."),
+			mcp.Required(),
+		),
+		mcp.WithString("chat_jid",
+			mcp.Description("Only search this chat (e.g., 628123456789@s.whatsapp.net or group@g.us)."),
+		),
+		mcp.WithString("sender",
+			mcp.Description("Only return messages from this phone number or JID."),
+		),
+		mcp.WithString("media_type",
+			mcp.Description("Only return messages with this media: image, video, audio, document or sticker."),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Only return messages sent after this RFC3339 timestamp."),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Only return messages sent before this RFC3339 timestamp."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of messages to return (default 20, max 100)."),
+			mcp.DefaultNumber(20),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of results to skip (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *QueryHandler) handleSearchMessages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return nil, err
+	}
+
+	req := domainChat.SearchMessagesRequest{
+		Query:     query,
+		ChatJID:   strings.TrimSpace(request.GetString("chat_jid", "")),
+		Sender:    strings.TrimSpace(request.GetString("sender", "")),
+		MediaType: strings.TrimSpace(request.GetString("media_type", "")),
+		Limit:     request.GetInt("limit", 20),
+		Offset:    request.GetInt("offset", 0),
+	}
+	if startTime := strings.TrimSpace(request.GetString("start_time", "")); startTime != "" {
+		req.StartTime = &startTime
+	}
+	if endTime := strings.TrimSpace(request.GetString("end_time", "")); endTime != "" {
+		req.EndTime = &endTime
+	}
+
+	resp, err := h.chatService.SearchMessages(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d of %d messages matching %q", len(resp.Data), resp.Pagination.Total, query)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
 func (h *QueryHandler) toolDownloadMedia() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_download_message_media",
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
@@ -35,13 +36,19 @@ func (handler *App) Login(c *fiber.Ctx) error {
 	response, err := handler.Service.Login(c.UserContext(), device.ID())
 	utils.PanicIfNeeded(err)
 
+	// Object storage returns a signed URL, local files are served below the base path
+	qrLink := response.ImageURL
+	if !strings.HasPrefix(qrLink, "http://") && !strings.HasPrefix(qrLink, "https://") {
+		qrLink = fmt.Sprintf("%s://%s%s/%s", c.Protocol(), c.Hostname(), config.AppBasePath, response.ImageURL)
+	}
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
 		Message: "Login success",
 		Results: map[string]any{
 			"device_id":   device.ID(),
-			"qr_link":     fmt.Sprintf("%s://%s%s/%s", c.Protocol(), c.Hostname(), config.AppBasePath, response.ImagePath),
+			"qr_link":     qrLink,
 			"qr_duration": response.Duration,
 		},
 	})
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Audit struct {
+	Service domainAudit.IAuditUsecase
+}
+
+func InitRestAudit(app fiber.Router, service domainAudit.IAuditUsecase) Audit {
+	rest := Audit{Service: service}
+
+	app.Get("/audit", rest.ListEntries)
+
+	return rest
+}
+
+func (controller *Audit) ListEntries(c *fiber.Ctx) error {
+	var request domainAudit.ListRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.List(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get audit log",
+		Results: response,
+	})
+}
@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	domainBackup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/backup"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Backup struct {
+	Service domainBackup.IBackupUsecase
+}
+
+func InitRestBackup(app fiber.Router, service domainBackup.IBackupUsecase) Backup {
+	rest := Backup{Service: service}
+
+	app.Get("/backup", rest.CreateBackup)
+
+	return rest
+}
+
+func (controller *Backup) CreateBackup(c *fiber.Ctx) error {
+	request := domainBackup.BackupRequest{
+		IncludeMedia: c.QueryBool("include_media", true),
+	}
+
+	// The archive can be large, build it on disk and stream it instead of holding it in memory
+	file, err := os.CreateTemp("", "whatsapp-backup-*.zip")
+	utils.PanicIfNeeded(err)
+
+	_, err = controller.Service.Backup(c.UserContext(), request, file)
+	if err == nil {
+		_, err = file.Seek(0, 0)
+	}
+	// The open handle keeps the data readable until the response is sent
+	_ = os.Remove(file.Name())
+	if err != nil {
+		_ = file.Close()
+		utils.PanicIfNeeded(err)
+	}
+
+	c.Attachment(fmt.Sprintf("whatsapp-backup-%s.zip", time.Now().UTC().Format("20060102-150405")))
+	c.Set(fiber.HeaderContentType, "application/zip")
+	return c.SendStream(file)
+}
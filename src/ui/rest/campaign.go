@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"encoding/json"
+
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Campaign struct {
+	Service domainCampaign.ICampaignUsecase
+}
+
+func InitRestCampaign(app fiber.Router, service domainCampaign.ICampaignUsecase) Campaign {
+	rest := Campaign{Service: service}
+
+	app.Post("/campaigns", rest.CreateCampaign)
+	app.Get("/campaigns", rest.ListCampaigns)
+	app.Get("/campaigns/:campaign_id", rest.GetCampaign)
+	app.Get("/campaigns/:campaign_id/progress", rest.GetCampaignProgress)
+	app.Get("/campaigns/:campaign_id/recipients", rest.ListCampaignRecipients)
+	app.Post("/campaigns/:campaign_id/pause", rest.PauseCampaign)
+	app.Post("/campaigns/:campaign_id/resume", rest.ResumeCampaign)
+	app.Post("/campaigns/:campaign_id/cancel", rest.CancelCampaign)
+
+	return rest
+}
+
+func (controller *Campaign) CreateCampaign(c *fiber.Ctx) error {
+	var request domainCampaign.CreateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	// Multipart uploads carry the message template and optional recipients as JSON strings
+	if c.Is("multipart") {
+		if message := c.FormValue("message"); message != "" {
+			request.Message = json.RawMessage(message)
+		}
+		if recipients := c.FormValue("recipients"); recipients != "" {
+			if err := json.Unmarshal([]byte(recipients), &request.Recipients); err != nil {
+				utils.PanicIfNeeded(pkgError.ValidationError("recipients: must be a JSON array of {phone, variables}"))
+			}
+		}
+		file, err := c.FormFile("recipients_csv")
+		if err == nil {
+			request.RecipientsCSV = file
+		}
+	}
+
+	response, err := controller.Service.Create(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign created",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) ListCampaigns(c *fiber.Ctx) error {
+	var request domainCampaign.ListRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.List(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaigns",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) GetCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.Get(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaign",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) GetCampaignProgress(c *fiber.Ctx) error {
+	response, err := controller.Service.Progress(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaign progress",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) ListCampaignRecipients(c *fiber.Ctx) error {
+	var request domainCampaign.ListRecipientsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.CampaignID = c.Params("campaign_id")
+
+	response, err := controller.Service.ListRecipients(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get campaign recipients",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) PauseCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.Pause(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign paused",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) ResumeCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.Resume(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign resumed",
+		Results: response,
+	})
+}
+
+func (controller *Campaign) CancelCampaign(c *fiber.Ctx) error {
+	response, err := controller.Service.Cancel(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("campaign_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Campaign cancelled",
+		Results: response,
+	})
+}
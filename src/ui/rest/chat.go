@@ -1,8 +1,13 @@
 package rest
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 )
@@ -17,6 +22,9 @@ func InitRestChat(app fiber.Router, service domainChat.IChatUsecase) Chat {
 	// Chat endpoints
 	app.Get("/chats", rest.ListChats)
 	app.Get("/chat/:chat_jid/messages", rest.GetChatMessages)
+	app.Get("/chat/:chat_jid/export", rest.ExportChat)
+	app.Post("/chat/:chat_jid/import", rest.ImportChat)
+	app.Get("/messages/search", rest.SearchMessages)
 	app.Post("/chat/:chat_jid/pin", rest.PinChat)
 	app.Post("/chat/:chat_jid/disappearing", rest.SetDisappearingTimer)
 	app.Post("/chat/:chat_jid/archive", rest.ArchiveChat)
@@ -81,6 +89,80 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Chat) ExportChat(c *fiber.Ctx) error {
+	var request domainChat.ExportChatRequest
+
+	request.ChatJID = c.Params("chat_jid")
+	request.Format = c.Query("format", domainChat.ExportFormatText)
+	request.IncludeMedia = c.QueryBool("include_media", false)
+
+	// Parse time filters
+	if startTime := c.Query("start_time"); startTime != "" {
+		request.StartTime = &startTime
+	}
+	if endTime := c.Query("end_time"); endTime != "" {
+		request.EndTime = &endTime
+	}
+
+	response, err := controller.Service.ExportChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	c.Attachment(response.Filename)
+	c.Set(fiber.HeaderContentType, response.ContentType)
+	return c.Send(response.Content)
+}
+
+func (controller *Chat) ImportChat(c *fiber.Ctx) error {
+	var request domainChat.ImportChatRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.ChatJID = c.Params("chat_jid")
+
+	// Senders arrive as a JSON object of transcript name to phone number or JID
+	if senders := c.FormValue("senders"); senders != "" {
+		if err := json.Unmarshal([]byte(senders), &request.Senders); err != nil {
+			utils.PanicIfNeeded(pkgError.ValidationError("senders: must be a JSON object of name to phone number or JID"))
+		}
+	}
+
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		utils.PanicIfNeeded(err)
+		defer file.Close()
+
+		request.FileName = fileHeader.Filename
+		request.File, err = io.ReadAll(file)
+		utils.PanicIfNeeded(err)
+	}
+
+	response, err := controller.Service.ImportChat(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Imported %d messages into chat %s", response.Imported, response.ChatJID),
+		Results: response,
+	})
+}
+
+func (controller *Chat) SearchMessages(c *fiber.Ctx) error {
+	var request domainChat.SearchMessagesRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.SearchMessages(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success search messages",
+		Results: response,
+	})
+}
+
 func (controller *Chat) PinChat(c *fiber.Ctx) error {
 	var request domainChat.PinChatRequest
 
@@ -23,6 +23,8 @@ func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase) Me
 	app.Post("/message/:message_id/star", rest.StarMessage)
 	app.Post("/message/:message_id/unstar", rest.UnstarMessage)
 	app.Get("/message/:message_id/download", rest.DownloadMedia)
+	app.Get("/message/:message_id/status", rest.GetMessageStatus)
+	app.Get("/message/:message_id/poll-results", rest.GetPollResults)
 	return rest
 }
 
@@ -182,3 +184,33 @@ func (controller *Message) DownloadMedia(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Message) GetMessageStatus(c *fiber.Ctx) error {
+	var request domainMessage.MessageStatusRequest
+	request.MessageID = c.Params("message_id")
+
+	response, err := controller.Service.GetMessageStatus(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get message status",
+		Results: response,
+	})
+}
+
+func (controller *Message) GetPollResults(c *fiber.Ctx) error {
+	var request domainMessage.PollResultsRequest
+	request.MessageID = c.Params("message_id")
+
+	response, err := controller.Service.GetPollResults(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get poll results",
+		Results: response,
+	})
+}
@@ -0,0 +1,11 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+func InitRestMetrics(app fiber.Router) {
+	app.Get("/metrics", adaptor.HTTPHandler(metrics.Handler()))
+}
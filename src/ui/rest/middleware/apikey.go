@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const APIKeyHeader = "X-Api-Key"
+
+type apiKeyScope int
+
+const (
+	// scopeDevice routes act on one device, which the key must be allowed to use
+	scopeDevice apiKeyScope = iota
+	// scopeShared routes act on data every device shares
+	scopeShared
+	// scopeGlobal routes act on every device, keys restricted to some devices cannot use them
+	scopeGlobal
+)
+
+type apiKeyRoute struct {
+	read  string
+	write string
+	scope apiKeyScope
+}
+
+// apiKeyRoutes maps the first path segment to the permissions reading (GET) and changing it need.
+// Anything else, such as webhooks, retention, backup and the API keys themselves, needs admin.
+var apiKeyRoutes = map[string]apiKeyRoute{
+	"send":       {domainAPIKey.PermissionSend, domainAPIKey.PermissionSend, scopeDevice},
+	"campaigns":  {domainAPIKey.PermissionSend, domainAPIKey.PermissionSend, scopeDevice},
+	"templates":  {domainAPIKey.PermissionSend, domainAPIKey.PermissionSend, scopeShared},
+	"chats":      {domainAPIKey.PermissionReadChats, domainAPIKey.PermissionReadChats, scopeDevice},
+	"messages":   {domainAPIKey.PermissionReadChats, domainAPIKey.PermissionReadChats, scopeDevice},
+	"chat":       {domainAPIKey.PermissionReadChats, domainAPIKey.PermissionSend, scopeDevice},
+	"message":    {domainAPIKey.PermissionReadChats, domainAPIKey.PermissionSend, scopeDevice},
+	"user":       {domainAPIKey.PermissionReadChats, domainAPIKey.PermissionSend, scopeDevice},
+	"newsletter": {domainAPIKey.PermissionReadChats, domainAPIKey.PermissionSend, scopeDevice},
+	"ws":         {domainAPIKey.PermissionReadChats, domainAPIKey.PermissionReadChats, scopeDevice},
+	"group":      {domainAPIKey.PermissionManageGroups, domainAPIKey.PermissionManageGroups, scopeDevice},
+	"app":        {domainAPIKey.PermissionManageDevices, domainAPIKey.PermissionManageDevices, scopeDevice},
+	"devices":    {domainAPIKey.PermissionManageDevices, domainAPIKey.PermissionManageDevices, scopeDevice},
+}
+
+// APIKeyAuth authenticates requests carrying an API key in the X-Api-Key header or as a bearer token, and
+// checks the key's permissions and devices before DeviceMiddleware resolves the device. Requests without a
+// key pass through to basic auth. A key restricted to a single device becomes the default device.
+func APIKeyAuth(service domainAPIKey.IAPIKeyUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := requestAPIKey(c)
+		if token == "" || service == nil {
+			return c.Next()
+		}
+
+		key, err := service.Authenticate(c.UserContext(), token)
+		if err != nil {
+			var authErr pkgError.AuthError
+			if errors.As(err, &authErr) {
+				return apiKeyError(c, fiber.StatusUnauthorized, "INVALID_API_KEY", authErr.Error())
+			}
+			logrus.Errorf("Failed to authenticate api key: %v", err)
+			return apiKeyError(c, fiber.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "failed to check api key")
+		}
+		c.Locals("api_key", key)
+
+		segments := strings.Split(strings.Trim(strings.TrimPrefix(c.Path(), config.AppBasePath), "/"), "/")
+		if segments[0] == "" {
+			return c.Next()
+		}
+
+		route, ok := apiKeyRoutes[segments[0]]
+		if !ok {
+			route = apiKeyRoute{domainAPIKey.PermissionAdmin, domainAPIKey.PermissionAdmin, scopeGlobal}
+		}
+		permission := route.write
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			permission = route.read
+		}
+		if !key.HasPermission(permission) {
+			return apiKeyError(c, fiber.StatusForbidden, "FORBIDDEN", "api key lacks the "+permission+" permission")
+		}
+
+		if len(key.Devices) == 0 || route.scope == scopeShared {
+			return c.Next()
+		}
+
+		// The device management routes name their device in the path, the list and create routes have none
+		deviceID := ""
+		if segments[0] == "devices" {
+			if len(segments) == 1 {
+				route.scope = scopeGlobal
+			} else {
+				deviceID, _ = url.PathUnescape(segments[1])
+			}
+		} else if route.scope == scopeDevice {
+			deviceID = requestDeviceID(c)
+		}
+
+		if route.scope == scopeGlobal {
+			return apiKeyError(c, fiber.StatusForbidden, "FORBIDDEN", "api key is restricted to devices and cannot use this endpoint")
+		}
+		if deviceID == "" {
+			if len(key.Devices) > 1 {
+				return apiKeyError(c, fiber.StatusBadRequest, "DEVICE_ID_REQUIRED",
+					"device_id is required via X-Device-Id header or device_id query for keys with several devices")
+			}
+			deviceID = key.Devices[0]
+			c.Request().Header.Set(DeviceIDHeader, url.QueryEscape(deviceID))
+		}
+		if !key.AllowsDevice(deviceID) {
+			return apiKeyError(c, fiber.StatusForbidden, "FORBIDDEN", "api key is not allowed to use device "+deviceID)
+		}
+
+		return c.Next()
+	}
+}
+
+// AuthenticatedByAPIKey reports whether APIKeyAuth accepted a key for the request
+func AuthenticatedByAPIKey(c *fiber.Ctx) bool {
+	_, ok := c.Locals("api_key").(domainAPIKey.APIKey)
+	return ok
+}
+
+func requestAPIKey(c *fiber.Ctx) string {
+	if key := strings.TrimSpace(c.Get(APIKeyHeader)); key != "" {
+		return key
+	}
+	authorization := c.Get(fiber.HeaderAuthorization)
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
+
+// requestDeviceID reads the device the same way DeviceMiddleware does
+func requestDeviceID(c *fiber.Ctx) string {
+	deviceID := strings.TrimSpace(c.Get(DeviceIDHeader))
+	if decoded, err := url.QueryUnescape(deviceID); err == nil {
+		deviceID = decoded
+	}
+	if deviceID == "" {
+		deviceID = strings.TrimSpace(c.Query("device_id"))
+	}
+	return deviceID
+}
+
+func apiKeyError(c *fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(utils.ResponseData{
+		Status:  status,
+		Code:    code,
+		Message: message,
+		Results: nil,
+	})
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticAPIKeys map[string]domainAPIKey.APIKey
+
+func (s staticAPIKeys) CreateKey(context.Context, domainAPIKey.CreateAPIKeyRequest) (domainAPIKey.CreateAPIKeyResponse, error) {
+	return domainAPIKey.CreateAPIKeyResponse{}, nil
+}
+
+func (s staticAPIKeys) ListKeys(context.Context) ([]domainAPIKey.APIKey, error) {
+	return nil, nil
+}
+
+func (s staticAPIKeys) RevokeKey(context.Context, string) error {
+	return nil
+}
+
+func (s staticAPIKeys) Authenticate(_ context.Context, key string) (domainAPIKey.APIKey, error) {
+	if found, ok := s[key]; ok {
+		return found, nil
+	}
+	return domainAPIKey.APIKey{}, pkgError.AuthError("invalid api key")
+}
+
+func newAPIKeyTestApp() *fiber.App {
+	keys := staticAPIKeys{
+		"sender": {ID: "1", Devices: []string{"sales"}, Permissions: []string{domainAPIKey.PermissionSend}},
+		"reader": {ID: "2", Devices: []string{"sales", "support"}, Permissions: []string{domainAPIKey.PermissionReadChats}},
+		"admin":  {ID: "3", Permissions: []string{domainAPIKey.PermissionAdmin}},
+	}
+
+	app := fiber.New()
+	app.Use(APIKeyAuth(keys))
+	app.All("/*", func(c *fiber.Ctx) error {
+		return c.SendString(c.Get(DeviceIDHeader))
+	})
+	return app
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	app := newAPIKeyTestApp()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		key    string
+		device string
+		status int
+		body   string
+	}{
+		{name: "no key falls through", method: fiber.MethodPost, target: "/send/message", status: fiber.StatusOK},
+		{name: "unknown key", method: fiber.MethodPost, target: "/send/message", key: "nope", status: fiber.StatusUnauthorized},
+		{name: "single device becomes default", method: fiber.MethodPost, target: "/send/message", key: "sender", status: fiber.StatusOK, body: "sales"},
+		{name: "other device", method: fiber.MethodPost, target: "/send/message", key: "sender", device: "support", status: fiber.StatusForbidden},
+		{name: "missing permission", method: fiber.MethodGet, target: "/chats", key: "sender", status: fiber.StatusForbidden},
+		{name: "read allows get only", method: fiber.MethodPost, target: "/message/ABC/read", key: "reader", device: "support", status: fiber.StatusForbidden},
+		{name: "several devices need one named", method: fiber.MethodGet, target: "/chats", key: "reader", status: fiber.StatusBadRequest},
+		{name: "device from query", method: fiber.MethodGet, target: "/chats?device_id=support", key: "reader", status: fiber.StatusOK},
+		{name: "device from path", method: fiber.MethodGet, target: "/devices/other/status", key: "sender", status: fiber.StatusForbidden},
+		{name: "global route with restricted key", method: fiber.MethodGet, target: "/api-keys", key: "reader", status: fiber.StatusForbidden},
+		{name: "admin on global route", method: fiber.MethodGet, target: "/api-keys", key: "admin", status: fiber.StatusOK},
+		{name: "admin on any device", method: fiber.MethodPost, target: "/group/leave", key: "admin", device: "support", status: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.key != "" {
+				req.Header.Set(fiber.HeaderAuthorization, "Bearer "+tt.key)
+			}
+			if tt.device != "" {
+				req.Header.Set(DeviceIDHeader, tt.device)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.body != "" {
+				body := make([]byte, len(tt.body))
+				_, _ = resp.Body.Read(body)
+				assert.Equal(t, tt.body, string(body))
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth_HeaderKeyMarksRequest(t *testing.T) {
+	app := fiber.New()
+	app.Use(APIKeyAuth(staticAPIKeys{"admin": {ID: "3", Permissions: []string{domainAPIKey.PermissionAdmin}}}))
+	app.Get("/backup", func(c *fiber.Ctx) error {
+		assert.True(t, AuthenticatedByAPIKey(c))
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/backup", nil)
+	req.Header.Set(APIKeyHeader, "admin")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxAuditFields     = 20
+	maxAuditValueRunes = 64
+	maxAuditErrorRunes = 255
+)
+
+// auditTargetFields hold the JIDs or phone numbers a call acts on
+var auditTargetFields = []string{"phone", "group_id", "participants", "jid", "newsletter_id", "chat_jid"}
+
+// auditContentFields hold message content, the audit log only keeps its length
+var auditContentFields = []string{"message", "caption", "content", "text", "body", "question", "options", "description"}
+
+// auditedGetRoutes are GET routes that change state or hand out the session, so they are recorded like writes
+var auditedGetRoutes = []string{
+	"/app/login",
+	"/app/login-with-code",
+	"/app/logout",
+	"/app/reconnect",
+	"/devices/:device_id/login",
+	"/backup",
+}
+
+// Audit records every state-changing call (anything but GET, HEAD and OPTIONS, plus the GET routes in
+// auditedGetRoutes) with its actor, device, targets, a summary of the request and the result. It runs before
+// Recovery so it sees the final response of handlers that panic. Requests rejected by basic auth and unknown
+// routes are not recorded.
+func Audit(service domainAudit.IAuditUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		if service == nil || method == fiber.MethodHead || method == fiber.MethodOptions {
+			return c.Next()
+		}
+
+		started := time.Now()
+		err := c.Next()
+
+		// The route is only known once the router matched it
+		route := strings.TrimPrefix(c.Route().Path, config.AppBasePath)
+		if method == fiber.MethodGet && !slices.Contains(auditedGetRoutes, route) {
+			return err
+		}
+
+		status := responseStatus(c, err)
+		if err != nil && (status == fiber.StatusNotFound || status == fiber.StatusMethodNotAllowed) {
+			return err
+		}
+		if status == fiber.StatusUnauthorized {
+			return err
+		}
+
+		entry := domainAudit.Entry{
+			ActorType:  domainAudit.ActorAnonymous,
+			DeviceID:   auditDeviceID(c),
+			Method:     method,
+			Route:      route,
+			Path:       c.Path(),
+			StatusCode: status,
+			Success:    err == nil && status < fiber.StatusBadRequest,
+			DurationMs: time.Since(started).Milliseconds(),
+			RemoteIP:   c.IP(),
+			CreatedAt:  started,
+		}
+		if key, ok := c.Locals("api_key").(domainAPIKey.APIKey); ok {
+			entry.ActorType, entry.Actor, entry.APIKeyID = domainAudit.ActorAPIKey, key.Name, key.ID
+		} else if username, ok := c.Locals("username").(string); ok && username != "" {
+			entry.ActorType, entry.Actor = domainAudit.ActorBasicAuth, username
+		}
+		entry.Targets, entry.Summary = auditRequest(c)
+		if !entry.Success {
+			entry.Error = auditError(c, err)
+		}
+
+		if recordErr := service.Record(tracing.Detach(c.UserContext()), entry); recordErr != nil {
+			logrus.Errorf("Failed to record audit entry for %s %s: %v", method, entry.Path, recordErr)
+		}
+		return err
+	}
+}
+
+func auditDeviceID(c *fiber.Ctx) string {
+	if deviceID, ok := c.Locals("device_id").(string); ok && deviceID != "" {
+		return deviceID
+	}
+	if deviceID := c.Params("device_id"); deviceID != "" {
+		return deviceID
+	}
+	return requestDeviceID(c)
+}
+
+// auditRequest collects the targets and a summary of the fields of a JSON, form or multipart request
+func auditRequest(c *fiber.Ctx) ([]string, map[string]string) {
+	fields := requestFields(c)
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if !slices.Contains(auditTargetFields, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	summary := make(map[string]string, min(len(names), maxAuditFields))
+	for _, name := range names[:min(len(names), maxAuditFields)] {
+		summary[name] = auditValue(name, fields[name])
+	}
+	return requestTargets(c, auditTargetFields...), summary
+}
+
+func auditValue(name string, value any) string {
+	lower := strings.ToLower(name)
+	if strings.Contains(lower, "password") || strings.Contains(lower, "secret") || strings.Contains(lower, "token") {
+		return "[redacted]"
+	}
+
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case []any:
+		return fmt.Sprintf("[%d items]", len(typed))
+	case map[string]any:
+		return "[object]"
+	case string:
+		if slices.Contains(auditContentFields, lower) {
+			return fmt.Sprintf("[%d chars]", utf8.RuneCountInString(typed))
+		}
+		return truncateRunes(typed, maxAuditValueRunes)
+	default:
+		if slices.Contains(auditContentFields, lower) {
+			return "[content]"
+		}
+		return truncateRunes(fmt.Sprint(typed), maxAuditValueRunes)
+	}
+}
+
+// auditError takes the message of the JSON error response, or the error a handler returned
+func auditError(c *fiber.Ctx, err error) string {
+	if err != nil {
+		return truncateRunes(err.Error(), maxAuditErrorRunes)
+	}
+	var response struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(c.Response().Body(), &response) == nil && response.Message != "" {
+		return truncateRunes(response.Message, maxAuditErrorRunes)
+	}
+	return ""
+}
+
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	return string([]rune(value)[:limit]) + "…"
+}
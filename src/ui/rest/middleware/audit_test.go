@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/basicauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryAuditLog struct {
+	mu      sync.Mutex
+	entries []domainAudit.Entry
+}
+
+func (m *memoryAuditLog) Record(_ context.Context, entry domainAudit.Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memoryAuditLog) List(context.Context, domainAudit.ListRequest) (domainAudit.ListResponse, error) {
+	return domainAudit.ListResponse{}, nil
+}
+
+func (m *memoryAuditLog) Run(context.Context) {}
+
+func newAuditTestApp(log *memoryAuditLog) *fiber.App {
+	app := fiber.New()
+	app.Use(Audit(log))
+	app.Use(Recovery())
+	app.Use(basicauth.New(basicauth.Config{Users: map[string]string{"ops": "secret"}}))
+	app.Post("/send/message", func(c *fiber.Ctx) error {
+		c.Locals("device_id", "sales")
+		return c.JSON(fiber.Map{"code": "SUCCESS"})
+	})
+	app.Post("/group/participants/remove", func(c *fiber.Ctx) error {
+		panic(pkgError.ValidationError("participants: cannot be blank."))
+	})
+	app.Get("/chats", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/app/logout", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"code": "SUCCESS"})
+	})
+	return app
+}
+
+func doAuditRequest(t *testing.T, app *fiber.App, method, target, contentType, body string, authenticated bool) int {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set(fiber.HeaderContentType, contentType)
+	}
+	if authenticated {
+		req.SetBasicAuth("ops", "secret")
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestAudit_RecordsStateChangingCalls(t *testing.T) {
+	log := &memoryAuditLog{}
+	app := newAuditTestApp(log)
+
+	status := doAuditRequest(t, app, fiber.MethodPost, "/send/message", fiber.MIMEApplicationJSON,
+		`{"phone":"6289685028129@s.whatsapp.net","message":"hello there","is_forwarded":false}`, true)
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status = doAuditRequest(t, app, fiber.MethodPost, "/group/participants/remove", fiber.MIMEApplicationForm,
+		"group_id=120363024512399999%40g.us&participants=6281111111111,6282222222222", true)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	require.Len(t, log.entries, 2)
+	sent := log.entries[0]
+	assert.Equal(t, domainAudit.ActorBasicAuth, sent.ActorType)
+	assert.Equal(t, "ops", sent.Actor)
+	assert.Equal(t, "sales", sent.DeviceID)
+	assert.Equal(t, "/send/message", sent.Route)
+	assert.Equal(t, []string{"6289685028129@s.whatsapp.net"}, sent.Targets)
+	assert.Equal(t, map[string]string{"message": "[11 chars]", "is_forwarded": "false"}, sent.Summary)
+	assert.True(t, sent.Success)
+
+	removed := log.entries[1]
+	assert.Equal(t, []string{"120363024512399999@g.us", "6281111111111", "6282222222222"}, removed.Targets)
+	assert.Equal(t, fiber.StatusBadRequest, removed.StatusCode)
+	assert.False(t, removed.Success)
+	assert.Equal(t, "participants: cannot be blank.", removed.Error)
+}
+
+func TestAudit_RecordsStateChangingGetRoutes(t *testing.T) {
+	log := &memoryAuditLog{}
+	app := newAuditTestApp(log)
+
+	assert.Equal(t, fiber.StatusOK, doAuditRequest(t, app, fiber.MethodGet, "/app/logout", "", "", true))
+	require.Len(t, log.entries, 1)
+	assert.Equal(t, fiber.MethodGet, log.entries[0].Method)
+	assert.Equal(t, "/app/logout", log.entries[0].Route)
+	assert.Equal(t, "ops", log.entries[0].Actor)
+	assert.True(t, log.entries[0].Success)
+}
+
+func TestAudit_SkipsReadsAndRejectedCalls(t *testing.T) {
+	log := &memoryAuditLog{}
+	app := newAuditTestApp(log)
+
+	assert.Equal(t, fiber.StatusOK, doAuditRequest(t, app, fiber.MethodGet, "/chats", "", "", true))
+	assert.Equal(t, fiber.StatusUnauthorized, doAuditRequest(t, app, fiber.MethodPost, "/send/message", "", "", false))
+	assert.Equal(t, fiber.StatusNotFound, doAuditRequest(t, app, fiber.MethodPost, "/unknown", "", "", true))
+	assert.Empty(t, log.entries)
+}
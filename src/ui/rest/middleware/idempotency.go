@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	idempotencyPurgeInterval = time.Hour
+)
+
+// Idempotency replays the stored response of a POST request whose Idempotency-Key was already used by the
+// same device within the retention window, so a client retrying after a timeout does not send twice.
+// Only successful responses are kept. A request rejected with a 4xx releases its key for the next attempt,
+// any other failure keeps it reserved until it expires.
+func Idempotency(repo domainIdempotency.IIdempotencyRepository, retention time.Duration) fiber.Handler {
+	var lastPurge atomic.Int64
+
+	return func(c *fiber.Ctx) error {
+		key := strings.TrimSpace(c.Get(IdempotencyKeyHeader))
+		if key == "" || c.Method() != fiber.MethodPost || repo == nil {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+				Status:  fiber.StatusBadRequest,
+				Code:    "INVALID_IDEMPOTENCY_KEY",
+				Message: "Idempotency-Key must not be longer than 255 characters",
+				Results: nil,
+			})
+		}
+
+		deviceID, _ := c.Locals("device_id").(string)
+		expiredBefore := time.Now().Add(-retention)
+		purgeExpiredIdempotencyKeys(repo, &lastPurge, expiredBefore)
+
+		reserved, existing, err := repo.ReserveKey(&domainIdempotency.Record{
+			DeviceID: deviceID,
+			Key:      key,
+			Path:     c.Path(),
+		}, expiredBefore)
+		if err != nil {
+			logrus.Errorf("Failed to reserve idempotency key for device %s: %v", deviceID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(utils.ResponseData{
+				Status:  fiber.StatusInternalServerError,
+				Code:    "INTERNAL_SERVER_ERROR",
+				Message: "failed to check Idempotency-Key",
+				Results: nil,
+			})
+		}
+		if !reserved {
+			return replayIdempotentResponse(c, existing)
+		}
+
+		// Only a client error releases the key: requests are validated before anything is sent, so a 4xx means
+		// nothing went out. After a timeout or server error the message may already be on its way, so the key
+		// stays reserved and retries get 409 until it expires instead of sending a second copy.
+		status := 0
+		defer func() {
+			recovered := recover()
+			if recovered != nil {
+				status = panicStatus(recovered)
+			}
+			if releasesIdempotencyKey(c, status) {
+				if err := repo.ReleaseKey(deviceID, key); err != nil {
+					logrus.Errorf("Failed to release idempotency key for device %s: %v", deviceID, err)
+				}
+			}
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			status = responseStatus(c, err)
+			return err
+		}
+
+		status = c.Response().StatusCode()
+		if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+			return nil
+		}
+		if err := repo.CompleteKey(deviceID, key, status, c.Response().Body()); err != nil {
+			logrus.Errorf("Failed to store idempotent response for device %s: %v", deviceID, err)
+		}
+		return nil
+	}
+}
+
+// releasesIdempotencyKey reports whether the request was turned away before it could have sent anything
+func releasesIdempotencyKey(c *fiber.Ctx, status int) bool {
+	if status < fiber.StatusBadRequest || status >= fiber.StatusInternalServerError || status == fiber.StatusRequestTimeout {
+		return false
+	}
+	return c.UserContext().Err() == nil
+}
+
+// panicStatus is the status Recovery answers with for a handler panic
+func panicStatus(recovered any) int {
+	if genericErr, ok := recovered.(pkgError.GenericError); ok {
+		return genericErr.StatusCode()
+	}
+	return fiber.StatusInternalServerError
+}
+
+func replayIdempotentResponse(c *fiber.Ctx, record *domainIdempotency.Record) error {
+	if record == nil || !record.Completed {
+		return c.Status(fiber.StatusConflict).JSON(utils.ResponseData{
+			Status:  fiber.StatusConflict,
+			Code:    "IDEMPOTENCY_KEY_IN_PROGRESS",
+			Message: "a request with this Idempotency-Key is still being processed or did not finish, its outcome is unknown",
+			Results: nil,
+		})
+	}
+	if record.Path != c.Path() {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(utils.ResponseData{
+			Status:  fiber.StatusUnprocessableEntity,
+			Code:    "IDEMPOTENCY_KEY_REUSED",
+			Message: "Idempotency-Key was already used for " + record.Path,
+			Results: nil,
+		})
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(record.StatusCode).Send(record.Response)
+}
+
+// purgeExpiredIdempotencyKeys drops expired keys in the background, at most once per purge interval.
+func purgeExpiredIdempotencyKeys(repo domainIdempotency.IIdempotencyRepository, lastPurge *atomic.Int64, before time.Time) {
+	now := time.Now().Unix()
+	last := lastPurge.Load()
+	if now-last < int64(idempotencyPurgeInterval/time.Second) || !lastPurge.CompareAndSwap(last, now) {
+		return
+	}
+
+	go func() {
+		purged, err := repo.PurgeKeysBefore(before)
+		if err != nil {
+			logrus.Warnf("Failed to purge expired idempotency keys: %v", err)
+		} else if purged > 0 {
+			logrus.Debugf("Purged %d expired idempotency key(s)", purged)
+		}
+	}()
+}
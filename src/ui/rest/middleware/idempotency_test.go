@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	domainIdempotency "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/idempotency"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]*domainIdempotency.Record
+}
+
+func newMemoryIdempotencyRepo() *memoryIdempotencyRepo {
+	return &memoryIdempotencyRepo{records: map[string]*domainIdempotency.Record{}}
+}
+
+func (r *memoryIdempotencyRepo) ReserveKey(record *domainIdempotency.Record, expiredBefore time.Time) (bool, *domainIdempotency.Record, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := record.DeviceID + "/" + record.Key
+	if existing, ok := r.records[id]; ok && !existing.CreatedAt.Before(expiredBefore) {
+		copied := *existing
+		return false, &copied, nil
+	}
+	stored := *record
+	stored.CreatedAt = time.Now()
+	r.records[id] = &stored
+	return true, nil, nil
+}
+
+func (r *memoryIdempotencyRepo) CompleteKey(deviceID, key string, statusCode int, response []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if record, ok := r.records[deviceID+"/"+key]; ok {
+		record.Completed = true
+		record.StatusCode = statusCode
+		record.Response = append([]byte(nil), response...)
+	}
+	return nil
+}
+
+func (r *memoryIdempotencyRepo) ReleaseKey(deviceID, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.records, deviceID+"/"+key)
+	return nil
+}
+
+func (r *memoryIdempotencyRepo) PurgeKeysBefore(time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotencyTestApp(repo domainIdempotency.IIdempotencyRepository, calls *int) *fiber.App {
+	app := fiber.New()
+	app.Use(Recovery())
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("device_id", c.Get(DeviceIDHeader))
+		return c.Next()
+	})
+	app.Use("/send", Idempotency(repo, time.Hour))
+	app.Post("/send/message", func(c *fiber.Ctx) error {
+		*calls++
+		switch c.Query("fail") {
+		case "validation":
+			panic(pkgError.ValidationError("phone: cannot be blank."))
+		case "server":
+			panic("device is offline")
+		case "timeout":
+			panic(context.DeadlineExceeded)
+		}
+		return c.JSON(fiber.Map{"message_id": "MSG-1", "call": *calls})
+	})
+	app.Post("/send/image", func(c *fiber.Ctx) error {
+		*calls++
+		return c.JSON(fiber.Map{"message_id": "IMG-1"})
+	})
+	return app
+}
+
+func sendWithIdempotencyKey(t *testing.T, app *fiber.App, target, deviceID, key string) (int, string, string) {
+	req := httptest.NewRequest("POST", target, nil)
+	req.Header.Set(DeviceIDHeader, deviceID)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), resp.Header.Get(IdempotentReplayedHeader)
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	app := newIdempotencyTestApp(newMemoryIdempotencyRepo(), &calls)
+
+	status, first, replayed := sendWithIdempotencyKey(t, app, "/send/message", "sales", "order-42")
+	assert.Equal(t, 200, status)
+	assert.Empty(t, replayed)
+
+	status, second, replayed := sendWithIdempotencyKey(t, app, "/send/message", "sales", "order-42")
+	assert.Equal(t, 200, status)
+	assert.Equal(t, first, second)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_KeysArePerDevice(t *testing.T) {
+	calls := 0
+	app := newIdempotencyTestApp(newMemoryIdempotencyRepo(), &calls)
+
+	sendWithIdempotencyKey(t, app, "/send/message", "sales", "order-42")
+	sendWithIdempotencyKey(t, app, "/send/message", "support", "order-42")
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ReleasesKeyOnValidationError(t *testing.T) {
+	calls := 0
+	app := newIdempotencyTestApp(newMemoryIdempotencyRepo(), &calls)
+
+	status, _, _ := sendWithIdempotencyKey(t, app, "/send/message?fail=validation", "sales", "order-42")
+	assert.Equal(t, 400, status)
+
+	status, _, replayed := sendWithIdempotencyKey(t, app, "/send/message", "sales", "order-42")
+	assert.Equal(t, 200, status)
+	assert.Empty(t, replayed)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_KeepsKeyWhenOutcomeIsUnknown(t *testing.T) {
+	for _, failure := range []string{"server", "timeout"} {
+		t.Run(failure, func(t *testing.T) {
+			calls := 0
+			app := newIdempotencyTestApp(newMemoryIdempotencyRepo(), &calls)
+
+			status, _, _ := sendWithIdempotencyKey(t, app, "/send/message?fail="+failure, "sales", "order-42")
+			assert.GreaterOrEqual(t, status, 500)
+
+			status, _, _ = sendWithIdempotencyKey(t, app, "/send/message", "sales", "order-42")
+			assert.Equal(t, 409, status)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestIdempotency_KeepsKeyAfterRequestTimeout(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Use(Recovery())
+	app.Use(RequestTimeout(10 * time.Millisecond))
+	app.Use("/send", Idempotency(newMemoryIdempotencyRepo(), time.Hour))
+	app.Post("/send/message", func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			// The message went out but the response came too late
+			<-c.UserContext().Done()
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": c.UserContext().Err().Error()})
+		}
+		return c.JSON(fiber.Map{"message_id": "MSG-1"})
+	})
+
+	status, _, _ := sendWithIdempotencyKey(t, app, "/send/message", "", "order-42")
+	assert.Equal(t, 400, status)
+
+	status, _, _ = sendWithIdempotencyKey(t, app, "/send/message", "", "order-42")
+	assert.Equal(t, 409, status)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_RejectsKeyReusedOnAnotherRoute(t *testing.T) {
+	calls := 0
+	app := newIdempotencyTestApp(newMemoryIdempotencyRepo(), &calls)
+
+	sendWithIdempotencyKey(t, app, "/send/message", "sales", "order-42")
+	status, _, _ := sendWithIdempotencyKey(t, app, "/send/image", "sales", "order-42")
+	assert.Equal(t, 422, status)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_ConflictWhileInProgress(t *testing.T) {
+	repo := newMemoryIdempotencyRepo()
+	_, _, _ = repo.ReserveKey(&domainIdempotency.Record{DeviceID: "sales", Key: "order-42", Path: "/send/message"}, time.Now().Add(-time.Hour))
+
+	calls := 0
+	app := newIdempotencyTestApp(repo, &calls)
+
+	status, _, _ := sendWithIdempotencyKey(t, app, "/send/message", "sales", "order-42")
+	assert.Equal(t, 409, status)
+	assert.Equal(t, 0, calls)
+}
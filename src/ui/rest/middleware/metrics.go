@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+// unmatchedRoute labels requests no route handled, so unknown paths do not each get a series
+const unmatchedRoute = "unmatched"
+
+// Metrics observes the latency of every request by method, route pattern and status. It runs first so the
+// time spent in authentication and the other middleware is included.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		started := time.Now()
+		err := c.Next()
+
+		status := responseStatus(c, err)
+		metrics.HTTPRequestDuration.WithLabelValues(c.Method(), requestRoute(c, status, err), strconv.Itoa(status)).
+			Observe(time.Since(started).Seconds())
+		return err
+	}
+}
+
+// requestRoute is the route pattern that handled the request, without the base path
+func requestRoute(c *fiber.Ctx, status int, err error) string {
+	if status == fiber.StatusNotFound && err != nil {
+		return unmatchedRoute
+	}
+	return strings.TrimPrefix(c.Route().Path, config.AppBasePath)
+}
+
+// responseStatus is the status code the client gets, including errors the fiber error handler turns into a response
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_ObservesRoutePatterns(t *testing.T) {
+	app := fiber.New()
+	app.Use(Metrics())
+	app.Post("/chat/:chat_jid/pin", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	before := testutil.CollectAndCount(metrics.HTTPRequestDuration)
+	for _, target := range []string{"/chat/6289685028129@s.whatsapp.net/pin", "/chat/6281111111111@s.whatsapp.net/pin", "/nope", "/other"} {
+		_, err := app.Test(httptest.NewRequest(fiber.MethodPost, target, nil))
+		require.NoError(t, err)
+	}
+
+	// One series for the route pattern and one shared by the unknown paths
+	assert.Equal(t, before+2, testutil.CollectAndCount(metrics.HTTPRequestDuration))
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/ratelimit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitScopeHeader     = "X-RateLimit-Scope"
+)
+
+// RateLimits are token bucket sizes in requests per minute, 0 disables a scope
+type RateLimits struct {
+	// PerCredential limits each API key, basic auth user, or client IP when neither is used
+	PerCredential int
+	PerDevice     int
+	// PerRecipient limits requests to one phone number or group from one device
+	PerRecipient int
+}
+
+// Enabled reports whether any scope is limited
+func (l RateLimits) Enabled() bool {
+	return l.PerCredential > 0 || l.PerDevice > 0 || l.PerRecipient > 0
+}
+
+// RateLimit rejects state-changing requests with 429 and Retry-After once a credential, device or recipient
+// has used its budget. It runs after DeviceMiddleware; the headers report the scope with the fewest requests
+// left. Share one limiter between routes so they draw from the same buckets.
+func RateLimit(limiter *ratelimit.Limiter, limits RateLimits) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		if limiter == nil || method == fiber.MethodGet || method == fiber.MethodHead || method == fiber.MethodOptions {
+			return c.Next()
+		}
+
+		deviceID, _ := c.Locals("device_id").(string)
+		requests := []ratelimit.Request{
+			{Key: "credential:" + rateLimitCredential(c), PerMinute: limits.PerCredential},
+			{Key: "device:" + deviceID, PerMinute: limits.PerDevice},
+		}
+		for _, recipient := range requestTargets(c, "phone", "group_id") {
+			requests = append(requests, ratelimit.Request{
+				Key:       "recipient:" + deviceID + "/" + normalizeRecipient(recipient),
+				PerMinute: limits.PerRecipient,
+			})
+		}
+
+		result := limiter.Take(requests...)
+		if result.Limit == 0 {
+			return c.Next()
+		}
+
+		scope, _, _ := strings.Cut(result.Key, ":")
+		c.Set(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+		c.Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		c.Set(RateLimitScopeHeader, scope)
+		if result.Allowed {
+			return c.Next()
+		}
+
+		retryAfter := max(1, int(math.Ceil(result.RetryAfter.Seconds())))
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		return c.Status(fiber.StatusTooManyRequests).JSON(utils.ResponseData{
+			Status:  fiber.StatusTooManyRequests,
+			Code:    "TOO_MANY_REQUESTS",
+			Message: fmt.Sprintf("rate limit of %d requests per minute per %s exceeded, retry in %d seconds", result.Limit, scope, retryAfter),
+			Results: nil,
+		})
+	}
+}
+
+func rateLimitCredential(c *fiber.Ctx) string {
+	if key, ok := c.Locals("api_key").(domainAPIKey.APIKey); ok {
+		return "key/" + key.ID
+	}
+	if username, ok := c.Locals("username").(string); ok && username != "" {
+		return "user/" + username
+	}
+	return "ip/" + c.IP()
+}
+
+// normalizeRecipient makes +6289685028129, 6289685028129 and 6289685028129@s.whatsapp.net share a bucket
+func normalizeRecipient(recipient string) string {
+	return strings.TrimPrefix(strings.TrimSuffix(recipient, "@s.whatsapp.net"), "+")
+}
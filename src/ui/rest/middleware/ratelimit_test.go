@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitTestApp(limits RateLimits) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("device_id", c.Get(DeviceIDHeader))
+		return c.Next()
+	})
+	limit := RateLimit(ratelimit.New(), limits)
+	app.Use("/send", limit)
+	app.Use("/group", limit)
+	app.All("/*", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func rateLimitedRequest(t *testing.T, app *fiber.App, method, target, device, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(DeviceIDHeader, device)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestRateLimit_PerRecipient(t *testing.T) {
+	app := newRateLimitTestApp(RateLimits{PerDevice: 10, PerRecipient: 1})
+
+	resp := rateLimitedRequest(t, app, fiber.MethodPost, "/send/message", "sales", `{"phone":"6289685028129"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get(RateLimitLimitHeader))
+	assert.Equal(t, "0", resp.Header.Get(RateLimitRemainingHeader))
+	assert.Equal(t, "recipient", resp.Header.Get(RateLimitScopeHeader))
+
+	resp = rateLimitedRequest(t, app, fiber.MethodPost, "/send/image", "sales", `{"phone":"6289685028129@s.whatsapp.net"}`)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	// Another recipient, or the same recipient from another device, has its own budget
+	resp = rateLimitedRequest(t, app, fiber.MethodPost, "/send/message", "sales", `{"phone":"6281111111111"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	resp = rateLimitedRequest(t, app, fiber.MethodPost, "/send/message", "support", `{"phone":"6289685028129"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestRateLimit_PerDeviceAcrossRoutes(t *testing.T) {
+	app := newRateLimitTestApp(RateLimits{PerDevice: 2})
+
+	assert.Equal(t, fiber.StatusOK, rateLimitedRequest(t, app, fiber.MethodPost, "/send/message", "sales", `{}`).StatusCode)
+	assert.Equal(t, fiber.StatusOK, rateLimitedRequest(t, app, fiber.MethodPost, "/group/leave", "sales", `{}`).StatusCode)
+	resp := rateLimitedRequest(t, app, fiber.MethodPost, "/group/participants", "sales", `{}`)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	// Reads and other routes are not limited
+	assert.Equal(t, fiber.StatusOK, rateLimitedRequest(t, app, fiber.MethodGet, "/group/info", "sales", "").StatusCode)
+	assert.Equal(t, fiber.StatusOK, rateLimitedRequest(t, app, fiber.MethodPost, "/chat/abc/pin", "sales", `{}`).StatusCode)
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestFields returns the top-level fields of a JSON, form or multipart request body, files as their
+// name. The result is kept on the request so several middlewares parse the body once.
+func requestFields(c *fiber.Ctx) map[string]any {
+	if fields, ok := c.Locals("request_fields").(map[string]any); ok {
+		return fields
+	}
+
+	fields := map[string]any{}
+	switch {
+	case c.Is("json"):
+		_ = json.Unmarshal(c.Body(), &fields)
+	case c.Is("multipart"):
+		if form, err := c.MultipartForm(); err == nil {
+			for name, values := range form.Value {
+				fields[name] = formValue(values)
+			}
+			for name, files := range form.File {
+				if len(files) > 0 {
+					fields[name] = "file " + files[0].Filename
+				}
+			}
+		}
+	default:
+		c.Request().PostArgs().VisitAll(func(key, value []byte) {
+			fields[string(key)] = string(value)
+		})
+	}
+
+	c.Locals("request_fields", fields)
+	return fields
+}
+
+// requestTargets returns the distinct JIDs or phone numbers found in the route parameters and body fields
+// with these names
+func requestTargets(c *fiber.Ctx, names ...string) []string {
+	fields := requestFields(c)
+
+	var targets []string
+	for _, name := range names {
+		if value := c.Params(name); value != "" {
+			targets = append(targets, value)
+		}
+		switch value := fields[name].(type) {
+		case string:
+			targets = append(targets, splitTargets(value)...)
+		case []any:
+			for _, item := range value {
+				if text, ok := item.(string); ok {
+					targets = append(targets, splitTargets(text)...)
+				}
+			}
+		}
+	}
+	return uniqueTargets(targets)
+}
+
+func formValue(values []string) any {
+	if len(values) == 1 {
+		return values[0]
+	}
+	items := make([]any, 0, len(values))
+	for _, value := range values {
+		items = append(items, value)
+	}
+	return items
+}
+
+// splitTargets accepts the comma separated participant lists form requests use
+func splitTargets(value string) []string {
+	var targets []string
+	for _, target := range strings.Split(value, ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func uniqueTargets(targets []string) []string {
+	unique := []string{}
+	for _, target := range targets {
+		if !slices.Contains(unique, target) {
+			unique = append(unique, target)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"io/fs"
+	"net/url"
+	"path"
+	"path/filepath"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Statics serves the files under root for the /statics/* route, decrypted when they are sealed at rest.
+// Without a signer, files that do not exist fall through to the next handler. With a signer every
+// request needs a valid signed link and this handler replaces the static mount.
+func Statics(root string, signer *mediastorage.URLSigner) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name, err := url.PathUnescape(c.Params("*"))
+		if err != nil || !filepath.IsLocal(name) {
+			return fiber.ErrNotFound
+		}
+
+		if signer != nil {
+			key := path.Join(filepath.ToSlash(root), filepath.ToSlash(name))
+			if err := signer.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+				return fiber.NewError(fiber.StatusForbidden, err.Error())
+			}
+		}
+
+		file, err := encryption.OpenFile(filepath.Join(root, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			if signer != nil {
+				return fiber.ErrNotFound
+			}
+			return c.Next()
+		}
+		if err != nil {
+			return err
+		}
+
+		c.Type(filepath.Ext(name))
+		return c.SendStream(file)
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatics_RequiresSignedLink(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "statics")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "media"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "media", "photo.jpg"), []byte("jpeg"), 0600))
+
+	signer := mediastorage.NewURLSigner([]byte("secret"), time.Minute)
+	app := fiber.New()
+	app.Get("/statics/*", Statics(root, signer))
+
+	// Keys are root joined with the requested path, like statics/media/photo.jpg in production
+	signed := signer.Sign(filepath.ToSlash(root) + "/media/photo.jpg")
+	query := signed[len(filepath.ToSlash(root)+"/media/photo.jpg"):]
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/statics/media/photo.jpg"+query, nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "jpeg", string(body))
+	assert.Equal(t, "image/jpeg", resp.Header.Get(fiber.HeaderContentType))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/statics/media/photo.jpg", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	// A signature is only valid for the file it was issued for
+	resp, err = app.Test(httptest.NewRequest("GET", "/statics/media/other.jpg"+query, nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/statics/..%2f..%2fetc%2fpasswd"+query, nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing opens a server span for every request, continuing the caller's trace when it sends traceparent, and
+// puts it in the user context so usecases, WhatsApp calls, storage queries and webhooks become its children.
+// The span is named after the route pattern once the request is handled.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		ctx := tracing.Extract(c.UserContext(), http.Header(c.GetReqHeaders()))
+		ctx, span := tracing.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(method), semconv.URLPath(c.Path())),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		status := responseStatus(c, err)
+		route := requestRoute(c, status, err)
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		return err
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_NamesSpanAfterRoute(t *testing.T) {
+	require.NoError(t, tracing.Setup(context.Background(), ""))
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Post("/chat/:chat_jid/pin", func(c *fiber.Ctx) error {
+		_, span := tracing.Start(c.UserContext(), "chat.PinChat")
+		span.End()
+		return c.SendStatus(fiber.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/chat/6289685028129@s.whatsapp.net/pin", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	usecase, server := spans[0], spans[1]
+
+	assert.Equal(t, "POST /chat/:chat_jid/pin", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, codes.Error, server.Status().Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), usecase.Parent().SpanID())
+}
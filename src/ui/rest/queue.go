@@ -0,0 +1,67 @@
+package rest
+
+import (
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Queue struct {
+	Service domainQueue.IQueueUsecase
+}
+
+// InitRestQueue must run before InitRestSend: its handlers take send requests made with queue=true
+// and pass every other request on to the regular send routes.
+func InitRestQueue(app fiber.Router, service domainQueue.IQueueUsecase) Queue {
+	rest := Queue{Service: service}
+
+	for _, sendType := range domainSend.StorableTypes {
+		app.Post("/send/"+sendType, rest.enqueueHandler(sendType))
+	}
+	for _, sendType := range []string{domainSend.TypeImage, domainSend.TypeVideo, domainSend.TypeAudio, domainSend.TypeFile} {
+		app.Post("/send/json/"+sendType, rest.enqueueHandler(sendType))
+	}
+	app.Get("/send/queue/:job_id", rest.GetJob)
+
+	return rest
+}
+
+func (controller *Queue) enqueueHandler(sendType string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !c.QueryBool("queue") {
+			return c.Next()
+		}
+		if !c.Is("json") {
+			utils.PanicIfNeeded(pkgError.ValidationError("queued messages must be sent as application/json; use *_url or *_path fields for media"))
+		}
+
+		request := domainQueue.EnqueueRequest{
+			Type:    sendType,
+			Payload: append([]byte(nil), c.Body()...),
+		}
+		response, err := controller.Service.Enqueue(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+		utils.PanicIfNeeded(err)
+
+		return c.Status(fiber.StatusAccepted).JSON(utils.ResponseData{
+			Status:  fiber.StatusAccepted,
+			Code:    "SUCCESS",
+			Message: "Message queued",
+			Results: response,
+		})
+	}
+}
+
+func (controller *Queue) GetJob(c *fiber.Ctx) error {
+	response, err := controller.Service.Get(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("job_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get queued message",
+		Results: response,
+	})
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	domainRetention "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/retention"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Retention struct {
+	Service domainRetention.IRetentionUsecase
+}
+
+func InitRestRetention(app fiber.Router, service domainRetention.IRetentionUsecase) Retention {
+	rest := Retention{Service: service}
+
+	app.Get("/retention/policies", rest.ListPolicies)
+	app.Post("/retention/policies", rest.CreatePolicy)
+	app.Put("/retention/policies/:policy_id", rest.UpdatePolicy)
+	app.Delete("/retention/policies/:policy_id", rest.DeletePolicy)
+	app.Get("/retention/report", rest.Report)
+
+	return rest
+}
+
+func (controller *Retention) ListPolicies(c *fiber.Ctx) error {
+	response, err := controller.Service.ListPolicies(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get retention policies",
+		Results: response,
+	})
+}
+
+func (controller *Retention) CreatePolicy(c *fiber.Ctx) error {
+	var request domainRetention.CreatePolicyRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreatePolicy(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Retention policy created",
+		Results: response,
+	})
+}
+
+func (controller *Retention) UpdatePolicy(c *fiber.Ctx) error {
+	var request domainRetention.UpdatePolicyRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.PolicyID = c.Params("policy_id")
+
+	response, err := controller.Service.UpdatePolicy(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Retention policy updated",
+		Results: response,
+	})
+}
+
+func (controller *Retention) DeletePolicy(c *fiber.Ctx) error {
+	err := controller.Service.DeletePolicy(c.UserContext(), c.Params("policy_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Retention policy deleted",
+		Results: nil,
+	})
+}
+
+func (controller *Retention) Report(c *fiber.Ctx) error {
+	response, err := controller.Service.Report(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get retention report",
+		Results: response,
+	})
+}
@@ -0,0 +1,106 @@
+package rest
+
+import (
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Schedule struct {
+	Service domainSchedule.IScheduleUsecase
+}
+
+func InitRestSchedule(app fiber.Router, service domainSchedule.IScheduleUsecase) Schedule {
+	rest := Schedule{Service: service}
+
+	app.Post("/send/schedule/:type", rest.ScheduleMessage)
+	app.Get("/send/schedules", rest.ListSchedules)
+	app.Get("/send/schedules/:schedule_id", rest.GetSchedule)
+	app.Post("/send/schedules/:schedule_id/cancel", rest.CancelSchedule)
+	app.Post("/send/schedules/:schedule_id/reschedule", rest.Reschedule)
+
+	return rest
+}
+
+func (controller *Schedule) ScheduleMessage(c *fiber.Ctx) error {
+	if !c.Is("json") {
+		utils.PanicIfNeeded(pkgError.ValidationError("scheduled messages must be sent as application/json; use *_url or *_path fields for media"))
+	}
+
+	var request domainSchedule.CreateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.Type = c.Params("type")
+	request.Payload = append([]byte(nil), c.Body()...)
+
+	response, err := controller.Service.Schedule(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Message scheduled",
+		Results: response,
+	})
+}
+
+func (controller *Schedule) ListSchedules(c *fiber.Ctx) error {
+	var request domainSchedule.ListRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.List(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get scheduled messages",
+		Results: response,
+	})
+}
+
+func (controller *Schedule) GetSchedule(c *fiber.Ctx) error {
+	response, err := controller.Service.Get(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("schedule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get scheduled message",
+		Results: response,
+	})
+}
+
+func (controller *Schedule) CancelSchedule(c *fiber.Ctx) error {
+	response, err := controller.Service.Cancel(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), c.Params("schedule_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Scheduled message cancelled",
+		Results: response,
+	})
+}
+
+func (controller *Schedule) Reschedule(c *fiber.Ctx) error {
+	var request domainSchedule.RescheduleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.ScheduleID = c.Params("schedule_id")
+
+	response, err := controller.Service.Reschedule(whatsapp.ContextWithDevice(c.UserContext(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Message rescheduled",
+		Results: response,
+	})
+}
@@ -43,6 +43,7 @@ func (controller *Send) SendText(c *fiber.Ctx) error {
 	var request domainSend.MessageRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormTemplateVariables(c, &request.TemplateRequest)
 
     err = controller.Validator.Struct(&request)
     utils.PanicIfNeeded(err)
@@ -66,6 +67,7 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormTemplateVariables(c, &request.TemplateRequest)
 
     err = controller.Validator.Struct(&request)
     utils.PanicIfNeeded(err)
@@ -92,6 +94,7 @@ func (controller *Send) SendFile(c *fiber.Ctx) error {
 	var request domainSend.FileRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	parseFormTemplateVariables(c, &request.TemplateRequest)
 
     err = controller.Validator.Struct(&request)
     utils.PanicIfNeeded(err)
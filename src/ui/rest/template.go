@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"encoding/json"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Template struct {
+	Service domainTemplate.ITemplateUsecase
+}
+
+func InitRestTemplate(app fiber.Router, service domainTemplate.ITemplateUsecase) Template {
+	rest := Template{Service: service}
+
+	app.Get("/templates", rest.ListTemplates)
+	app.Post("/templates", rest.CreateTemplate)
+	app.Get("/templates/:template_id", rest.GetTemplate)
+	app.Put("/templates/:template_id", rest.UpdateTemplate)
+	app.Delete("/templates/:template_id", rest.DeleteTemplate)
+
+	return rest
+}
+
+func (controller *Template) ListTemplates(c *fiber.Ctx) error {
+	response, err := controller.Service.ListTemplates(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get templates",
+		Results: response,
+	})
+}
+
+func (controller *Template) GetTemplate(c *fiber.Ctx) error {
+	response, err := controller.Service.GetTemplate(c.UserContext(), c.Params("template_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get template",
+		Results: response,
+	})
+}
+
+func (controller *Template) CreateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.CreateTemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template created",
+		Results: response,
+	})
+}
+
+func (controller *Template) UpdateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.UpdateTemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.TemplateID = c.Params("template_id")
+
+	response, err := controller.Service.UpdateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template updated",
+		Results: response,
+	})
+}
+
+func (controller *Template) DeleteTemplate(c *fiber.Ctx) error {
+	err := controller.Service.DeleteTemplate(c.UserContext(), c.Params("template_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template deleted",
+		Results: nil,
+	})
+}
+
+// parseFormTemplateVariables reads the variables of a form-encoded send request, which arrive as a JSON object string.
+func parseFormTemplateVariables(c *fiber.Ctx, template *domainSend.TemplateRequest) {
+	if c.Is("json") {
+		return
+	}
+
+	if raw := c.FormValue("variables"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &template.Variables); err != nil {
+			utils.PanicIfNeeded(pkgError.ValidationError("variables: must be a JSON object of strings"))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package rest
+
+import (
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Webhook struct {
+	Service domainWebhook.IWebhookUsecase
+}
+
+func InitRestWebhook(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+
+	app.Get("/webhooks/deliveries", rest.ListDeliveries)
+	app.Post("/webhooks/deliveries/redrive", rest.RedriveDeadDeliveries)
+	app.Get("/webhooks/deliveries/:delivery_id", rest.GetDelivery)
+	app.Post("/webhooks/deliveries/:delivery_id/redrive", rest.RedriveDelivery)
+
+	app.Get("/devices/:device_id/webhooks", rest.ListSubscriptions)
+	app.Post("/devices/:device_id/webhooks", rest.CreateSubscription)
+	app.Get("/devices/:device_id/webhooks/:subscription_id", rest.GetSubscription)
+	app.Put("/devices/:device_id/webhooks/:subscription_id", rest.UpdateSubscription)
+	app.Delete("/devices/:device_id/webhooks/:subscription_id", rest.DeleteSubscription)
+
+	return rest
+}
+
+// InitRestWebhookReplay registers the replay route, which needs the device middleware.
+func InitRestWebhookReplay(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+
+	app.Post("/webhooks/replay", rest.Replay)
+
+	return rest
+}
+
+func (controller *Webhook) ListDeliveries(c *fiber.Ctx) error {
+	var request domainWebhook.ListDeliveriesRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListDeliveries(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook deliveries",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) GetDelivery(c *fiber.Ctx) error {
+	response, err := controller.Service.GetDelivery(c.UserContext(), c.Params("delivery_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook delivery",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) RedriveDelivery(c *fiber.Ctx) error {
+	response, err := controller.Service.RedriveDelivery(c.UserContext(), c.Params("delivery_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook delivery queued for re-delivery",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) RedriveDeadDeliveries(c *fiber.Ctx) error {
+	var request domainWebhook.RedriveDeliveriesRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.RedriveDeadDeliveries(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Dead webhook deliveries queued for re-delivery",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ListSubscriptions(c *fiber.Ctx) error {
+	response, err := controller.Service.ListSubscriptions(c.UserContext(), c.Params("device_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook subscriptions",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) GetSubscription(c *fiber.Ctx) error {
+	response, err := controller.Service.GetSubscription(c.UserContext(), c.Params("device_id"), c.Params("subscription_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook subscription",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) CreateSubscription(c *fiber.Ctx) error {
+	var request domainWebhook.CreateSubscriptionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.DeviceID = c.Params("device_id")
+
+	response, err := controller.Service.CreateSubscription(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook subscription created",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) UpdateSubscription(c *fiber.Ctx) error {
+	var request domainWebhook.UpdateSubscriptionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.DeviceID = c.Params("device_id")
+	request.SubscriptionID = c.Params("subscription_id")
+
+	response, err := controller.Service.UpdateSubscription(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook subscription updated",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) DeleteSubscription(c *fiber.Ctx) error {
+	err := controller.Service.DeleteSubscription(c.UserContext(), c.Params("device_id"), c.Params("subscription_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook subscription deleted",
+		Results: nil,
+	})
+}
+
+func (controller *Webhook) Replay(c *fiber.Ctx) error {
+	var request domainWebhook.ReplayRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.Replay(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Webhook replay completed",
+		Results: response,
+	})
+}
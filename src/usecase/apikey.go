@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiKeyPrefix = "wak_"
+	// apiKeyTouchInterval limits last-used updates to one write per key and interval
+	apiKeyTouchInterval = time.Minute
+)
+
+type serviceAPIKey struct {
+	apiKeyRepo    domainAPIKey.IAPIKeyRepository
+	deviceManager *whatsapp.DeviceManager
+}
+
+func NewAPIKeyService(apiKeyRepo domainAPIKey.IAPIKeyRepository, deviceManager *whatsapp.DeviceManager) domainAPIKey.IAPIKeyUsecase {
+	return &serviceAPIKey{
+		apiKeyRepo:    apiKeyRepo,
+		deviceManager: deviceManager,
+	}
+}
+
+func (service serviceAPIKey) CreateKey(ctx context.Context, request domainAPIKey.CreateAPIKeyRequest) (domainAPIKey.CreateAPIKeyResponse, error) {
+	if err := validations.ValidateCreateAPIKey(ctx, &request); err != nil {
+		return domainAPIKey.CreateAPIKeyResponse{}, err
+	}
+
+	devices := utils.UniqueStrings(request.Devices)
+	for _, deviceID := range devices {
+		if service.deviceManager == nil {
+			return domainAPIKey.CreateAPIKeyResponse{}, fmt.Errorf("device manager not initialized")
+		}
+		if inst, ok := service.deviceManager.GetDevice(deviceID); !ok || inst == nil {
+			return domainAPIKey.CreateAPIKeyResponse{}, pkgError.NotFoundError(fmt.Sprintf("device %s not found", deviceID))
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domainAPIKey.CreateAPIKeyResponse{}, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	plaintext := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &domainAPIKey.APIKey{
+		Name:        strings.TrimSpace(request.Name),
+		Prefix:      plaintext[:len(apiKeyPrefix)+8],
+		KeyHash:     hashAPIKey(plaintext),
+		Devices:     devices,
+		Permissions: utils.UniqueStrings(request.Permissions),
+	}
+	if err := service.apiKeyRepo.CreateKey(key); err != nil {
+		return domainAPIKey.CreateAPIKeyResponse{}, fmt.Errorf("failed to save api key: %w", err)
+	}
+	return domainAPIKey.CreateAPIKeyResponse{APIKey: *key, Key: plaintext}, nil
+}
+
+func (service serviceAPIKey) ListKeys(_ context.Context) ([]domainAPIKey.APIKey, error) {
+	keys, err := service.apiKeyRepo.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	response := make([]domainAPIKey.APIKey, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, *key)
+	}
+	return response, nil
+}
+
+func (service serviceAPIKey) RevokeKey(_ context.Context, keyID string) error {
+	key, err := service.apiKeyRepo.GetKey(keyID)
+	if err != nil {
+		return fmt.Errorf("failed to get api key: %w", err)
+	}
+	if key == nil {
+		return pkgError.NotFoundError(fmt.Sprintf("api key %s not found", keyID))
+	}
+
+	if err := service.apiKeyRepo.RevokeKey(keyID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+func (service serviceAPIKey) Authenticate(_ context.Context, plaintext string) (domainAPIKey.APIKey, error) {
+	plaintext = strings.TrimSpace(plaintext)
+	if plaintext == "" {
+		return domainAPIKey.APIKey{}, pkgError.AuthError("api key is required")
+	}
+
+	key, err := service.apiKeyRepo.GetKeyByHash(hashAPIKey(plaintext))
+	if err != nil {
+		return domainAPIKey.APIKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if key == nil || key.RevokedAt != nil {
+		return domainAPIKey.APIKey{}, pkgError.AuthError("invalid api key")
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := service.apiKeyRepo.TouchKey(key.ID, now); err != nil {
+			logrus.Warnf("Failed to record use of api key %s: %v", key.ID, err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return *key, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are random, so a plain hash is enough to look
+// them up without storing them.
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+type memoryAPIKeyRepo struct {
+	keys    map[string]*domainAPIKey.APIKey
+	touches int
+}
+
+func (r *memoryAPIKeyRepo) CreateKey(key *domainAPIKey.APIKey) error {
+	key.ID = "key-1"
+	key.CreatedAt = time.Now()
+	r.keys[key.ID] = key
+	return nil
+}
+
+func (r *memoryAPIKeyRepo) GetKey(keyID string) (*domainAPIKey.APIKey, error) {
+	return r.keys[keyID], nil
+}
+
+func (r *memoryAPIKeyRepo) GetKeyByHash(keyHash string) (*domainAPIKey.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryAPIKeyRepo) ListKeys() ([]*domainAPIKey.APIKey, error) {
+	return nil, nil
+}
+
+func (r *memoryAPIKeyRepo) RevokeKey(keyID string, revokedAt time.Time) error {
+	r.keys[keyID].RevokedAt = &revokedAt
+	return nil
+}
+
+func (r *memoryAPIKeyRepo) TouchKey(keyID string, usedAt time.Time) error {
+	r.touches++
+	r.keys[keyID].LastUsedAt = &usedAt
+	return nil
+}
+
+func TestAPIKeyService_AuthenticateUntilRevoked(t *testing.T) {
+	repo := &memoryAPIKeyRepo{keys: map[string]*domainAPIKey.APIKey{}}
+	service := NewAPIKeyService(repo, nil)
+	ctx := context.Background()
+
+	created, err := service.CreateKey(ctx, domainAPIKey.CreateAPIKeyRequest{
+		Name:        "crm",
+		Permissions: []string{domainAPIKey.PermissionSend, domainAPIKey.PermissionSend},
+	})
+	if err != nil {
+		t.Fatalf("create key: %v", err)
+	}
+	if !strings.HasPrefix(created.Key, created.Prefix) || repo.keys["key-1"].KeyHash == created.Key {
+		t.Fatalf("expected the key to start with its prefix and be stored hashed, got %+v", created)
+	}
+	if len(created.Permissions) != 1 {
+		t.Errorf("expected duplicate permissions to be dropped, got %v", created.Permissions)
+	}
+
+	for range 2 {
+		key, err := service.Authenticate(ctx, created.Key)
+		if err != nil {
+			t.Fatalf("authenticate: %v", err)
+		}
+		if key.LastUsedAt == nil {
+			t.Errorf("expected last used time to be set")
+		}
+	}
+	if repo.touches != 1 {
+		t.Errorf("expected one last used update within the interval, got %d", repo.touches)
+	}
+
+	if err := service.RevokeKey(ctx, created.ID); err != nil {
+		t.Fatalf("revoke key: %v", err)
+	}
+	if _, err := service.Authenticate(ctx, created.Key); err != pkgError.AuthError("invalid api key") {
+		t.Errorf("expected revoked key to be rejected, got %v", err)
+	}
+	if err := service.RevokeKey(ctx, "missing"); err != pkgError.NotFoundError("api key missing not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -1,10 +1,10 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
@@ -12,6 +12,8 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	fiberUtils "github.com/gofiber/fiber/v2/utils"
@@ -35,6 +37,9 @@ func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository, dev
 }
 
 func (service *serviceApp) Login(ctx context.Context, deviceID string) (response domainApp.LoginResponse, err error) {
+	ctx, span := tracing.Start(ctx, "app.Login")
+	defer func() { tracing.End(span, err) }()
+
 	instance, client, err := service.ensureClient(ctx, deviceID)
 	if err != nil {
 		return response, err
@@ -70,13 +75,18 @@ func (service *serviceApp) Login(ctx context.Context, deviceID string) (response
 			response.Duration = evt.Timeout / time.Second / 2
 			if evt.Event == "code" {
 				qrPath := fmt.Sprintf("%s/scan-qr-%s.png", config.PathQrCode, fiberUtils.UUIDv4())
-				if err := qrcode.WriteFile(evt.Code, qrcode.Medium, 512, qrPath); err != nil {
+				qrImage, err := qrcode.Encode(evt.Code, qrcode.Medium, 512)
+				if err == nil {
+					// Codes keep rotating after the login request has returned, so the request context is not used
+					err = mediastorage.Active().Put(context.Background(), qrPath, bytes.NewReader(qrImage), int64(len(qrImage)), "image/png")
+				}
+				if err != nil {
 					logrus.Errorf("[LOGIN][%s] Error when write qr code to file: %v", deviceID, err)
 					continue // Skip sending if QR generation failed
 				}
 				go func(path string, duration time.Duration) {
 					time.Sleep(duration * time.Second)
-					if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					if err := mediastorage.Active().Delete(context.Background(), path); err != nil {
 						logrus.Errorf("[LOGIN][%s] error when remove qrImage file: %v", deviceID, err)
 					}
 				}(qrPath, response.Duration)
@@ -107,6 +117,9 @@ func (service *serviceApp) Login(ctx context.Context, deviceID string) (response
 			return response, fmt.Errorf("QR channel closed without receiving image")
 		}
 		response.ImagePath = imagePath
+		if response.ImageURL, err = mediastorage.Active().URL(ctx, imagePath); err != nil {
+			return response, err
+		}
 	case <-ctx.Done():
 		return response, ctx.Err()
 	case <-time.After(120 * time.Second):
@@ -117,6 +130,9 @@ func (service *serviceApp) Login(ctx context.Context, deviceID string) (response
 }
 
 func (service *serviceApp) LoginWithCode(ctx context.Context, deviceID string, phoneNumber string) (loginCode string, err error) {
+	ctx, span := tracing.Start(ctx, "app.LoginWithCode")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateLoginWithCode(ctx, phoneNumber); err != nil {
 		logrus.Errorf("Error when validate login with code: %s", err.Error())
 		return loginCode, err
@@ -140,7 +156,9 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, deviceID string, p
 	}
 
 	logrus.Infof("[LOGIN_CODE][%s] Starting phone pairing for number: %s", deviceID, phoneNumber)
-	loginCode, err = client.PairPhone(ctx, phoneNumber, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
+	loginCode, err = tracing.Call(ctx, "whatsmeow.PairPhone", func(ctx context.Context) (string, error) {
+		return client.PairPhone(ctx, phoneNumber, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
+	})
 	if err != nil {
 		logrus.Errorf("Error when pairing phone: %s", err.Error())
 		return loginCode, err
@@ -151,7 +169,10 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, deviceID string, p
 	return loginCode, nil
 }
 
-func (service *serviceApp) Logout(ctx context.Context, deviceID string) error {
+func (service *serviceApp) Logout(ctx context.Context, deviceID string) (err error) {
+	ctx, span := tracing.Start(ctx, "app.Logout")
+	defer func() { tracing.End(span, err) }()
+
 	if service.deviceManager == nil {
 		return fmt.Errorf("device manager not initialized")
 	}
@@ -181,7 +202,10 @@ func (service *serviceApp) Logout(ctx context.Context, deviceID string) error {
 	return nil
 }
 
-func (service *serviceApp) Reconnect(_ context.Context, deviceID string) (err error) {
+func (service *serviceApp) Reconnect(ctx context.Context, deviceID string) (err error) {
+	ctx, span := tracing.Start(ctx, "app.Reconnect")
+	defer func() { tracing.End(span, err) }()
+
 	instance, client, err := service.ensureClient(context.Background(), deviceID)
 	if err != nil {
 		return err
@@ -200,7 +224,10 @@ func (service *serviceApp) Reconnect(_ context.Context, deviceID string) (err er
 	return err
 }
 
-func (service *serviceApp) Status(_ context.Context, deviceID string) (bool, bool, error) {
+func (service *serviceApp) Status(ctx context.Context, deviceID string) (isConnected bool, isLoggedIn bool, err error) {
+	ctx, span := tracing.Start(ctx, "app.Status")
+	defer func() { tracing.End(span, err) }()
+
 	if service.deviceManager == nil {
 		return false, false, fmt.Errorf("device manager not initialized")
 	}
@@ -224,6 +251,9 @@ func (service *serviceApp) Status(_ context.Context, deviceID string) (bool, boo
 }
 
 func (service *serviceApp) FirstDevice(ctx context.Context) (response domainApp.DevicesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "app.FirstDevice")
+	defer func() { tracing.End(span, err) }()
+
 	devices, err := service.FetchDevices(ctx)
 	if err != nil {
 		return response, err
@@ -234,7 +264,10 @@ func (service *serviceApp) FirstDevice(ctx context.Context) (response domainApp.
 	return devices[0], nil
 }
 
-func (service *serviceApp) FetchDevices(_ context.Context) (response []domainApp.DevicesResponse, err error) {
+func (service *serviceApp) FetchDevices(ctx context.Context) (response []domainApp.DevicesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "app.FetchDevices")
+	defer func() { tracing.End(span, err) }()
+
 	if service.deviceManager == nil {
 		return response, fmt.Errorf("device manager not initialized")
 	}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+const auditPurgeInterval = time.Hour
+
+type serviceAudit struct {
+	auditRepo domainAudit.IAuditRepository
+}
+
+func NewAuditService(auditRepo domainAudit.IAuditRepository) domainAudit.IAuditUsecase {
+	return &serviceAudit{
+		auditRepo: auditRepo,
+	}
+}
+
+func (service serviceAudit) Record(_ context.Context, entry domainAudit.Entry) error {
+	if err := service.auditRepo.StoreEntry(&entry); err != nil {
+		return fmt.Errorf("failed to store audit entry: %w", err)
+	}
+	return nil
+}
+
+func (service serviceAudit) List(ctx context.Context, request domainAudit.ListRequest) (domainAudit.ListResponse, error) {
+	if err := validations.ValidateListAudit(ctx, &request); err != nil {
+		return domainAudit.ListResponse{}, err
+	}
+
+	filter := &domainAudit.Filter{
+		DeviceID: request.DeviceID,
+		Actor:    request.Actor,
+		Route:    request.Route,
+		Target:   request.Target,
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	}
+	// Both times were validated as RFC3339
+	if request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, request.EndTime)
+		filter.EndTime = &endTime
+	}
+
+	entries, total, err := service.auditRepo.ListEntries(filter)
+	if err != nil {
+		return domainAudit.ListResponse{}, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	response := domainAudit.ListResponse{
+		Data: make([]domainAudit.Entry, 0, len(entries)),
+		Pagination: domainAudit.PaginationResponse{
+			Limit:  request.Limit,
+			Offset: request.Offset,
+			Total:  total,
+		},
+	}
+	for _, entry := range entries {
+		response.Data = append(response.Data, *entry)
+	}
+	return response, nil
+}
+
+func (service serviceAudit) Run(ctx context.Context) {
+	ticker := time.NewTicker(auditPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if config.AppAuditRetentionDays > 0 {
+			cutoff := time.Now().AddDate(0, 0, -config.AppAuditRetentionDays)
+			if purged, err := service.auditRepo.PurgeEntriesBefore(cutoff); err != nil {
+				logrus.Errorf("Failed to purge audit log: %v", err)
+			} else if purged > 0 {
+				logrus.Infof("Purged %d audit log entries older than %d days", purged, config.AppAuditRetentionDays)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
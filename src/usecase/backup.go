@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainBackup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/backup"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceBackup struct{}
+
+func NewBackupService() domainBackup.IBackupUsecase {
+	return &serviceBackup{}
+}
+
+// backupTarget is a storage of this host: where backup reads it and where restore puts it back
+type backupTarget struct {
+	name string
+	kind string
+	uri  string
+	// path is empty for databases that do not live in a SQLite file
+	path string
+}
+
+// backupTargets lists the storages of this host. Media kept in an S3 bucket is not among them, the bucket's
+// versioning or replication has to cover it.
+func backupTargets() []backupTarget {
+	targets := []backupTarget{sqliteTarget(domainBackup.StoreWhatsApp, config.DBURI)}
+	if config.DBKeysURI != "" && config.DBKeysURI != config.DBURI {
+		targets = append(targets, sqliteTarget(domainBackup.StoreKeys, config.DBKeysURI))
+	}
+	targets = append(targets, sqliteTarget(domainBackup.StoreChatStorage, config.ChatStorageURI))
+	if config.ChatStorageEncryptionKeyFile != "" {
+		targets = append(targets, backupTarget{name: domainBackup.StoreEncryptionKeys, kind: domainBackup.KindFile, path: config.ChatStorageEncryptionKeyFile})
+	}
+	if config.MediaStorage != "s3" {
+		targets = append(targets, backupTarget{name: domainBackup.StoreMedia, kind: domainBackup.KindDirectory, path: config.PathMedia})
+	}
+	return targets
+}
+
+func sqliteTarget(name, uri string) backupTarget {
+	target := backupTarget{name: name, kind: domainBackup.KindSQLite, uri: uri}
+	target.path, _ = chatstorage.SQLitePath(uri)
+	return target
+}
+
+func (service serviceBackup) Backup(ctx context.Context, request domainBackup.BackupRequest, w io.Writer) (domainBackup.Manifest, error) {
+	manifest := domainBackup.Manifest{
+		Version:    domainBackup.ManifestVersion,
+		AppVersion: config.AppVersion,
+		CreatedAt:  time.Now().UTC(),
+		Devices:    []string{},
+		Stores:     []domainBackup.Store{},
+	}
+
+	// The data is unreadable without its key, so a backup of encrypted storages has to carry the keyring
+	if config.ChatStorageEncryptionKey != "" {
+		return manifest, pkgError.ValidationError("the storages are encrypted with --db-encryption-key, which cannot be backed up; " +
+			"pass the key with --db-encryption-key-file instead so the backup includes it")
+	}
+	if config.MediaStorage == "s3" {
+		manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("%s: kept in the S3 bucket, back it up with the bucket's versioning or replication", domainBackup.StoreMedia))
+	}
+
+	snapshotDir, err := os.MkdirTemp(config.PathStorages, "backup-")
+	if err != nil {
+		return manifest, fmt.Errorf("failed to create snapshot folder: %w", err)
+	}
+	defer os.RemoveAll(snapshotDir)
+
+	archive := zip.NewWriter(w)
+	for _, target := range backupTargets() {
+		store := domainBackup.Store{Name: target.name, Kind: target.kind, Source: target.path}
+
+		switch target.kind {
+		case domainBackup.KindSQLite:
+			if target.path == "" {
+				// Never echo the URI, a PostgreSQL URI carries the password
+				manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("%s: not a SQLite database, back it up with pg_dump", target.name))
+				continue
+			}
+			if !fileExists(target.path) {
+				continue
+			}
+
+			snapshot := filepath.Join(snapshotDir, target.name+".db")
+			if err := chatstorage.BackupSQLite(ctx, target.uri, snapshot); err != nil {
+				return manifest, fmt.Errorf("failed to snapshot %s: %w", target.name, err)
+			}
+			if target.name == domainBackup.StoreWhatsApp {
+				devices, err := chatstorage.SQLiteDeviceJIDs(ctx, snapshot)
+				if err != nil {
+					return manifest, fmt.Errorf("failed to list devices: %w", err)
+				}
+				manifest.Devices = append(manifest.Devices, devices...)
+			}
+
+			file, err := addArchiveFile(archive, "databases/"+target.name+".db", snapshot, zip.Deflate)
+			if err != nil {
+				return manifest, err
+			}
+			store.Files = []domainBackup.File{file}
+
+		case domainBackup.KindFile:
+			if !fileExists(target.path) {
+				continue
+			}
+			file, err := addArchiveFile(archive, "storages/"+filepath.Base(target.path), target.path, zip.Deflate)
+			if err != nil {
+				return manifest, err
+			}
+			store.Files = []domainBackup.File{file}
+
+		case domainBackup.KindDirectory:
+			if !request.IncludeMedia {
+				manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("%s: left out on request", target.name))
+				continue
+			}
+			if !fileExists(target.path) {
+				continue
+			}
+			err := filepath.WalkDir(target.path, func(current string, entry fs.DirEntry, err error) error {
+				if err != nil || !entry.Type().IsRegular() {
+					return err
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				relative, err := filepath.Rel(target.path, current)
+				if err != nil {
+					return err
+				}
+				// Media is compressed already, deflating it again only costs time
+				file, err := addArchiveFile(archive, path.Join(target.name, filepath.ToSlash(relative)), current, zip.Store)
+				if err != nil {
+					return err
+				}
+				store.Files = append(store.Files, file)
+				return nil
+			})
+			if err != nil {
+				return manifest, fmt.Errorf("failed to archive %s: %w", target.name, err)
+			}
+		}
+
+		manifest.Stores = append(manifest.Stores, store)
+	}
+
+	writer, err := archive.Create(domainBackup.ManifestName)
+	if err != nil {
+		return manifest, err
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return manifest, err
+	}
+	if err := archive.Close(); err != nil {
+		return manifest, fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// stagedStore is a store extracted and verified next to its target, waiting to be moved in place
+type stagedStore struct {
+	target backupTarget
+	staged string
+}
+
+func (service serviceBackup) Restore(ctx context.Context, request domainBackup.RestoreRequest) (domainBackup.Manifest, error) {
+	var manifest domainBackup.Manifest
+	if request.Archive == "" {
+		return manifest, pkgError.ValidationError("archive: cannot be blank.")
+	}
+
+	reader, err := zip.OpenReader(request.Archive)
+	if err != nil {
+		return manifest, pkgError.ValidationError(fmt.Sprintf("archive: not a backup archive: %v", err))
+	}
+	defer reader.Close()
+
+	entries := make(map[string]*zip.File, len(reader.File))
+	for _, entry := range reader.File {
+		entries[entry.Name] = entry
+	}
+
+	manifest, err = readBackupManifest(entries[domainBackup.ManifestName])
+	if err != nil {
+		return manifest, err
+	}
+
+	targets := make(map[string]backupTarget)
+	for _, target := range backupTargets() {
+		targets[target.name] = target
+	}
+
+	// Extract and verify every store before replacing anything, a bad archive leaves the host untouched
+	var staged []stagedStore
+	defer func() {
+		for _, store := range staged {
+			_ = os.RemoveAll(store.staged)
+		}
+	}()
+	for _, store := range manifest.Stores {
+		if store.Name == domainBackup.StorePolls {
+			logrus.Infof("[BACKUP] Skipping the legacy poll store, polls are kept in the chat storage")
+			continue
+		}
+		if store.Name == domainBackup.StoreEncryptionKeys && config.ChatStorageEncryptionKeyFile == "" {
+			return manifest, pkgError.ValidationError("archive has an encryption key file, set --db-encryption-key-file to where it should be restored")
+		}
+		target, ok := targets[store.Name]
+		if !ok || target.kind != store.Kind {
+			return manifest, pkgError.ValidationError(fmt.Sprintf("archive has a %s store this host is not configured for", store.Name))
+		}
+		if target.path == "" {
+			return manifest, pkgError.ValidationError(fmt.Sprintf("archive has a %s SQLite database but this host keeps it elsewhere", store.Name))
+		}
+		if !request.Force && storeExists(target) {
+			return manifest, pkgError.ValidationError(fmt.Sprintf("%s already exists at %s, restore with force to overwrite it", store.Name, target.path))
+		}
+
+		stagedPath := target.path + ".restore"
+		if err := os.RemoveAll(stagedPath); err != nil {
+			return manifest, err
+		}
+		staged = append(staged, stagedStore{target: target, staged: stagedPath})
+		if store.Kind == domainBackup.KindDirectory {
+			// An empty folder has no files that would create it
+			if err := os.MkdirAll(stagedPath, 0755); err != nil {
+				return manifest, err
+			}
+		}
+
+		for _, file := range store.Files {
+			destination := stagedPath
+			if store.Kind == domainBackup.KindDirectory {
+				relative := strings.TrimPrefix(file.Path, store.Name+"/")
+				if !filepath.IsLocal(relative) {
+					return manifest, pkgError.ValidationError(fmt.Sprintf("archive entry %s escapes the %s folder", file.Path, store.Name))
+				}
+				destination = filepath.Join(stagedPath, filepath.FromSlash(relative))
+			}
+			if err := extractArchiveFile(entries[file.Path], file, destination); err != nil {
+				return manifest, err
+			}
+		}
+
+		if store.Name == domainBackup.StoreEncryptionKeys {
+			if err := os.Chmod(stagedPath, 0600); err != nil {
+				return manifest, err
+			}
+		}
+		if store.Kind == domainBackup.KindSQLite {
+			err := chatstorage.CheckSQLite(ctx, stagedPath)
+			removeSQLiteSidecars(stagedPath)
+			if err != nil {
+				return manifest, pkgError.ValidationError(fmt.Sprintf("%s: %v", store.Name, err))
+			}
+		}
+	}
+
+	for _, store := range staged {
+		if err := os.RemoveAll(store.target.path); err != nil {
+			return manifest, fmt.Errorf("failed to replace %s: %w", store.target.name, err)
+		}
+		if store.target.kind == domainBackup.KindSQLite {
+			// A leftover WAL of the old database would be replayed over the restored one
+			removeSQLiteSidecars(store.target.path)
+		}
+		if err := os.Rename(store.staged, store.target.path); err != nil {
+			return manifest, fmt.Errorf("failed to restore %s: %w", store.target.name, err)
+		}
+		logrus.Infof("[BACKUP] Restored %s to %s", store.target.name, store.target.path)
+	}
+	staged = nil
+
+	return manifest, nil
+}
+
+func readBackupManifest(entry *zip.File) (domainBackup.Manifest, error) {
+	var manifest domainBackup.Manifest
+	if entry == nil {
+		return manifest, pkgError.ValidationError("archive: missing " + domainBackup.ManifestName)
+	}
+
+	reader, err := entry.Open()
+	if err != nil {
+		return manifest, err
+	}
+	defer reader.Close()
+
+	if err := json.NewDecoder(reader).Decode(&manifest); err != nil {
+		return manifest, pkgError.ValidationError(fmt.Sprintf("archive: invalid %s: %v", domainBackup.ManifestName, err))
+	}
+	if manifest.Version < 1 || manifest.Version > domainBackup.ManifestVersion {
+		return manifest, pkgError.ValidationError(fmt.Sprintf("archive: unsupported backup version %d", manifest.Version))
+	}
+	return manifest, nil
+}
+
+// addArchiveFile copies a file into the archive and returns its manifest entry
+func addArchiveFile(archive *zip.Writer, name, source string, method uint16) (domainBackup.File, error) {
+	file := domainBackup.File{Path: name}
+
+	input, err := os.Open(source)
+	if err != nil {
+		return file, err
+	}
+	defer input.Close()
+
+	info, err := input.Stat()
+	if err != nil {
+		return file, err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return file, err
+	}
+	header.Name = name
+	header.Method = method
+
+	writer, err := archive.CreateHeader(header)
+	if err != nil {
+		return file, err
+	}
+	hash := sha256.New()
+	file.Size, err = io.Copy(io.MultiWriter(writer, hash), input)
+	if err != nil {
+		return file, fmt.Errorf("failed to archive %s: %w", source, err)
+	}
+	file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return file, nil
+}
+
+// extractArchiveFile writes an archive entry to destination and checks it against its manifest entry
+func extractArchiveFile(entry *zip.File, file domainBackup.File, destination string) error {
+	if entry == nil {
+		return pkgError.ValidationError(fmt.Sprintf("archive: missing %s", file.Path))
+	}
+
+	input, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return err
+	}
+	output, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(output, hash), input)
+	if err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("archive: failed to read %s: %v", file.Path, err))
+	}
+	if size != file.Size || hex.EncodeToString(hash.Sum(nil)) != file.SHA256 {
+		return pkgError.ValidationError(fmt.Sprintf("archive: %s does not match its checksum", file.Path))
+	}
+	return output.Close()
+}
+
+func removeSQLiteSidecars(name string) {
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		_ = os.Remove(name + suffix)
+	}
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// storeExists reports whether restoring a store would overwrite data, an empty media folder does not count
+func storeExists(target backupTarget) bool {
+	if target.kind != domainBackup.KindDirectory {
+		return fileExists(target.path)
+	}
+	entries, err := os.ReadDir(target.path)
+	return err == nil && len(entries) > 0 || err != nil && !errors.Is(err, fs.ErrNotExist)
+}
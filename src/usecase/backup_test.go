@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainBackup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/backup"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// useBackupHost points the storage settings at a fresh folder for the duration of the test
+func useBackupHost(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	previous := []string{config.DBURI, config.DBKeysURI, config.ChatStorageURI, config.PathStorages, config.PathMedia,
+		config.ChatStorageEncryptionKey, config.ChatStorageEncryptionKeyFile, config.MediaStorage}
+	t.Cleanup(func() {
+		config.DBURI, config.DBKeysURI, config.ChatStorageURI, config.PathStorages, config.PathMedia,
+			config.ChatStorageEncryptionKey, config.ChatStorageEncryptionKeyFile, config.MediaStorage =
+			previous[0], previous[1], previous[2], previous[3], previous[4], previous[5], previous[6], previous[7]
+	})
+
+	config.PathStorages = filepath.Join(dir, "storages")
+	config.PathMedia = filepath.Join(dir, "media")
+	config.DBURI = "file:" + filepath.Join(config.PathStorages, "whatsapp.db") + "?_foreign_keys=on"
+	config.DBKeysURI = ""
+	config.ChatStorageURI = "file:" + filepath.Join(config.PathStorages, "chatstorage.db")
+	if err := os.MkdirAll(config.PathStorages, 0755); err != nil {
+		t.Fatalf("failed to create storages: %v", err)
+	}
+	return dir
+}
+
+func execSQLite(t *testing.T, uri string, statements ...string) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", uri)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", uri, err)
+	}
+	defer db.Close()
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("failed to run %q: %v", statement, err)
+		}
+	}
+}
+
+func writeBackupArchive(t *testing.T, includeMedia bool) (string, domainBackup.Manifest) {
+	t.Helper()
+	var buffer bytes.Buffer
+	manifest, err := NewBackupService().Backup(context.Background(), domainBackup.BackupRequest{IncludeMedia: includeMedia}, &buffer)
+	if err != nil {
+		t.Fatalf("expected no backup error, got %v", err)
+	}
+	archive := filepath.Join(t.TempDir(), "backup.zip")
+	if err := os.WriteFile(archive, buffer.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	return archive, manifest
+}
+
+func TestBackupRestore_RoundTrip(t *testing.T) {
+	useBackupHost(t)
+	execSQLite(t, config.DBURI,
+		"CREATE TABLE whatsmeow_device (jid TEXT PRIMARY KEY)",
+		"INSERT INTO whatsmeow_device VALUES ('6289685028129:12@s.whatsapp.net')")
+	execSQLite(t, config.ChatStorageURI,
+		"PRAGMA journal_mode=WAL",
+		"CREATE TABLE messages (id TEXT PRIMARY KEY)",
+		"INSERT INTO messages VALUES ('ABC')")
+	if err := os.MkdirAll(filepath.Join(config.PathMedia, "6289685028129"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(config.PathMedia, "6289685028129", "photo.jpg"), []byte("jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, manifest := writeBackupArchive(t, true)
+	if len(manifest.Stores) != 3 {
+		t.Fatalf("expected 3 stores, got %+v", manifest.Stores)
+	}
+	if len(manifest.Devices) != 1 || manifest.Devices[0] != "6289685028129:12@s.whatsapp.net" {
+		t.Errorf("unexpected devices %v", manifest.Devices)
+	}
+
+	useBackupHost(t)
+	restored, err := NewBackupService().Restore(context.Background(), domainBackup.RestoreRequest{Archive: archive})
+	if err != nil {
+		t.Fatalf("expected no restore error, got %v", err)
+	}
+	if len(restored.Stores) != 3 {
+		t.Errorf("expected the manifest back, got %+v", restored)
+	}
+
+	db, err := sql.Open("sqlite3", config.ChatStorageURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var id string
+	if err := db.QueryRow("SELECT id FROM messages").Scan(&id); err != nil || id != "ABC" {
+		t.Errorf("expected the restored chat storage to keep its rows, got %q %v", id, err)
+	}
+	if content, err := os.ReadFile(filepath.Join(config.PathMedia, "6289685028129", "photo.jpg")); err != nil || string(content) != "jpeg" {
+		t.Errorf("expected the media file to be restored, got %q %v", content, err)
+	}
+
+	// A second restore would overwrite the storages it just put in place
+	if _, err := NewBackupService().Restore(context.Background(), domainBackup.RestoreRequest{Archive: archive}); err == nil {
+		t.Errorf("expected restore over existing storages to need force")
+	}
+	if _, err := NewBackupService().Restore(context.Background(), domainBackup.RestoreRequest{Archive: archive, Force: true}); err != nil {
+		t.Errorf("expected forced restore to succeed, got %v", err)
+	}
+}
+
+func TestRestore_RejectsTamperedArchive(t *testing.T) {
+	useBackupHost(t)
+	execSQLite(t, config.ChatStorageURI, "CREATE TABLE messages (id TEXT PRIMARY KEY)")
+	archive, _ := writeBackupArchive(t, false)
+
+	// Rewrite the archive with the database entry altered but the manifest untouched
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range reader.File {
+		input, _ := entry.Open()
+		var content bytes.Buffer
+		_, _ = content.ReadFrom(input)
+		_ = input.Close()
+		if strings.HasPrefix(entry.Name, "databases/") {
+			content.WriteString("garbage")
+		}
+		output, _ := writer.Create(entry.Name)
+		_, _ = output.Write(content.Bytes())
+	}
+	_ = writer.Close()
+	_ = reader.Close()
+	if err := os.WriteFile(archive, buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	useBackupHost(t)
+	_, err = NewBackupService().Restore(context.Background(), domainBackup.RestoreRequest{Archive: archive})
+	if err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("expected a checksum error, got %v", err)
+	}
+	if _, err := os.Stat(strings.TrimPrefix(config.ChatStorageURI, "file:")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing to be restored from a tampered archive")
+	}
+}
+
+func TestBackupRestore_EncryptionKeyFile(t *testing.T) {
+	useBackupHost(t)
+	execSQLite(t, config.ChatStorageURI, "CREATE TABLE messages (id TEXT PRIMARY KEY)")
+	config.ChatStorageEncryptionKeyFile = filepath.Join(config.PathStorages, "keys.json")
+	if err := os.WriteFile(config.ChatStorageEncryptionKeyFile, []byte(`{"active_key":"k1","keys":{"k1":"a2V5"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, manifest := writeBackupArchive(t, false)
+	var stored bool
+	for _, store := range manifest.Stores {
+		stored = stored || store.Name == domainBackup.StoreEncryptionKeys
+	}
+	if !stored {
+		t.Fatalf("expected the key file in the archive, got %+v", manifest.Stores)
+	}
+
+	useBackupHost(t)
+	if _, err := NewBackupService().Restore(context.Background(), domainBackup.RestoreRequest{Archive: archive}); err == nil {
+		t.Errorf("expected restore to ask where the key file goes")
+	}
+	config.ChatStorageEncryptionKeyFile = filepath.Join(config.PathStorages, "restored-keys.json")
+	if _, err := NewBackupService().Restore(context.Background(), domainBackup.RestoreRequest{Archive: archive}); err != nil {
+		t.Fatalf("expected no restore error, got %v", err)
+	}
+	if info, err := os.Stat(config.ChatStorageEncryptionKeyFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the key file to be restored readable by the owner only, got %v %v", info, err)
+	}
+}
+
+func TestBackup_RefusesInlineEncryptionKey(t *testing.T) {
+	useBackupHost(t)
+	config.ChatStorageEncryptionKey = "a2V5"
+
+	var buffer bytes.Buffer
+	_, err := NewBackupService().Backup(context.Background(), domainBackup.BackupRequest{IncludeMedia: true}, &buffer)
+	var validationError pkgError.ValidationError
+	if !errors.As(err, &validationError) || !strings.Contains(err.Error(), "--db-encryption-key-file") {
+		t.Fatalf("expected a validation error pointing at the key file flag, got %v", err)
+	}
+}
+
+func TestBackup_SkipsS3Media(t *testing.T) {
+	useBackupHost(t)
+	execSQLite(t, config.ChatStorageURI, "CREATE TABLE messages (id TEXT PRIMARY KEY)")
+	config.MediaStorage = "s3"
+
+	_, manifest := writeBackupArchive(t, true)
+	for _, store := range manifest.Stores {
+		if store.Name == domainBackup.StoreMedia {
+			t.Fatalf("expected no media store with S3, got %+v", store)
+		}
+	}
+	if len(manifest.Skipped) != 1 || !strings.Contains(manifest.Skipped[0], "S3 bucket") {
+		t.Errorf("expected the skipped media to be listed, got %v", manifest.Skipped)
+	}
+}
@@ -0,0 +1,505 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	campaignIdleWait     = 30 * time.Second
+	campaignOfflineWait  = 5 * time.Second
+	campaignDailyCapWait = 5 * time.Minute
+	campaignSkipWait     = time.Second
+	campaignSendTimeout  = 2 * time.Minute
+	campaignCheckTimeout = 10 * time.Second
+)
+
+type serviceCampaign struct {
+	campaignRepo  domainCampaign.ICampaignRepository
+	sendService   domainSend.ISendUsecase
+	templateRepo  domainTemplate.ITemplateRepository
+	deviceManager *whatsapp.DeviceManager
+	wake          *deviceWakeups
+}
+
+func NewCampaignService(campaignRepo domainCampaign.ICampaignRepository, sendService domainSend.ISendUsecase, templateRepo domainTemplate.ITemplateRepository, deviceManager *whatsapp.DeviceManager) domainCampaign.ICampaignUsecase {
+	return &serviceCampaign{
+		campaignRepo:  campaignRepo,
+		sendService:   sendService,
+		templateRepo:  templateRepo,
+		deviceManager: deviceManager,
+		wake:          newDeviceWakeups(),
+	}
+}
+
+func (service serviceCampaign) Create(ctx context.Context, request domainCampaign.CreateRequest) (domainCampaign.Campaign, error) {
+	if request.RecipientsCSV != nil {
+		file, err := request.RecipientsCSV.Open()
+		if err != nil {
+			return domainCampaign.Campaign{}, fmt.Errorf("failed to open recipients csv: %w", err)
+		}
+		recipients, err := parseRecipientsCSV(file)
+		file.Close()
+		if err != nil {
+			return domainCampaign.Campaign{}, err
+		}
+		request.Recipients = append(request.Recipients, recipients...)
+	}
+
+	if err := validations.ValidateCreateCampaign(ctx, &request); err != nil {
+		return domainCampaign.Campaign{}, err
+	}
+
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return domainCampaign.Campaign{}, err
+	}
+
+	// Render every recipient up front so a missing variable or bad number fails the request, not the 900th send.
+	seen := make(map[string]bool, len(request.Recipients))
+	recipients := make([]*domainCampaign.Recipient, 0, len(request.Recipients))
+	for i, input := range request.Recipients {
+		prepared, err := renderCampaignMessage(request.Type, request.Message, input.Phone, input.Variables)
+		if err == nil {
+			err = prepared.validate(ctx, service.templateRepo)
+		}
+		if err != nil {
+			return domainCampaign.Campaign{}, pkgError.ValidationError(fmt.Sprintf("recipient %d (%s): %s", i+1, input.Phone, err.Error()))
+		}
+
+		if seen[prepared.phone] {
+			continue
+		}
+		seen[prepared.phone] = true
+		recipients = append(recipients, &domainCampaign.Recipient{Phone: prepared.phone, Variables: input.Variables})
+	}
+
+	campaign := &domainCampaign.Campaign{
+		DeviceID:      deviceID,
+		Name:          request.Name,
+		Type:          request.Type,
+		Message:       request.Message,
+		RatePerMinute: request.RatePerMinute,
+		JitterSeconds: *request.JitterSeconds,
+		DailyCap:      *request.DailyCap,
+	}
+	if err := service.campaignRepo.CreateCampaign(campaign, recipients); err != nil {
+		return domainCampaign.Campaign{}, fmt.Errorf("failed to save campaign: %w", err)
+	}
+
+	service.wake.notify(deviceID)
+	return service.Get(ctx, campaign.ID)
+}
+
+func (service serviceCampaign) List(ctx context.Context, request domainCampaign.ListRequest) (response domainCampaign.ListResponse, err error) {
+	if err = validations.ValidateListCampaigns(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	campaigns, total, err := service.campaignRepo.ListCampaigns(&domainCampaign.Filter{
+		DeviceID: deviceID,
+		Status:   domainCampaign.Status(request.Status),
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	})
+	if err != nil {
+		return response, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	response.Data = make([]domainCampaign.Campaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		response.Data = append(response.Data, *campaign)
+	}
+	response.Pagination = domainCampaign.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceCampaign) Get(ctx context.Context, campaignID string) (domainCampaign.Campaign, error) {
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return domainCampaign.Campaign{}, err
+	}
+
+	campaign, err := service.campaignRepo.GetCampaign(deviceID, campaignID)
+	if err != nil {
+		return domainCampaign.Campaign{}, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	if campaign == nil {
+		return domainCampaign.Campaign{}, pkgError.NotFoundError(fmt.Sprintf("campaign %s not found", campaignID))
+	}
+
+	progress, err := service.campaignRepo.GetCampaignProgress(campaign.ID)
+	if err != nil {
+		return domainCampaign.Campaign{}, fmt.Errorf("failed to get campaign progress: %w", err)
+	}
+	campaign.Progress = &progress
+	return *campaign, nil
+}
+
+func (service serviceCampaign) Progress(ctx context.Context, campaignID string) (domainCampaign.ProgressResponse, error) {
+	campaign, err := service.Get(ctx, campaignID)
+	if err != nil {
+		return domainCampaign.ProgressResponse{}, err
+	}
+
+	return domainCampaign.ProgressResponse{
+		CampaignID: campaign.ID,
+		Status:     campaign.Status,
+		Progress:   *campaign.Progress,
+	}, nil
+}
+
+func (service serviceCampaign) ListRecipients(ctx context.Context, request domainCampaign.ListRecipientsRequest) (response domainCampaign.ListRecipientsResponse, err error) {
+	if err = validations.ValidateListCampaignRecipients(ctx, &request); err != nil {
+		return response, err
+	}
+
+	if _, err = service.Get(ctx, request.CampaignID); err != nil {
+		return response, err
+	}
+
+	recipients, total, err := service.campaignRepo.ListRecipients(&domainCampaign.RecipientFilter{
+		CampaignID: request.CampaignID,
+		Status:     domainCampaign.RecipientStatus(request.Status),
+		Limit:      request.Limit,
+		Offset:     request.Offset,
+	})
+	if err != nil {
+		return response, fmt.Errorf("failed to list campaign recipients: %w", err)
+	}
+
+	response.Data = make([]domainCampaign.Recipient, 0, len(recipients))
+	for _, recipient := range recipients {
+		response.Data = append(response.Data, *recipient)
+	}
+	response.Pagination = domainCampaign.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceCampaign) Pause(ctx context.Context, campaignID string) (domainCampaign.Campaign, error) {
+	return service.transition(ctx, campaignID, domainCampaign.StatusPaused, domainCampaign.StatusRunning)
+}
+
+func (service serviceCampaign) Resume(ctx context.Context, campaignID string) (domainCampaign.Campaign, error) {
+	return service.transition(ctx, campaignID, domainCampaign.StatusRunning, domainCampaign.StatusPaused)
+}
+
+// Cancel stops a campaign for good. Recipients that were not reached yet stay queued in the progress counts.
+func (service serviceCampaign) Cancel(ctx context.Context, campaignID string) (domainCampaign.Campaign, error) {
+	return service.transition(ctx, campaignID, domainCampaign.StatusCancelled, domainCampaign.StatusRunning, domainCampaign.StatusPaused)
+}
+
+func (service serviceCampaign) transition(ctx context.Context, campaignID string, status domainCampaign.Status, from ...domainCampaign.Status) (domainCampaign.Campaign, error) {
+	campaign, err := service.Get(ctx, campaignID)
+	if err != nil {
+		return campaign, err
+	}
+
+	changed, err := service.campaignRepo.UpdateCampaignStatus(campaign.DeviceID, campaignID, status, from...)
+	if err != nil {
+		return campaign, fmt.Errorf("failed to update campaign: %w", err)
+	}
+	if !changed {
+		return campaign, pkgError.ValidationError(fmt.Sprintf("campaign is already %s", campaign.Status))
+	}
+
+	service.wake.notify(campaign.DeviceID)
+	return service.Get(ctx, campaignID)
+}
+
+func (service serviceCampaign) Run(ctx context.Context) {
+	if failed, err := service.campaignRepo.FailInterruptedRecipients(); err != nil {
+		logrus.Errorf("Failed to recover interrupted campaign recipients: %v", err)
+	} else if failed > 0 {
+		logrus.Warnf("Marked %d campaign recipient(s) interrupted by the last shutdown as failed", failed)
+	}
+
+	superviseDeviceWorkers(ctx, service.deviceManager, "campaign", service.runDeviceWorker)
+}
+
+// runDeviceWorker sends campaign messages of one device one at a time, so throttling applies per device
+// no matter how many campaigns are running. Campaigns are worked through oldest first.
+func (service serviceCampaign) runDeviceWorker(ctx context.Context, deviceID string) {
+	wake := service.wake.channel(deviceID)
+	defer service.wake.remove(deviceID)
+
+	var nextSendAt time.Time
+	for {
+		timer := time.NewTimer(service.sendNext(ctx, deviceID, &nextSendAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-wake:
+			timer.Stop()
+		}
+	}
+}
+
+// sendNext handles at most one recipient and returns how long the worker should sleep afterwards.
+// nextSendAt carries the rate limit across wake-ups so pausing or creating campaigns cannot skip the delay.
+func (service serviceCampaign) sendNext(ctx context.Context, deviceID string, nextSendAt *time.Time) time.Duration {
+	if wait := time.Until(*nextSendAt); wait > 0 {
+		return wait
+	}
+
+	inst, ok := service.deviceManager.GetDevice(deviceID)
+	if !ok || inst == nil || !inst.IsConnected() || !inst.IsLoggedIn() {
+		return campaignOfflineWait
+	}
+
+	campaign, err := service.campaignRepo.NextRunningCampaign(deviceID)
+	if err != nil {
+		logrus.Errorf("Failed to fetch running campaign for device %s: %v", deviceID, err)
+		return campaignIdleWait
+	}
+	if campaign == nil {
+		return campaignIdleWait
+	}
+
+	if campaign.DailyCap > 0 {
+		sent, err := service.campaignRepo.CountSentSince(deviceID, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			logrus.Errorf("Failed to count campaign messages of device %s: %v", deviceID, err)
+			return campaignIdleWait
+		}
+		if sent >= int64(campaign.DailyCap) {
+			logrus.Debugf("Device %s reached the daily cap of %d campaign messages", deviceID, campaign.DailyCap)
+			return campaignDailyCapWait
+		}
+	}
+
+	recipient, err := service.campaignRepo.NextQueuedRecipient(campaign.ID)
+	if err != nil {
+		logrus.Errorf("Failed to fetch next recipient of campaign %s: %v", campaign.ID, err)
+		return campaignIdleWait
+	}
+	if recipient == nil {
+		if completed, err := service.campaignRepo.CompleteCampaign(campaign.ID); err != nil {
+			logrus.Errorf("Failed to complete campaign %s: %v", campaign.ID, err)
+		} else if completed {
+			logrus.Infof("Campaign %s (%s) completed", campaign.ID, campaign.Name)
+		}
+		return campaignSkipWait
+	}
+
+	claimed, err := service.campaignRepo.ClaimRecipient(recipient.ID)
+	if err != nil {
+		logrus.Errorf("Failed to claim campaign recipient %s: %v", recipient.ID, err)
+		return campaignIdleWait
+	}
+	if !claimed {
+		return campaignSkipWait
+	}
+
+	if !service.deliver(whatsapp.ContextWithDevice(ctx, inst), inst.GetClient(), campaign, recipient) {
+		return campaignSkipWait
+	}
+	*nextSendAt = time.Now().Add(campaignInterval(campaign))
+	return time.Until(*nextSendAt)
+}
+
+// deliver sends the campaign message to one recipient and records the outcome.
+// It returns false when nothing was sent, so the next recipient does not have to wait for the rate limit.
+func (service serviceCampaign) deliver(ctx context.Context, client *whatsmeow.Client, campaign *domainCampaign.Campaign, recipient *domainCampaign.Recipient) bool {
+	// Let an in-flight send finish on shutdown instead of leaving the recipient in sending.
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), campaignSendTimeout)
+	defer cancel()
+
+	record := func(status domainCampaign.RecipientStatus, messageID, errMsg string) {
+		if err := service.campaignRepo.UpdateRecipientResult(recipient.ID, status, messageID, errMsg); err != nil {
+			logrus.Errorf("Failed to record campaign recipient %s as %s: %v", recipient.ID, status, err)
+		}
+	}
+
+	prepared, err := renderCampaignMessage(campaign.Type, campaign.Message, recipient.Phone, recipient.Variables)
+	if err != nil {
+		record(domainCampaign.RecipientFailed, "", err.Error())
+		return false
+	}
+
+	registered, err := checkOnWhatsApp(sendCtx, client, recipient.Phone)
+	if err != nil {
+		record(domainCampaign.RecipientFailed, "", err.Error())
+		return false
+	}
+	if !registered {
+		record(domainCampaign.RecipientNotOnWhatsApp, "", "")
+		return false
+	}
+
+	response, err := prepared.send(sendCtx, service.sendService)
+	if err != nil {
+		record(domainCampaign.RecipientFailed, "", err.Error())
+		logrus.Warnf("Campaign %s message to %s failed: %v", campaign.ID, recipient.Phone, err)
+		return true
+	}
+
+	record(domainCampaign.RecipientSent, response.MessageID, "")
+	return true
+}
+
+// campaignInterval is the pause after a sent message: the rate limit spacing plus a random jitter.
+func campaignInterval(campaign *domainCampaign.Campaign) time.Duration {
+	interval := time.Minute / time.Duration(max(campaign.RatePerMinute, 1))
+	if campaign.JitterSeconds > 0 {
+		interval += rand.N(time.Duration(campaign.JitterSeconds) * time.Second)
+	}
+	return interval
+}
+
+// checkOnWhatsApp reports whether a user JID is registered. Unlike utils.IsOnWhatsapp it surfaces lookup
+// errors, so a network hiccup fails the recipient instead of labelling it not on WhatsApp. Groups and
+// newsletters are not looked up.
+func checkOnWhatsApp(ctx context.Context, client *whatsmeow.Client, jid string) (bool, error) {
+	phone, isUser := strings.CutSuffix(jid, "@"+types.DefaultUserServer)
+	if !isUser {
+		return true, nil
+	}
+	if client == nil {
+		return false, pkgError.ErrWaCLI
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, campaignCheckTimeout)
+	defer cancel()
+
+	results, err := tracing.Call(ctx, "whatsmeow.IsOnWhatsApp", func(ctx context.Context) ([]types.IsOnWhatsAppResponse, error) {
+		return client.IsOnWhatsApp(ctx, []string{"+" + phone})
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check whatsapp registration: %w", err)
+	}
+	for _, result := range results {
+		if !result.IsIn {
+			return false, nil
+		}
+	}
+	return len(results) > 0, nil
+}
+
+// renderCampaignMessage fills the {{name}} placeholders of every string in the message template with the
+// recipient's variables and decodes the result as a send request addressed to phone.
+func renderCampaignMessage(sendType string, message json.RawMessage, phone string, variables map[string]string) (*preparedSend, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(message, &fields); err != nil || fields == nil {
+		return nil, pkgError.ValidationError("message must be a JSON object with the fields of the send request")
+	}
+
+	var missing []string
+	rendered := renderTemplateValue(fields, variables, &missing)
+	if missing = utils.UniqueStrings(missing); len(missing) > 0 {
+		return nil, pkgError.ValidationError("missing template variables: " + strings.Join(missing, ", "))
+	}
+
+	fields = rendered.(map[string]any)
+	fields["phone"] = phone
+	// A stored template is rendered by the send service, so hand it the recipient's variables
+	if _, templated := fields["template_id"]; templated && fields["variables"] == nil {
+		fields["variables"] = variables
+	}
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode campaign message: %w", err)
+	}
+	return prepareSendRequest(sendType, payload)
+}
+
+func renderTemplateValue(value any, variables map[string]string, missing *[]string) any {
+	switch v := value.(type) {
+	case string:
+		rendered, absent := utils.RenderTemplate(v, variables)
+		*missing = append(*missing, absent...)
+		return rendered
+	case map[string]any:
+		for key, item := range v {
+			v[key] = renderTemplateValue(item, variables, missing)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = renderTemplateValue(item, variables, missing)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// parseRecipientsCSV reads a CSV upload with a header row. The phone column is required and every
+// other column becomes a template variable named after its header.
+func parseRecipientsCSV(file io.Reader) ([]domainCampaign.RecipientRequest, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("recipients_csv: cannot read header row: %v", err))
+	}
+
+	phoneColumn := -1
+	for i, column := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if strings.EqualFold(header[i], "phone") {
+			phoneColumn = i
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, pkgError.ValidationError("recipients_csv: header must contain a phone column")
+	}
+
+	var recipients []domainCampaign.RecipientRequest
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, pkgError.ValidationError(fmt.Sprintf("recipients_csv: line %d: %v", line, err))
+		}
+
+		recipient := domainCampaign.RecipientRequest{
+			Phone:     strings.TrimSpace(record[phoneColumn]),
+			Variables: make(map[string]string, len(header)-1),
+		}
+		for i, value := range record {
+			if i != phoneColumn && header[i] != "" {
+				recipient.Variables[header[i]] = value
+			}
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
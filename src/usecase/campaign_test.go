@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+)
+
+func TestRenderCampaignMessage_FillsVariables(t *testing.T) {
+	message := json.RawMessage(`{"phone":"ignored","message":"Hi {{name}}, order {{order_id}} is ready"}`)
+
+	prepared, err := renderCampaignMessage(domainSend.TypeMessage, message, "6289685028129",
+		map[string]string{"name": "Ana", "order_id": "A-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	request := prepared.request.(domainSend.MessageRequest)
+	if request.Phone != "6289685028129@s.whatsapp.net" {
+		t.Errorf("expected recipient phone, got %q", request.Phone)
+	}
+	if request.Message != "Hi Ana, order A-1 is ready" {
+		t.Errorf("unexpected rendered message %q", request.Message)
+	}
+}
+
+func TestRenderCampaignMessage_RendersNestedValues(t *testing.T) {
+	message := json.RawMessage(`{"question":"Lunch on {{day}}?","options":["{{day}} noon","skip"],"max_answer":1}`)
+
+	prepared, err := renderCampaignMessage(domainSend.TypePoll, message, "6289685028129", map[string]string{"day": "Friday"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	request := prepared.request.(domainSend.PollRequest)
+	if request.Question != "Lunch on Friday?" || request.Options[0] != "Friday noon" {
+		t.Errorf("unexpected rendered poll %+v", request)
+	}
+}
+
+func TestRenderCampaignMessage_ReportsMissingVariables(t *testing.T) {
+	message := json.RawMessage(`{"message":"Hi {{name}}, code {{code}} {{code}}"}`)
+
+	_, err := renderCampaignMessage(domainSend.TypeMessage, message, "6289685028129", map[string]string{"name": "Ana"})
+	if err == nil || !strings.Contains(err.Error(), "missing template variables: code") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+}
+
+func TestParseRecipientsCSV(t *testing.T) {
+	input := "\ufeffname, Phone ,order_id\nAna,6289685028129,A-1\nBudi,6281234567890,B-2\n"
+
+	recipients, err := parseRecipientsCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(recipients) != 2 {
+		t.Fatalf("expected 2 recipients, got %d", len(recipients))
+	}
+	if recipients[1].Phone != "6281234567890" || recipients[1].Variables["name"] != "Budi" || recipients[1].Variables["order_id"] != "B-2" {
+		t.Errorf("unexpected recipient %+v", recipients[1])
+	}
+	if _, ok := recipients[0].Variables["Phone"]; ok {
+		t.Error("phone column should not become a variable")
+	}
+}
+
+func TestParseRecipientsCSV_RequiresPhoneColumn(t *testing.T) {
+	if _, err := parseRecipientsCSV(strings.NewReader("name,number\nAna,62896\n")); err == nil {
+		t.Fatal("expected csv without phone column to be rejected")
+	}
+}
+
+func TestCampaignInterval(t *testing.T) {
+	campaign := &domainCampaign.Campaign{RatePerMinute: 30, JitterSeconds: 5}
+
+	for range 20 {
+		interval := campaignInterval(campaign)
+		if interval < 2*time.Second || interval >= 7*time.Second {
+			t.Fatalf("interval %s outside of rate spacing plus jitter", interval)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -26,6 +27,9 @@ func NewChatService(chatStorageRepo domainChatStorage.IChatStorageRepository) do
 }
 
 func (service serviceChat) ListChats(ctx context.Context, request domainChat.ListChatsRequest) (response domainChat.ListChatsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.ListChats")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateListChats(ctx, &request); err != nil {
 		return response, err
 	}
@@ -91,6 +95,9 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 }
 
 func (service serviceChat) GetChatMessages(ctx context.Context, request domainChat.GetChatMessagesRequest) (response domainChat.GetChatMessagesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.GetChatMessages")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateGetChatMessages(ctx, &request); err != nil {
 		return response, err
 	}
@@ -160,22 +167,10 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	// Convert entities to domain objects
 	messageInfos := make([]domainChat.MessageInfo, 0, len(messages))
 	for _, message := range messages {
-		messageInfo := domainChat.MessageInfo{
-			ID:         message.ID,
-			ChatJID:    message.ChatJID,
-			SenderJID:  message.Sender,
-			Content:    message.Content,
-			Timestamp:  message.Timestamp.Format(time.RFC3339),
-			IsFromMe:   message.IsFromMe,
-			MediaType:  message.MediaType,
-			Filename:   message.Filename,
-			URL:        message.URL,
-			FileLength: message.FileLength,
-			CreatedAt:  message.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:  message.UpdatedAt.Format(time.RFC3339),
-		}
-		messageInfos = append(messageInfos, messageInfo)
+		messageInfos = append(messageInfos, toMessageInfo(message))
 	}
+	service.attachReceipts(messages, messageInfos)
+	service.attachHistory(messages, messageInfos)
 
 	// Create chat info for response
 	chatInfo := domainChat.ChatInfo{
@@ -208,6 +203,189 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	return response, nil
 }
 
+func (service serviceChat) SearchMessages(ctx context.Context, request domainChat.SearchMessagesRequest) (response domainChat.SearchMessagesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.SearchMessages")
+	defer func() { tracing.End(span, err) }()
+
+	if err = validations.ValidateSearchMessages(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.MessageSearchFilter{
+		DeviceID:  deviceIDFromContext(ctx),
+		Query:     request.Query,
+		ChatJID:   request.ChatJID,
+		MediaType: request.MediaType,
+		Limit:     request.Limit,
+		Offset:    request.Offset,
+	}
+	if request.Sender != "" {
+		sender, err := utils.ParseJID(request.Sender)
+		if err != nil {
+			return response, pkgError.ValidationError(err.Error())
+		}
+		filter.Sender = sender.ToNonAD().String()
+	}
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, *request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, *request.EndTime)
+		filter.EndTime = &endTime
+	}
+
+	results, total, err := service.chatStorageRepo.SearchAllMessages(filter)
+	if err != nil {
+		logrus.WithError(err).WithField("query", request.Query).Error("Failed to search messages")
+		return response, err
+	}
+
+	messages := make([]*domainChatStorage.Message, 0, len(results))
+	messageInfos := make([]domainChat.MessageInfo, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, result.Message)
+		messageInfos = append(messageInfos, toMessageInfo(result.Message))
+	}
+	service.attachReceipts(messages, messageInfos)
+	service.attachHistory(messages, messageInfos)
+
+	response.Data = make([]domainChat.SearchMessageResult, 0, len(results))
+	for i, result := range results {
+		response.Data = append(response.Data, domainChat.SearchMessageResult{
+			MessageInfo: messageInfos[i],
+			Snippet:     result.Snippet,
+		})
+	}
+	response.Pagination = domainChat.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+	return response, nil
+}
+
+func toMessageInfo(message *domainChatStorage.Message) domainChat.MessageInfo {
+	return domainChat.MessageInfo{
+		ID:         message.ID,
+		ChatJID:    message.ChatJID,
+		SenderJID:  message.Sender,
+		Content:    message.Content,
+		Timestamp:  message.Timestamp.Format(time.RFC3339),
+		IsFromMe:   message.IsFromMe,
+		MediaType:  message.MediaType,
+		Filename:   message.Filename,
+		URL:        message.URL,
+		FileLength: message.FileLength,
+		EditedAt:   formatOptionalTime(message.EditedAt),
+		RevokedAt:  formatOptionalTime(message.RevokedAt),
+		RevokedBy:  message.RevokedBy,
+		CreatedAt:  message.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  message.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// attachReceipts fills the delivery status of outgoing messages. Failures are logged so the listing still succeeds.
+func (service serviceChat) attachReceipts(messages []*domainChatStorage.Message, messageInfos []domainChat.MessageInfo) {
+	var ids []string
+	for _, message := range messages {
+		if message.IsFromMe {
+			ids = append(ids, message.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	receiptsByMessage, err := service.chatStorageRepo.GetMessageReceipts("", ids)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to load message receipts")
+		return
+	}
+
+	for i, message := range messages {
+		if !message.IsFromMe {
+			continue
+		}
+
+		// Message IDs are only unique per device, so skip receipts recorded by other devices
+		var receipts []*domainChatStorage.MessageReceipt
+		for _, receipt := range receiptsByMessage[message.ID] {
+			if message.DeviceID == "" || receipt.DeviceID == message.DeviceID {
+				receipts = append(receipts, receipt)
+			}
+		}
+
+		messageInfos[i].Status = domainChatStorage.ReceiptStatus(receipts)
+		for _, receipt := range receipts {
+			messageInfos[i].Receipts = append(messageInfos[i].Receipts, domainChat.ReceiptInfo{
+				RecipientJID: receipt.RecipientJID,
+				Status:       receipt.Status(),
+				DeliveredAt:  formatOptionalTime(receipt.DeliveredAt),
+				ReadAt:       formatOptionalTime(receipt.ReadAt),
+				PlayedAt:     formatOptionalTime(receipt.PlayedAt),
+			})
+		}
+	}
+}
+
+// attachHistory fills the reactions and edit history of messages. Failures are logged so the listing still succeeds.
+func (service serviceChat) attachHistory(messages []*domainChatStorage.Message, messageInfos []domainChat.MessageInfo) {
+	if len(messages) == 0 {
+		return
+	}
+
+	var ids, editedIDs []string
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+		if message.EditedAt != nil {
+			editedIDs = append(editedIDs, message.ID)
+		}
+	}
+
+	reactionsByMessage, err := service.chatStorageRepo.GetMessageReactions("", ids)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to load message reactions")
+	}
+	var editsByMessage map[string][]*domainChatStorage.MessageEdit
+	if len(editedIDs) > 0 {
+		if editsByMessage, err = service.chatStorageRepo.GetMessageEdits("", editedIDs); err != nil {
+			logrus.WithError(err).Warn("Failed to load message edits")
+		}
+	}
+
+	for i, message := range messages {
+		// Message IDs are only unique per device and chat
+		for _, reaction := range reactionsByMessage[message.ID] {
+			if reaction.DeviceID != message.DeviceID || reaction.ChatJID != message.ChatJID {
+				continue
+			}
+			messageInfos[i].Reactions = append(messageInfos[i].Reactions, domainChat.ReactionInfo{
+				ReactorJID: reaction.ReactorJID,
+				Emoji:      reaction.Emoji,
+				ReactedAt:  reaction.ReactedAt.Format(time.RFC3339),
+			})
+		}
+		for _, edit := range editsByMessage[message.ID] {
+			if edit.DeviceID != message.DeviceID || edit.ChatJID != message.ChatJID {
+				continue
+			}
+			messageInfos[i].Edits = append(messageInfos[i].Edits, domainChat.EditInfo{
+				Revision: edit.Revision,
+				Content:  edit.Content,
+				EditedAt: edit.EditedAt.Format(time.RFC3339),
+			})
+		}
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func deviceIDFromContext(ctx context.Context) string {
 	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
 		if jid := inst.JID(); jid != "" {
@@ -219,6 +397,9 @@ func deviceIDFromContext(ctx context.Context) string {
 }
 
 func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinChatRequest) (response domainChat.PinChatResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.PinChat")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidatePinChat(ctx, &request); err != nil {
 		return response, err
 	}
@@ -229,7 +410,7 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 	}
 
 	// Validate JID and ensure connection
-	targetJID, err := utils.ValidateJidWithLogin(client, request.ChatJID)
+	targetJID, err := utils.ValidateJidWithLogin(ctx, client, request.ChatJID)
 	if err != nil {
 		return response, err
 	}
@@ -238,7 +419,10 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 	patchInfo := appstate.BuildPin(targetJID, request.Pinned)
 
 	// Send app state update
-	if err = client.SendAppState(ctx, patchInfo); err != nil {
+	err = tracing.Run(ctx, "whatsmeow.SendAppState", func(ctx context.Context) error {
+		return client.SendAppState(ctx, patchInfo)
+	})
+	if err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"chat_jid": request.ChatJID,
 			"pinned":   request.Pinned,
@@ -266,6 +450,9 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 }
 
 func (service serviceChat) SetDisappearingTimer(ctx context.Context, request domainChat.SetDisappearingTimerRequest) (response domainChat.SetDisappearingTimerResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.SetDisappearingTimer")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateSetDisappearingTimer(ctx, &request); err != nil {
 		return response, err
 	}
@@ -276,13 +463,16 @@ func (service serviceChat) SetDisappearingTimer(ctx context.Context, request dom
 	}
 
 	// Validate JID and ensure connection
-	targetJID, err := utils.ValidateJidWithLogin(client, request.ChatJID)
+	targetJID, err := utils.ValidateJidWithLogin(ctx, client, request.ChatJID)
 	if err != nil {
 		return response, err
 	}
 
 	// Set disappearing timer using whatsmeow
-	if err = client.SetDisappearingTimer(ctx, targetJID, time.Duration(request.TimerSeconds)*time.Second, time.Now()); err != nil {
+	err = tracing.Run(ctx, "whatsmeow.SetDisappearingTimer", func(ctx context.Context) error {
+		return client.SetDisappearingTimer(ctx, targetJID, time.Duration(request.TimerSeconds)*time.Second, time.Now())
+	})
+	if err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"chat_jid":      request.ChatJID,
 			"timer_seconds": request.TimerSeconds,
@@ -316,6 +506,9 @@ func (service serviceChat) SetDisappearingTimer(ctx context.Context, request dom
 }
 
 func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.ArchiveChatRequest) (response domainChat.ArchiveChatResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.ArchiveChat")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateArchiveChat(ctx, &request); err != nil {
 		return response, err
 	}
@@ -326,7 +519,7 @@ func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.A
 	}
 
 	// Validate JID and ensure connection
-	targetJID, err := utils.ValidateJidWithLogin(client, request.ChatJID)
+	targetJID, err := utils.ValidateJidWithLogin(ctx, client, request.ChatJID)
 	if err != nil {
 		return response, err
 	}
@@ -335,7 +528,10 @@ func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.A
 	patchInfo := appstate.BuildArchive(targetJID, request.Archived, time.Now(), nil)
 
 	// Send app state update
-	if err = client.SendAppState(ctx, patchInfo); err != nil {
+	err = tracing.Run(ctx, "whatsmeow.SendAppState", func(ctx context.Context) error {
+		return client.SendAppState(ctx, patchInfo)
+	})
+	if err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"chat_jid": request.ChatJID,
 			"archived": request.Archived,
@@ -362,7 +558,10 @@ func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.A
 	return response, nil
 }
 
-func (service serviceChat) UpsertChat(ctx context.Context, chat domainChat.ChatInfo) error {
+func (service serviceChat) UpsertChat(ctx context.Context, chat domainChat.ChatInfo) (err error) {
+	ctx, span := tracing.Start(ctx, "chat.UpsertChat")
+	defer func() { tracing.End(span, err) }()
+
 	storageChat := &domainChatStorage.Chat{
 		DeviceID: deviceIDFromContext(ctx),
 		JID:      chat.JID,
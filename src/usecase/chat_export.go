@@ -0,0 +1,341 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// exportTimeLayout matches the timestamps of WhatsApp's own "Export chat" text files
+const exportTimeLayout = "02/01/2006, 15:04"
+
+var unsafeFilenameChars = regexp.MustCompile(`[^\p{L}\p{N} ._-]+`)
+
+// ExportChat renders the stored history of a chat, oldest message first. With IncludeMedia the media
+// is downloaded again from WhatsApp and bundled with the transcript in a zip archive.
+func (service serviceChat) ExportChat(ctx context.Context, request domainChat.ExportChatRequest) (response domainChat.ExportChatResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.ExportChat")
+	defer func() { tracing.End(span, err) }()
+
+	if err = validations.ValidateExportChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	var chat *domainChatStorage.Chat
+	if deviceID != "" {
+		chat, err = service.chatStorageRepo.GetChatByDevice(deviceID, request.ChatJID)
+	} else {
+		chat, err = service.chatStorageRepo.GetChat(request.ChatJID)
+	}
+	if err != nil {
+		return response, fmt.Errorf("failed to get chat: %w", err)
+	}
+	if chat == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("chat with JID %s not found", request.ChatJID))
+	}
+
+	filter := &domainChatStorage.MessageFilter{DeviceID: deviceID, ChatJID: chat.JID}
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, *request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, *request.EndTime)
+		filter.EndTime = &endTime
+	}
+
+	messages, err := service.chatStorageRepo.GetMessages(filter)
+	if err != nil {
+		return response, fmt.Errorf("failed to get messages: %w", err)
+	}
+	slices.Reverse(messages)
+
+	// Media files keyed by message ID, with their key in the media storage
+	mediaFiles := make(map[string]string)
+	if request.IncludeMedia {
+		client := whatsapp.ClientFromContext(ctx)
+		if client == nil {
+			return response, pkgError.ErrWaCLI
+		}
+		for _, message := range messages {
+			if message.MediaType == "" || message.URL == "" || message.RevokedAt != nil {
+				continue
+			}
+			extracted, err := downloadStoredMedia(ctx, client, message)
+			if err != nil {
+				logrus.Warnf("Export of chat %s skips media of message %s: %v", chat.JID, message.ID, err)
+				continue
+			}
+			mediaFiles[message.ID] = extracted.MediaPath
+		}
+	}
+
+	// Media is stored in the archive root under a name unique to the message, like WhatsApp does
+	attachments := make(map[string]string, len(mediaFiles))
+	for id, path := range mediaFiles {
+		attachments[id] = exportMediaName(id, path)
+	}
+
+	var transcript []byte
+	var extension, contentType string
+	switch request.Format {
+	case domainChat.ExportFormatJSON:
+		transcript, err = service.renderChatJSON(chat, messages, attachments)
+		extension, contentType = "json", "application/json"
+	case domainChat.ExportFormatHTML:
+		transcript, err = renderChatHTML(chat, messages, attachments)
+		extension, contentType = "html", "text/html; charset=utf-8"
+	default:
+		transcript = renderChatText(messages, attachments)
+		extension, contentType = "txt", "text/plain; charset=utf-8"
+	}
+	if err != nil {
+		return response, fmt.Errorf("failed to render chat export: %w", err)
+	}
+
+	baseName := exportBaseName(chat)
+	if !request.IncludeMedia {
+		response.Filename = baseName + "." + extension
+		response.ContentType = contentType
+		response.Content = transcript
+		return response, nil
+	}
+
+	archive, err := zipChatExport(ctx, baseName+"."+extension, transcript, mediaFiles, attachments)
+	if err != nil {
+		return response, fmt.Errorf("failed to build chat export archive: %w", err)
+	}
+	response.Filename = baseName + ".zip"
+	response.ContentType = "application/zip"
+	response.Content = archive
+	return response, nil
+}
+
+func (service serviceChat) renderChatJSON(chat *domainChatStorage.Chat, messages []*domainChatStorage.Message, attachments map[string]string) ([]byte, error) {
+	messageInfos := make([]domainChat.MessageInfo, 0, len(messages))
+	for _, message := range messages {
+		messageInfos = append(messageInfos, toMessageInfo(message))
+	}
+	service.attachReceipts(messages, messageInfos)
+	service.attachHistory(messages, messageInfos)
+
+	document := domainChat.ExportChatDocument{
+		Chat: domainChat.ChatInfo{
+			JID:                 chat.JID,
+			Name:                chat.Name,
+			LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
+			EphemeralExpiration: chat.EphemeralExpiration,
+			CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
+		},
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Messages:   make([]domainChat.ExportMessage, 0, len(messages)),
+	}
+	for i, message := range messages {
+		document.Messages = append(document.Messages, domainChat.ExportMessage{
+			MessageInfo: messageInfos[i],
+			MediaFile:   attachments[message.ID],
+		})
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// renderChatText writes the transcript in the format of WhatsApp's own chat export on Android
+func renderChatText(messages []*domainChatStorage.Message, attachments map[string]string) []byte {
+	var builder strings.Builder
+	for _, message := range messages {
+		builder.WriteString(message.Timestamp.Local().Format(exportTimeLayout))
+		builder.WriteString(" - ")
+		builder.WriteString(exportSenderName(message))
+		builder.WriteString(": ")
+		builder.WriteString(exportMessageText(message, attachments[message.ID]))
+		builder.WriteString("\n")
+	}
+	return []byte(builder.String())
+}
+
+func exportMessageText(message *domainChatStorage.Message, attachment string) string {
+	if message.RevokedAt != nil {
+		return "This message was deleted"
+	}
+
+	var parts []string
+	if message.MediaType != "" {
+		if attachment != "" {
+			parts = append(parts, attachment+" (file attached)")
+		} else {
+			parts = append(parts, "<Media omitted>")
+		}
+	}
+	if message.Content != "" {
+		parts = append(parts, message.Content)
+	}
+
+	text := strings.Join(parts, "\n")
+	if message.EditedAt != nil {
+		text += " <This message was edited>"
+	}
+	return text
+}
+
+// exportSenderName shows the sender as a phone number, falling back to the raw user part for LIDs
+func exportSenderName(message *domainChatStorage.Message) string {
+	if message.IsFromMe {
+		return "You"
+	}
+	jid, err := types.ParseJID(message.Sender)
+	if err != nil || jid.User == "" {
+		return message.Sender
+	}
+	if jid.Server == types.DefaultUserServer {
+		return "+" + jid.User
+	}
+	return jid.User
+}
+
+var chatExportHTMLTemplate = template.Must(template.New("chat").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body{margin:0;background:#efeae2;font-family:-apple-system,"Segoe UI",Roboto,Helvetica,Arial,sans-serif;font-size:14px;color:#111b21}
+header{position:sticky;top:0;background:#008069;color:#fff;padding:12px 20px}
+header h1{margin:0;font-size:18px}
+header p{margin:2px 0 0;font-size:12px;opacity:.85}
+main{max-width:860px;margin:0 auto;padding:16px}
+.message{max-width:75%;margin:4px 0;padding:6px 9px 4px;border-radius:8px;background:#fff;box-shadow:0 1px .5px rgba(11,20,26,.13);clear:both;float:left}
+.message.me{background:#d9fdd3;float:right}
+.sender{font-size:12.5px;font-weight:600;color:#1f7aec}
+.text{white-space:pre-wrap;word-wrap:break-word}
+.deleted{font-style:italic;color:#667781}
+.meta{font-size:11px;color:#667781;text-align:right;margin-top:2px}
+img,video{max-width:100%;border-radius:6px;display:block}
+.clear{clear:both}
+</style>
+</head>
+<body>
+<header><h1>{{.Title}}</h1><p>{{.Subtitle}}</p></header>
+<main>
+{{range .Messages}}<div class="message{{if .FromMe}} me{{end}}">
+{{if not .FromMe}}<div class="sender">{{.Sender}}</div>{{end}}{{if .Deleted}}<div class="text deleted">This message was deleted</div>{{else}}{{if .Attachment}}{{if eq .MediaType "image" "sticker"}}<img src="{{.Attachment}}" alt="{{.Attachment}}">{{else if eq .MediaType "video"}}<video controls src="{{.Attachment}}"></video>{{else if eq .MediaType "audio"}}<audio controls src="{{.Attachment}}"></audio>{{else}}<a href="{{.Attachment}}">{{.Attachment}}</a>{{end}}{{else if .MediaType}}<div class="text deleted">&lt;Media omitted&gt;</div>{{end}}{{if .Text}}<div class="text">{{.Text}}</div>{{end}}{{end}}
+<div class="meta">{{if .Edited}}Edited · {{end}}{{.Time}}</div>
+</div>
+{{end}}<div class="clear"></div>
+</main>
+</body>
+</html>
+`))
+
+type chatExportHTMLMessage struct {
+	Sender     string
+	FromMe     bool
+	Time       string
+	Text       string
+	MediaType  string
+	Attachment string
+	Deleted    bool
+	Edited     bool
+}
+
+// renderChatHTML writes a transcript that needs nothing but the media files next to it
+func renderChatHTML(chat *domainChatStorage.Chat, messages []*domainChatStorage.Message, attachments map[string]string) ([]byte, error) {
+	page := struct {
+		Title    string
+		Subtitle string
+		Messages []chatExportHTMLMessage
+	}{
+		Title:    chat.Name,
+		Subtitle: fmt.Sprintf("%s · %d messages · exported %s", chat.JID, len(messages), time.Now().Local().Format(exportTimeLayout)),
+	}
+	if page.Title == "" {
+		page.Title = chat.JID
+	}
+
+	for _, message := range messages {
+		page.Messages = append(page.Messages, chatExportHTMLMessage{
+			Sender:     exportSenderName(message),
+			FromMe:     message.IsFromMe,
+			Time:       message.Timestamp.Local().Format(exportTimeLayout),
+			Text:       message.Content,
+			MediaType:  message.MediaType,
+			Attachment: attachments[message.ID],
+			Deleted:    message.RevokedAt != nil,
+			Edited:     message.EditedAt != nil,
+		})
+	}
+
+	var buffer bytes.Buffer
+	if err := chatExportHTMLTemplate.Execute(&buffer, page); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// zipChatExport bundles the transcript with the media files, stored under their attachment names
+func zipChatExport(ctx context.Context, transcriptName string, transcript []byte, mediaFiles, attachments map[string]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	writer, err := archive.Create(transcriptName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(transcript); err != nil {
+		return nil, err
+	}
+
+	for id, path := range mediaFiles {
+		content, err := mediastorage.Active().Open(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read media %s: %w", path, err)
+		}
+		writer, err := archive.Create(attachments[id])
+		if err == nil {
+			_, err = io.Copy(writer, content)
+		}
+		_ = content.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func exportMediaName(messageID, path string) string {
+	return unsafeFilenameChars.ReplaceAllString(messageID, "_") + filepath.Ext(path)
+}
+
+// exportBaseName names the export after the chat like WhatsApp does, keeping it safe for file systems and headers
+func exportBaseName(chat *domainChatStorage.Chat) string {
+	name := chat.Name
+	if name == "" {
+		name = chat.JID
+	}
+	name = strings.TrimSpace(unsafeFilenameChars.ReplaceAllString(name, "_"))
+	return "WhatsApp Chat - " + name
+}
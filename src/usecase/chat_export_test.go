@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+)
+
+func TestRenderChatText_MatchesWhatsAppFormat(t *testing.T) {
+	sentAt := time.Date(2024, 3, 5, 9, 7, 0, 0, time.Local)
+	revokedAt := sentAt.Add(time.Minute)
+	messages := []*domainChatStorage.Message{
+		{ID: "1", Sender: "6289685028129@s.whatsapp.net", Content: "Hello", Timestamp: sentAt},
+		{ID: "2", IsFromMe: true, Content: "Hi there", Timestamp: sentAt, EditedAt: &revokedAt},
+		{ID: "3", Sender: "123456789@lid", MediaType: "image", Content: "look", Timestamp: sentAt},
+		{ID: "4", Sender: "6289685028129@s.whatsapp.net", MediaType: "document", Timestamp: sentAt},
+		{ID: "5", Sender: "6289685028129@s.whatsapp.net", Content: "oops", Timestamp: sentAt, RevokedAt: &revokedAt},
+	}
+
+	got := string(renderChatText(messages, map[string]string{"4": "4.pdf"}))
+	want := "05/03/2024, 09:07 - +6289685028129: Hello\n" +
+		"05/03/2024, 09:07 - You: Hi there <This message was edited>\n" +
+		"05/03/2024, 09:07 - 123456789: <Media omitted>\nlook\n" +
+		"05/03/2024, 09:07 - +6289685028129: 4.pdf (file attached)\n" +
+		"05/03/2024, 09:07 - +6289685028129: This message was deleted\n"
+	if got != want {
+		t.Errorf("unexpected transcript:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderChatHTML_EscapesContent(t *testing.T) {
+	chat := &domainChatStorage.Chat{JID: "6289685028129@s.whatsapp.net", Name: "Ana"}
+	messages := []*domainChatStorage.Message{
+		{ID: "1", Sender: chat.JID, Content: "<script>alert(1)</script>", Timestamp: time.Now()},
+		{ID: "2", Sender: chat.JID, MediaType: "image", Timestamp: time.Now()},
+	}
+
+	page, err := renderChatHTML(chat, messages, map[string]string{"2": "2.jpg"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(string(page), "<script>") {
+		t.Errorf("expected message content to be escaped")
+	}
+	if !strings.Contains(string(page), `<img src="2.jpg"`) {
+		t.Errorf("expected attached image to be embedded")
+	}
+}
+
+func TestExportBaseName_SanitizesChatName(t *testing.T) {
+	if got := exportBaseName(&domainChatStorage.Chat{JID: "1@g.us", Name: "Family / Friends: 2024"}); got != "WhatsApp Chat - Family _ Friends_ 2024" {
+		t.Errorf("unexpected base name %q", got)
+	}
+	if got := exportBaseName(&domainChatStorage.Chat{JID: "1@g.us"}); got != "WhatsApp Chat - 1_g.us" {
+		t.Errorf("expected JID fallback, got %q", got)
+	}
+}
+
+func TestZipChatExport_BundlesMediaAtRoot(t *testing.T) {
+	dir := t.TempDir()
+	mediastorage.SetActive(mediastorage.NewLocal(dir))
+	t.Cleanup(func() { mediastorage.SetActive(mediastorage.NewLocal("")) })
+	mediaPath := "statics/media/photo.jpg"
+	if err := os.MkdirAll(filepath.Join(dir, "statics", "media"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, mediaPath), []byte("jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zipChatExport(context.Background(), "chat.txt", []byte("transcript"),
+		map[string]string{"ABC": mediaPath}, map[string]string{"ABC": exportMediaName("ABC", mediaPath)})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("expected a valid zip, got %v", err)
+	}
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[file.Name] = string(content)
+	}
+	if files["chat.txt"] != "transcript" || files["ABC.jpg"] != "jpeg" || len(files) != 2 {
+		t.Errorf("unexpected archive contents %v", files)
+	}
+}
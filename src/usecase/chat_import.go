@@ -0,0 +1,559 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/encryption"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// importedMessageIDPrefix marks messages that came from an imported transcript rather than from WhatsApp
+const importedMessageIDPrefix = "IMPORT-"
+
+// Message lines of WhatsApp's "Export chat" transcripts. Android writes "05/03/2024, 09:07 - Ana: Hi" and iOS
+// writes "[05/03/2024, 09:07:31] Ana: Hi"; depending on the locale both use 12-hour clocks or two-digit years.
+var (
+	androidTranscriptLine = regexp.MustCompile(`^(\d{1,2})[./-](\d{1,2})[./-](\d{2,4}),? (\d{1,2}):(\d{2})(?::(\d{2}))?(?: ?([AaPp])\.? ?[Mm]\.?)? - (.*)$`)
+	iosTranscriptLine     = regexp.MustCompile(`^\[(\d{1,2})[./-](\d{1,2})[./-](\d{2,4}),? (\d{1,2}):(\d{2})(?::(\d{2}))?(?: ?([AaPp])\.? ?[Mm]\.?)?\] (.*)$`)
+	iosAttachment         = regexp.MustCompile(`<attached: ([^>]+)>`)
+	androidAttachment     = regexp.MustCompile(`^(.+) \(file attached\)$`)
+	phoneSenderName       = regexp.MustCompile(`^\+?[\d ()-]{7,}$`)
+	nonDigits             = regexp.MustCompile(`\D`)
+)
+
+// Placeholders WhatsApp writes for media that was left out of an export
+var omittedMediaTypes = map[string]string{
+	"<Media omitted>":  "document",
+	"image omitted":    "image",
+	"video omitted":    "video",
+	"GIF omitted":      "video",
+	"audio omitted":    "audio",
+	"sticker omitted":  "sticker",
+	"document omitted": "document",
+}
+
+// transcriptEntry is a message line of a transcript with its continuation lines. The day and month
+// order of the date is only known once every line is read.
+type transcriptEntry struct {
+	date     [3]int
+	clock    [3]int
+	meridiem string
+	sender   string
+	text     string
+	system   bool
+}
+
+// ImportChat stores the messages of a WhatsApp "Export chat" archive. Messages already in the chat storage,
+// for example from a history sync, are recognized by their minute, content and media and skipped.
+func (service serviceChat) ImportChat(ctx context.Context, request domainChat.ImportChatRequest) (response domainChat.ImportChatResponse, err error) {
+	ctx, span := tracing.Start(ctx, "chat.ImportChat")
+	defer func() { tracing.End(span, err) }()
+
+	if err = validations.ValidateImportChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	chatJID, err := types.ParseJID(request.ChatJID)
+	if err != nil || chatJID.User == "" {
+		return response, pkgError.ValidationError(fmt.Sprintf("chat_jid: %s is not a valid JID.", request.ChatJID))
+	}
+	chatJID = chatJID.ToNonAD()
+
+	transcript, mediaFiles, chatName, err := openChatArchive(request.FileName, request.File)
+	if err != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("file: %v.", err))
+	}
+	if request.ChatName != "" {
+		chatName = request.ChatName
+	}
+
+	location := time.Local
+	if request.Timezone != "" {
+		location, _ = time.LoadLocation(request.Timezone)
+	}
+
+	entries := parseChatTranscript(transcript)
+	dateOrder := request.DateOrder
+	if dateOrder == "" {
+		dateOrder = detectDateOrder(entries)
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	deviceID := deviceIDFromContext(ctx)
+	selfJID := deviceID
+	if client != nil && client.Store.ID != nil {
+		selfJID = client.Store.ID.ToNonAD().String()
+	}
+	senders, unmapped := resolveTranscriptSenders(entries, chatJID, request.Senders, request.SelfName,
+		importContactNames(ctx, client))
+
+	response.ChatJID = chatJID.String()
+	response.UnmappedSenders = unmapped
+
+	var messages []*domainChatStorage.Message
+	occurrences := make(map[string]int)
+	for _, entry := range entries {
+		timestamp, ok := entry.time(dateOrder, location)
+		attachment, mediaType, content, deleted := transcriptContent(entry.text)
+		if entry.system || deleted || !ok || (content == "" && mediaType == "") {
+			response.Skipped++
+			continue
+		}
+
+		message := &domainChatStorage.Message{
+			ChatJID:   chatJID.String(),
+			DeviceID:  deviceID,
+			Sender:    entry.sender,
+			Content:   content,
+			Timestamp: timestamp.UTC(),
+			MediaType: mediaType,
+			Filename:  attachment,
+		}
+		if jid, found := senders[entry.sender]; found {
+			message.Sender = jid
+			message.IsFromMe = jid == ""
+		}
+		if message.IsFromMe {
+			message.Sender = selfJID
+		}
+
+		// Identical lines get distinct but stable IDs, so importing the same archive again updates them
+		key := fmt.Sprintf("%d|%s|%s|%s", message.Timestamp.Unix(), message.Sender, message.MediaType, message.Content)
+		message.ID = importMessageID(message.ChatJID, key, occurrences[key])
+		occurrences[key]++
+		messages = append(messages, message)
+	}
+
+	candidates := len(messages)
+	messages, err = service.skipStoredMessages(deviceID, chatJID.String(), messages)
+	if err != nil {
+		return response, err
+	}
+	response.Duplicates = candidates - len(messages)
+
+	if len(messages) == 0 {
+		return response, nil
+	}
+
+	mediaDir := filepath.Join(config.PathMedia, utils.ExtractPhoneNumber(chatJID.String()), "imported")
+	for _, message := range messages {
+		file, ok := mediaFiles[message.Filename]
+		if message.Filename == "" || !ok {
+			continue
+		}
+		size, err := extractImportedMedia(file, mediaDir)
+		if err != nil {
+			logrus.Warnf("Import of chat %s skips media %s: %v", chatJID, message.Filename, err)
+			continue
+		}
+		message.FileLength = size
+		response.MediaFiles++
+	}
+
+	latest := messages[len(messages)-1].Timestamp
+	for _, message := range messages {
+		if message.Timestamp.After(latest) {
+			latest = message.Timestamp
+		}
+	}
+	err = service.chatStorageRepo.UpsertChat(&domainChatStorage.Chat{
+		DeviceID:        deviceID,
+		JID:             chatJID.String(),
+		Name:            chatName,
+		LastMessageTime: latest,
+	})
+	if err != nil {
+		return response, fmt.Errorf("failed to store chat: %w", err)
+	}
+	if err := service.chatStorageRepo.StoreMessagesBatch(messages); err != nil {
+		return response, fmt.Errorf("failed to store messages: %w", err)
+	}
+
+	response.Imported = len(messages)
+	logrus.Infof("Imported %d messages into chat %s, skipped %d duplicates", response.Imported, chatJID, response.Duplicates)
+	return response, nil
+}
+
+// skipStoredMessages drops the messages the chat storage already has. Transcripts only keep the minute
+// of a message, so stored messages are matched on their minute, media and content, once each.
+func (service serviceChat) skipStoredMessages(deviceID, chatJID string, messages []*domainChatStorage.Message) ([]*domainChatStorage.Message, error) {
+	if len(messages) == 0 {
+		return messages, nil
+	}
+
+	start, end := messages[0].Timestamp, messages[0].Timestamp
+	for _, message := range messages {
+		if message.Timestamp.Before(start) {
+			start = message.Timestamp
+		}
+		if message.Timestamp.After(end) {
+			end = message.Timestamp
+		}
+	}
+	start = start.Truncate(time.Minute)
+	end = end.Truncate(time.Minute).Add(time.Minute)
+
+	stored, err := service.chatStorageRepo.GetMessages(&domainChatStorage.MessageFilter{
+		DeviceID:  deviceID,
+		ChatJID:   chatJID,
+		StartTime: &start,
+		EndTime:   &end,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored messages: %w", err)
+	}
+
+	known := make(map[string]int, len(stored))
+	for _, message := range stored {
+		known[importDedupKey(message)]++
+	}
+
+	fresh := messages[:0]
+	for _, message := range messages {
+		key := importDedupKey(message)
+		if known[key] > 0 {
+			known[key]--
+			continue
+		}
+		fresh = append(fresh, message)
+	}
+	return fresh, nil
+}
+
+func importDedupKey(message *domainChatStorage.Message) string {
+	return fmt.Sprintf("%d|%t|%s", message.Timestamp.UTC().Truncate(time.Minute).Unix(), message.MediaType != "",
+		strings.TrimSpace(message.Content))
+}
+
+func importMessageID(chatJID, key string, occurrence int) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", chatJID, key, occurrence)))
+	return importedMessageIDPrefix + strings.ToUpper(hex.EncodeToString(hash[:10]))
+}
+
+// openChatArchive returns the transcript and media of an export, which is either a zip archive or a bare
+// transcript, and the chat name WhatsApp put in the file name
+func openChatArchive(fileName string, content []byte) (string, map[string]*zip.File, string, error) {
+	if !bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		return strings.TrimPrefix(string(content), "\ufeff"), nil, chatNameFromExportFile(fileName), nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return "", nil, "", fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	// iOS names the transcript _chat.txt, Android after the chat
+	var transcriptFile *zip.File
+	for _, file := range archive.File {
+		if strings.HasSuffix(strings.ToLower(file.Name), ".txt") && (transcriptFile == nil || path.Base(file.Name) == "_chat.txt") {
+			transcriptFile = file
+		}
+	}
+	if transcriptFile == nil {
+		return "", nil, "", fmt.Errorf("archive has no chat transcript")
+	}
+
+	reader, err := transcriptFile.Open()
+	if err != nil {
+		return "", nil, "", err
+	}
+	defer reader.Close()
+	transcript, err := io.ReadAll(reader)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	mediaFiles := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		if file != transcriptFile && !file.FileInfo().IsDir() {
+			mediaFiles[path.Base(file.Name)] = file
+		}
+	}
+
+	chatName := chatNameFromExportFile(transcriptFile.Name)
+	if chatName == "" {
+		chatName = chatNameFromExportFile(fileName)
+	}
+	return strings.TrimPrefix(string(transcript), "\ufeff"), mediaFiles, chatName, nil
+}
+
+func chatNameFromExportFile(fileName string) string {
+	name := strings.TrimSuffix(path.Base(fileName), path.Ext(fileName))
+	for _, prefix := range []string{"WhatsApp Chat - ", "WhatsApp Chat with "} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(name, prefix))
+		}
+	}
+	return ""
+}
+
+// parseChatTranscript splits a transcript into messages. Lines that do not start with a timestamp continue
+// the message before them.
+func parseChatTranscript(transcript string) []*transcriptEntry {
+	spaces := strings.NewReplacer("\u202f", " ", "\u00a0", " ", "\r", "")
+	var entries []*transcriptEntry
+	for _, line := range strings.Split(spaces.Replace(transcript), "\n") {
+		// iOS prefixes attachments and notices with a left-to-right mark
+		marked := strings.HasPrefix(line, "\u200e")
+		line = strings.TrimLeft(line, "\u200e\u200f")
+
+		match := androidTranscriptLine.FindStringSubmatch(line)
+		if match == nil {
+			match = iosTranscriptLine.FindStringSubmatch(line)
+		}
+		if match == nil {
+			if len(entries) > 0 {
+				entries[len(entries)-1].text += "\n" + stripDirectionMarks(line)
+			}
+			continue
+		}
+
+		entry := &transcriptEntry{meridiem: strings.ToLower(match[7])}
+		for i := range 3 {
+			entry.date[i], _ = strconv.Atoi(match[1+i])
+			entry.clock[i], _ = strconv.Atoi(match[4+i])
+		}
+
+		sender, text, found := strings.Cut(match[8], ": ")
+		marked = marked || strings.HasPrefix(text, "\u200e")
+		entry.sender = strings.TrimSpace(stripDirectionMarks(sender))
+		entry.text = stripDirectionMarks(text)
+		entry.system = !found || (marked && !isTranscriptMedia(entry.text))
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range entries {
+		entry.text = strings.TrimRight(entry.text, "\n ")
+	}
+	return entries
+}
+
+func stripDirectionMarks(text string) string {
+	return strings.NewReplacer("\u200e", "", "\u200f", "").Replace(text)
+}
+
+func isTranscriptMedia(text string) bool {
+	attachment, mediaType, _, _ := transcriptContent(text)
+	return attachment != "" || mediaType != ""
+}
+
+// detectDateOrder tells day-first from month-first dates by the first day above 12, defaulting to day-first
+func detectDateOrder(entries []*transcriptEntry) string {
+	for _, entry := range entries {
+		if entry.date[0] > 12 {
+			return domainChat.ImportDateOrderDMY
+		}
+		if entry.date[1] > 12 {
+			return domainChat.ImportDateOrderMDY
+		}
+	}
+	return domainChat.ImportDateOrderDMY
+}
+
+func (entry *transcriptEntry) time(dateOrder string, location *time.Location) (time.Time, bool) {
+	day, month, year := entry.date[0], entry.date[1], entry.date[2]
+	if dateOrder == domainChat.ImportDateOrderMDY {
+		day, month = month, day
+	}
+	if year < 100 {
+		year += 2000
+	}
+
+	hour := entry.clock[0]
+	switch entry.meridiem {
+	case "p":
+		if hour < 12 {
+			hour += 12
+		}
+	case "a":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+
+	timestamp := time.Date(year, time.Month(month), day, hour, entry.clock[1], entry.clock[2], 0, location)
+	valid := month >= 1 && month <= 12 && timestamp.Day() == day && hour < 24 && entry.clock[1] < 60
+	return timestamp, valid
+}
+
+// transcriptContent splits the text of a transcript message into its attachment, media type and caption
+func transcriptContent(text string) (attachment, mediaType, content string, deleted bool) {
+	switch strings.TrimSuffix(text, ".") {
+	case "This message was deleted", "You deleted this message":
+		return "", "", "", true
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(text, "<This message was edited>"))
+
+	if match := iosAttachment.FindStringSubmatch(text); match != nil {
+		attachment = strings.TrimSpace(match[1])
+		content = strings.TrimSpace(strings.Replace(text, match[0], "", 1))
+		return attachment, mediaTypeFromFilename(attachment), content, false
+	}
+
+	first, rest, _ := strings.Cut(text, "\n")
+	if match := androidAttachment.FindStringSubmatch(first); match != nil {
+		attachment = strings.TrimSpace(match[1])
+		return attachment, mediaTypeFromFilename(attachment), strings.TrimSpace(rest), false
+	}
+	if omitted, ok := omittedMediaTypes[strings.TrimSpace(first)]; ok {
+		return "", omitted, strings.TrimSpace(rest), false
+	}
+	return "", "", text, false
+}
+
+func mediaTypeFromFilename(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".heic":
+		return "image"
+	case ".webp":
+		return "sticker"
+	case ".mp4", ".mov", ".3gp", ".mkv", ".gif":
+		return "video"
+	case ".opus", ".ogg", ".m4a", ".mp3", ".aac", ".amr", ".wav":
+		return "audio"
+	}
+	return "document"
+}
+
+// resolveTranscriptSenders maps the sender names of a transcript to JIDs, an empty JID standing for the
+// account itself. Names are matched against the given mapping, "You" or selfName, phone numbers and
+// contact names; in a private chat a single name left over is the other participant. Names that cannot
+// be mapped are returned sorted and kept as sender.
+func resolveTranscriptSenders(entries []*transcriptEntry, chatJID types.JID, mapping map[string]string, selfName string, contacts map[string]string) (map[string]string, []string) {
+	resolved := make(map[string]string)
+	var unmapped []string
+	for _, entry := range entries {
+		name := entry.sender
+		if _, done := resolved[name]; done || entry.system || name == "" || slices.Contains(unmapped, name) {
+			continue
+		}
+
+		if value, ok := mapping[name]; ok {
+			if jid, ok := senderJIDFromValue(value); ok {
+				resolved[name] = jid
+				continue
+			}
+		}
+		if name == "You" || (selfName != "" && strings.EqualFold(name, selfName)) {
+			resolved[name] = ""
+			continue
+		}
+		if phoneSenderName.MatchString(name) {
+			if jid, ok := senderJIDFromValue(name); ok {
+				resolved[name] = jid
+				continue
+			}
+		}
+		if jid := contacts[strings.ToLower(name)]; jid != "" {
+			resolved[name] = jid
+			continue
+		}
+		unmapped = append(unmapped, name)
+	}
+
+	if chatJID.Server == types.DefaultUserServer && len(unmapped) == 1 {
+		resolved[unmapped[0]] = chatJID.String()
+		unmapped = nil
+	}
+	sort.Strings(unmapped)
+	if unmapped == nil {
+		unmapped = []string{}
+	}
+	return resolved, unmapped
+}
+
+// senderJIDFromValue accepts a JID or a phone number in any notation
+func senderJIDFromValue(value string) (string, bool) {
+	if strings.Contains(value, "@") {
+		jid, err := types.ParseJID(strings.TrimSpace(value))
+		if err != nil || jid.User == "" {
+			return "", false
+		}
+		return jid.ToNonAD().String(), true
+	}
+
+	digits := nonDigits.ReplaceAllString(value, "")
+	if len(digits) < 7 || len(digits) > 15 {
+		return "", false
+	}
+	return types.NewJID(digits, types.DefaultUserServer).String(), true
+}
+
+// importContactNames maps the lowercased names of the address book to JIDs, leaving out names shared by
+// several contacts
+func importContactNames(ctx context.Context, client *whatsmeow.Client) map[string]string {
+	names := make(map[string]string)
+	if client == nil || client.Store == nil || client.Store.Contacts == nil {
+		return names
+	}
+
+	contacts, err := client.Store.Contacts.GetAllContacts(ctx)
+	if err != nil {
+		logrus.Warnf("Failed to load contacts for chat import: %v", err)
+		return names
+	}
+
+	for jid, contact := range contacts {
+		for _, name := range []string{contact.FullName, contact.PushName, contact.BusinessName} {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if key == "" {
+				continue
+			}
+			if existing, ok := names[key]; ok && existing != jid.ToNonAD().String() {
+				names[key] = ""
+				continue
+			}
+			names[key] = jid.ToNonAD().String()
+		}
+	}
+	return names
+}
+
+func extractImportedMedia(file *zip.File, dir string) (uint64, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	target, err := encryption.CreateFile(filepath.Join(dir, path.Base(file.Name)), 0600)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := io.Copy(target, reader)
+	if err != nil {
+		_ = target.Close()
+		return 0, err
+	}
+	return uint64(size), target.Close()
+}
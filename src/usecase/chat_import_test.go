@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestParseChatTranscript_Android(t *testing.T) {
+	transcript := "05/03/2024, 09:07 - Messages and calls are end-to-end encrypted. Tap to learn more.\n" +
+		"05/03/2024, 09:07 - Ana: Hello\n" +
+		"second line\n" +
+		"05/03/2024, 21:15 - +62 896-8502-8129: IMG-20240305-WA0001.jpg (file attached)\n" +
+		"look at this\n"
+
+	entries := parseChatTranscript(transcript)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if !entries[0].system {
+		t.Errorf("expected the encryption notice to be a system message")
+	}
+	if entries[1].sender != "Ana" || entries[1].text != "Hello\nsecond line" {
+		t.Errorf("unexpected multi-line entry %+v", entries[1])
+	}
+
+	attachment, mediaType, content, _ := transcriptContent(entries[2].text)
+	if attachment != "IMG-20240305-WA0001.jpg" || mediaType != "image" || content != "look at this" {
+		t.Errorf("unexpected attachment %q, media type %q, content %q", attachment, mediaType, content)
+	}
+}
+
+func TestParseChatTranscript_IOS(t *testing.T) {
+	transcript := "[3/5/24, 9:07:31 PM] Family: \u200eMessages and calls are end-to-end encrypted.\n" +
+		"\u200e[3/5/24, 9:08:02 PM] Ana: \u200e<attached: 00000003-PHOTO-2024-03-05-21-08-02.jpg>\n" +
+		"[3/13/24, 12:01:00 AM] Ben: Hi all\n"
+
+	entries := parseChatTranscript(transcript)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if !entries[0].system || entries[1].system {
+		t.Errorf("expected only the notice to be a system message")
+	}
+
+	order := detectDateOrder(entries)
+	if order != domainChat.ImportDateOrderMDY {
+		t.Fatalf("expected month-first dates, got %s", order)
+	}
+	timestamp, ok := entries[1].time(order, time.UTC)
+	if !ok || !timestamp.Equal(time.Date(2024, 3, 5, 21, 8, 2, 0, time.UTC)) {
+		t.Errorf("unexpected timestamp %v", timestamp)
+	}
+	timestamp, _ = entries[2].time(order, time.UTC)
+	if !timestamp.Equal(time.Date(2024, 3, 13, 0, 1, 0, 0, time.UTC)) {
+		t.Errorf("expected midnight for 12 AM, got %v", timestamp)
+	}
+
+	attachment, mediaType, _, _ := transcriptContent(entries[1].text)
+	if attachment != "00000003-PHOTO-2024-03-05-21-08-02.jpg" || mediaType != "image" {
+		t.Errorf("unexpected attachment %q of type %q", attachment, mediaType)
+	}
+}
+
+func TestTranscriptContent_Placeholders(t *testing.T) {
+	if _, mediaType, _, _ := transcriptContent("<Media omitted>"); mediaType != "document" {
+		t.Errorf("expected omitted media to be kept as media, got %q", mediaType)
+	}
+	if _, _, _, deleted := transcriptContent("This message was deleted"); !deleted {
+		t.Errorf("expected deleted message to be recognized")
+	}
+	if _, _, content, _ := transcriptContent("Fixed it <This message was edited>"); content != "Fixed it" {
+		t.Errorf("expected edit marker to be removed, got %q", content)
+	}
+}
+
+func TestResolveTranscriptSenders(t *testing.T) {
+	entries := []*transcriptEntry{
+		{sender: "Me Myself"},
+		{sender: "+62 896-8502-8129"},
+		{sender: "Ana"},
+		{sender: "Ben"},
+		{sender: "Cleo"},
+		{sender: "Dan"},
+	}
+	group := types.NewJID("120363024512399999", types.GroupServer)
+
+	resolved, unmapped := resolveTranscriptSenders(entries, group, map[string]string{"Ben": "6281234567890"}, "me myself",
+		map[string]string{"ana": "6280000000001@s.whatsapp.net", "cleo": ""})
+
+	expected := map[string]string{
+		"Me Myself":         "",
+		"+62 896-8502-8129": "6289685028129@s.whatsapp.net",
+		"Ana":               "6280000000001@s.whatsapp.net",
+		"Ben":               "6281234567890@s.whatsapp.net",
+	}
+	for name, jid := range expected {
+		if got, ok := resolved[name]; !ok || got != jid {
+			t.Errorf("expected %s to resolve to %q, got %q", name, jid, got)
+		}
+	}
+	if len(unmapped) != 2 || unmapped[0] != "Cleo" || unmapped[1] != "Dan" {
+		t.Errorf("expected ambiguous and unknown names to stay unmapped, got %v", unmapped)
+	}
+
+	private := types.NewJID("6289685028129", types.DefaultUserServer)
+	resolved, unmapped = resolveTranscriptSenders([]*transcriptEntry{{sender: "You"}, {sender: "Ana"}}, private, nil, "", nil)
+	if resolved["Ana"] != private.String() || len(unmapped) != 0 {
+		t.Errorf("expected the other participant of a private chat to be the chat, got %v %v", resolved, unmapped)
+	}
+}
+
+func TestOpenChatArchive_Zip(t *testing.T) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, content := range map[string]string{
+		"WhatsApp Chat with Ana.txt": "\ufeff05/03/2024, 09:07 - Ana: Hello\n",
+		"IMG-20240305-WA0001.jpg":    "jpeg",
+	} {
+		writer, _ := archive.Create(name)
+		_, _ = writer.Write([]byte(content))
+	}
+	_ = archive.Close()
+
+	transcript, mediaFiles, chatName, err := openChatArchive("upload.zip", buffer.Bytes())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if transcript != "05/03/2024, 09:07 - Ana: Hello\n" {
+		t.Errorf("unexpected transcript %q", transcript)
+	}
+	if chatName != "Ana" {
+		t.Errorf("expected chat name from the transcript file, got %q", chatName)
+	}
+	if _, ok := mediaFiles["IMG-20240305-WA0001.jpg"]; !ok || len(mediaFiles) != 1 {
+		t.Errorf("unexpected media files %v", mediaFiles)
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+const deviceWorkerSyncInterval = 10 * time.Second
+
+// superviseDeviceWorkers keeps exactly one worker goroutine per registered device until ctx is cancelled.
+// Workers are started for devices added at runtime and their context is cancelled when the device is removed.
+func superviseDeviceWorkers(ctx context.Context, dm *whatsapp.DeviceManager, name string, worker func(ctx context.Context, deviceID string)) {
+	if dm == nil {
+		logrus.Warnf("device manager not initialized; %s workers not started", name)
+		return
+	}
+
+	running := make(map[string]context.CancelFunc)
+	syncWorkers := func() {
+		seen := make(map[string]bool)
+		for _, inst := range dm.ListDevices() {
+			deviceID := inst.ID()
+			seen[deviceID] = true
+			if _, ok := running[deviceID]; ok {
+				continue
+			}
+
+			workerCtx, cancel := context.WithCancel(ctx)
+			running[deviceID] = cancel
+			logrus.Debugf("Starting %s worker for device %s", name, deviceID)
+			go worker(workerCtx, deviceID)
+		}
+
+		for deviceID, cancel := range running {
+			if !seen[deviceID] {
+				logrus.Debugf("Stopping %s worker for removed device %s", name, deviceID)
+				cancel()
+				delete(running, deviceID)
+			}
+		}
+	}
+
+	ticker := time.NewTicker(deviceWorkerSyncInterval)
+	defer ticker.Stop()
+	for {
+		syncWorkers()
+
+		select {
+		case <-ctx.Done():
+			for _, cancel := range running {
+				cancel()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deviceRegistryID returns the registry ID of the device in ctx, which is what per-device workers are keyed by.
+func deviceRegistryID(ctx context.Context) (string, error) {
+	inst, ok := whatsapp.DeviceFromContext(ctx)
+	if !ok || inst == nil {
+		return "", pkgError.ValidationError("device_id is required via X-Device-Id header or device_id query")
+	}
+	return inst.ID(), nil
+}
+
+// deviceWakeups lets API calls nudge a sleeping per-device worker.
+type deviceWakeups struct {
+	mu       sync.Mutex
+	channels map[string]chan struct{}
+}
+
+func newDeviceWakeups() *deviceWakeups {
+	return &deviceWakeups{channels: make(map[string]chan struct{})}
+}
+
+func (w *deviceWakeups) channel(deviceID string) chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ch, ok := w.channels[deviceID]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		w.channels[deviceID] = ch
+	}
+	return ch
+}
+
+func (w *deviceWakeups) remove(deviceID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.channels, deviceID)
+}
+
+func (w *deviceWakeups) notify(deviceID string) {
+	w.mu.Lock()
+	ch, ok := w.channels[deviceID]
+	w.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
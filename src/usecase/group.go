@@ -12,6 +12,7 @@ import (
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 )
@@ -23,6 +24,9 @@ func NewGroupService() domainGroup.IGroupUsecase {
 }
 
 func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domainGroup.JoinGroupWithLinkRequest) (groupID string, err error) {
+	ctx, span := tracing.Start(ctx, "group.JoinGroupWithLink")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateJoinGroupWithLink(ctx, request); err != nil {
 		return groupID, err
 	}
@@ -33,7 +37,9 @@ func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domai
 	}
 	utils.MustLogin(client)
 
-	jid, err := client.JoinGroupWithLink(ctx, request.Link)
+	jid, err := tracing.Call(ctx, "whatsmeow.JoinGroupWithLink", func(ctx context.Context) (types.JID, error) {
+		return client.JoinGroupWithLink(ctx, request.Link)
+	})
 	if err != nil {
 		return
 	}
@@ -41,6 +47,9 @@ func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domai
 }
 
 func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.LeaveGroupRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "group.LeaveGroup")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateLeaveGroup(ctx, request); err != nil {
 		return err
 	}
@@ -50,15 +59,20 @@ func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.
 		return pkgError.ErrWaCLI
 	}
 
-	JID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	JID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return client.LeaveGroup(ctx, JID)
+	return tracing.Run(ctx, "whatsmeow.LeaveGroup", func(ctx context.Context) error {
+		return client.LeaveGroup(ctx, JID)
+	})
 }
 
 func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
+	ctx, span := tracing.Start(ctx, "group.CreateGroup")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
 		return groupID, err
 	}
@@ -81,7 +95,9 @@ func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup
 		GroupLinkedParent: types.GroupLinkedParent{},
 	}
 
-	groupInfo, err := client.CreateGroup(ctx, groupConfig)
+	groupInfo, err := tracing.Call(ctx, "whatsmeow.CreateGroup", func(ctx context.Context) (*types.GroupInfo, error) {
+		return client.CreateGroup(ctx, groupConfig)
+	})
 	if err != nil {
 		return
 	}
@@ -90,6 +106,9 @@ func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup
 }
 
 func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request domainGroup.GetGroupInfoFromLinkRequest) (response domainGroup.GetGroupInfoFromLinkResponse, err error) {
+	ctx, span := tracing.Start(ctx, "group.GetGroupInfoFromLink")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateGetGroupInfoFromLink(ctx, request); err != nil {
 		return response, err
 	}
@@ -100,7 +119,9 @@ func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request do
 	}
 	utils.MustLogin(client)
 
-	groupInfo, err := client.GetGroupInfoFromLink(ctx, request.Link)
+	groupInfo, err := tracing.Call(ctx, "whatsmeow.GetGroupInfoFromLink", func(ctx context.Context) (*types.GroupInfo, error) {
+		return client.GetGroupInfoFromLink(ctx, request.Link)
+	})
 	if err != nil {
 		return response, err
 	}
@@ -121,6 +142,9 @@ func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request do
 }
 
 func (service serviceGroup) ManageParticipant(ctx context.Context, request domainGroup.ParticipantRequest) (result []domainGroup.ParticipantStatus, err error) {
+	ctx, span := tracing.Start(ctx, "group.ManageParticipant")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateParticipant(ctx, request); err != nil {
 		return result, err
 	}
@@ -131,7 +155,7 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 	}
 	utils.MustLogin(client)
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return result, err
 	}
@@ -141,7 +165,9 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 		return result, err
 	}
 
-	participants, err := client.UpdateGroupParticipants(ctx, groupJID, participantsJID, request.Action)
+	participants, err := tracing.Call(ctx, "whatsmeow.UpdateGroupParticipants", func(ctx context.Context) ([]types.GroupParticipant, error) {
+		return client.UpdateGroupParticipants(ctx, groupJID, participantsJID, request.Action)
+	})
 	if err != nil {
 		return result, err
 	}
@@ -166,6 +192,9 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 }
 
 func (service serviceGroup) GetGroupParticipants(ctx context.Context, request domainGroup.GetGroupParticipantsRequest) (response domainGroup.GetGroupParticipantsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "group.GetGroupParticipants")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateGetGroupParticipants(ctx, request); err != nil {
 		return response, err
 	}
@@ -175,12 +204,14 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 		return response, pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
-	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	groupInfo, err := tracing.Call(ctx, "whatsmeow.GetGroupInfo", func(ctx context.Context) (*types.GroupInfo, error) {
+		return client.GetGroupInfo(ctx, groupJID)
+	})
 	if err != nil {
 		return response, err
 	}
@@ -199,7 +230,9 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 		// Fetch user info for verified business names (ignore errors)
 		userInfoMap := make(map[types.JID]types.UserInfo)
 		if len(jids) > 0 {
-			userInfoMap, _ = client.GetUserInfo(ctx, jids)
+			userInfoMap, _ = tracing.Call(ctx, "whatsmeow.GetUserInfo", func(ctx context.Context) (map[types.JID]types.UserInfo, error) {
+				return client.GetUserInfo(ctx, jids)
+			})
 		}
 
 		for _, participant := range groupInfo.Participants {
@@ -238,6 +271,9 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 }
 
 func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, request domainGroup.GetGroupRequestParticipantsRequest) (result []domainGroup.GetGroupRequestParticipantsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "group.GetGroupRequestParticipants")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateGetGroupRequestParticipants(ctx, request); err != nil {
 		return result, err
 	}
@@ -247,12 +283,14 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 		return result, pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := client.GetGroupRequestParticipants(ctx, groupJID)
+	participants, err := tracing.Call(ctx, "whatsmeow.GetGroupRequestParticipants", func(ctx context.Context) ([]types.GroupParticipantRequest, error) {
+		return client.GetGroupRequestParticipants(ctx, groupJID)
+	})
 	if err != nil {
 		return result, err
 	}
@@ -266,7 +304,9 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 	// Fetch user info for verified business names (ignore errors)
 	userInfoMap := make(map[types.JID]types.UserInfo)
 	if len(jids) > 0 {
-		userInfoMap, _ = client.GetUserInfo(ctx, jids)
+		userInfoMap, _ = tracing.Call(ctx, "whatsmeow.GetUserInfo", func(ctx context.Context) (map[types.JID]types.UserInfo, error) {
+			return client.GetUserInfo(ctx, jids)
+		})
 	}
 
 	for _, participant := range participants {
@@ -292,6 +332,9 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 }
 
 func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context, request domainGroup.GroupRequestParticipantsRequest) (result []domainGroup.ParticipantStatus, err error) {
+	ctx, span := tracing.Start(ctx, "group.ManageGroupRequestParticipants")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateManageGroupRequestParticipants(ctx, request); err != nil {
 		return result, err
 	}
@@ -301,7 +344,7 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 		return result, pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return result, err
 	}
@@ -311,7 +354,9 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 		return result, err
 	}
 
-	participants, err := client.UpdateGroupRequestParticipants(ctx, groupJID, participantsJID, request.Action)
+	participants, err := tracing.Call(ctx, "whatsmeow.UpdateGroupRequestParticipants", func(ctx context.Context) ([]types.GroupParticipant, error) {
+		return client.UpdateGroupRequestParticipants(ctx, groupJID, participantsJID, request.Action)
+	})
 	if err != nil {
 		return result, err
 	}
@@ -345,7 +390,7 @@ func (service serviceGroup) participantToJID(ctx context.Context, participants [
 	for _, participant := range participants {
 		formattedParticipant := participant + config.WhatsappTypeUser
 
-		if !utils.IsOnWhatsapp(client, formattedParticipant) {
+		if !utils.IsOnWhatsapp(ctx, client, formattedParticipant) {
 			return nil, pkgError.ErrUserNotRegistered
 		}
 
@@ -357,6 +402,9 @@ func (service serviceGroup) participantToJID(ctx context.Context, participants [
 }
 
 func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGroup.SetGroupPhotoRequest) (pictureID string, err error) {
+	ctx, span := tracing.Start(ctx, "group.SetGroupPhoto")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateSetGroupPhoto(ctx, request); err != nil {
 		return pictureID, err
 	}
@@ -366,7 +414,7 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		return pictureID, pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return pictureID, err
 	}
@@ -389,7 +437,9 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		photoBytes = processedImageBuffer.Bytes()
 	}
 
-	pictureID, err = client.SetGroupPhoto(ctx, groupJID, photoBytes)
+	pictureID, err = tracing.Call(ctx, "whatsmeow.SetGroupPhoto", func(ctx context.Context) (string, error) {
+		return client.SetGroupPhoto(ctx, groupJID, photoBytes)
+	})
 	if err != nil {
 		logrus.Printf("Failed to set group photo: %v", err)
 		return pictureID, err
@@ -399,6 +449,9 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 }
 
 func (service serviceGroup) SetGroupName(ctx context.Context, request domainGroup.SetGroupNameRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "group.SetGroupName")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateSetGroupName(ctx, request); err != nil {
 		return err
 	}
@@ -408,15 +461,20 @@ func (service serviceGroup) SetGroupName(ctx context.Context, request domainGrou
 		return pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return client.SetGroupName(ctx, groupJID, request.Name)
+	return tracing.Run(ctx, "whatsmeow.SetGroupName", func(ctx context.Context) error {
+		return client.SetGroupName(ctx, groupJID, request.Name)
+	})
 }
 
 func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGroup.SetGroupLockedRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "group.SetGroupLocked")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateSetGroupLocked(ctx, request); err != nil {
 		return err
 	}
@@ -426,15 +484,20 @@ func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGr
 		return pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return client.SetGroupLocked(ctx, groupJID, request.Locked)
+	return tracing.Run(ctx, "whatsmeow.SetGroupLocked", func(ctx context.Context) error {
+		return client.SetGroupLocked(ctx, groupJID, request.Locked)
+	})
 }
 
 func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domainGroup.SetGroupAnnounceRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "group.SetGroupAnnounce")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateSetGroupAnnounce(ctx, request); err != nil {
 		return err
 	}
@@ -444,15 +507,20 @@ func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domain
 		return pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return client.SetGroupAnnounce(ctx, groupJID, request.Announce)
+	return tracing.Run(ctx, "whatsmeow.SetGroupAnnounce", func(ctx context.Context) error {
+		return client.SetGroupAnnounce(ctx, groupJID, request.Announce)
+	})
 }
 
 func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopicRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "group.SetGroupTopic")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateSetGroupTopic(ctx, request); err != nil {
 		return err
 	}
@@ -462,17 +530,22 @@ func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGro
 		return pkgError.ErrWaCLI
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
 	// SetGroupTopic with auto-generated IDs (previousID and newID will be handled automatically)
-	return client.SetGroupTopic(ctx, groupJID, "", "", request.Topic)
+	return tracing.Run(ctx, "whatsmeow.SetGroupTopic", func(ctx context.Context) error {
+		return client.SetGroupTopic(ctx, groupJID, "", "", request.Topic)
+	})
 }
 
 // GroupInfo retrieves detailed information about a WhatsApp group
 func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.GroupInfoRequest) (response domainGroup.GroupInfoResponse, err error) {
+	ctx, span := tracing.Start(ctx, "group.GroupInfo")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate the incoming request
 	if err = validations.ValidateGroupInfo(ctx, request); err != nil {
 		return response, err
@@ -487,13 +560,15 @@ func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.G
 	utils.MustLogin(client)
 
 	// Validate and parse the provided group JID / ID
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
 	// Fetch group information from WhatsApp
-	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	groupInfo, err := tracing.Call(ctx, "whatsmeow.GetGroupInfo", func(ctx context.Context) (*types.GroupInfo, error) {
+		return client.GetGroupInfo(ctx, groupJID)
+	})
 	if err != nil {
 		return response, err
 	}
@@ -507,6 +582,9 @@ func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.G
 }
 
 func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request domainGroup.GetGroupInviteLinkRequest) (response domainGroup.GetGroupInviteLinkResponse, err error) {
+	ctx, span := tracing.Start(ctx, "group.GetGroupInviteLink")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateGetGroupInviteLink(ctx, request); err != nil {
 		return response, err
 	}
@@ -517,12 +595,14 @@ func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request doma
 	}
 	utils.MustLogin(client)
 
-	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(ctx, client, request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
-	inviteLink, err := client.GetGroupInviteLink(ctx, groupJID, request.Reset)
+	inviteLink, err := tracing.Call(ctx, "whatsmeow.GetGroupInviteLink", func(ctx context.Context) (string, error) {
+		return client.GetGroupInviteLink(ctx, groupJID, request.Reset)
+	})
 	if err != nil {
 		return response, err
 	}
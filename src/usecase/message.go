@@ -3,7 +3,6 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
@@ -12,6 +11,7 @@ import (
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -35,6 +35,9 @@ func NewMessageService(chatStorageRepo domainChatStorage.IChatStorageRepository)
 }
 
 func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMessage.MarkAsReadRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.MarkAsRead")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateMarkAsRead(ctx, request); err != nil {
 		return response, err
 	}
@@ -44,13 +47,16 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	ids := []types.MessageID{request.MessageID}
-	if err = client.MarkRead(ctx, ids, time.Now(), dataWaRecipient, *client.Store.ID); err != nil {
+	err = tracing.Run(ctx, "whatsmeow.MarkRead", func(ctx context.Context) error {
+		return client.MarkRead(ctx, ids, time.Now(), dataWaRecipient, *client.Store.ID)
+	})
+	if err != nil {
 		return response, err
 	}
 
@@ -67,6 +73,9 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 }
 
 func (service serviceMessage) ReactMessage(ctx context.Context, request domainMessage.ReactionRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.ReactMessage")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateReactMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -76,7 +85,7 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -92,10 +101,26 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 			SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
 		},
 	}
-	ts, err := client.SendMessage(ctx, dataWaRecipient, msg)
+	ts, err := tracing.Call(ctx, "whatsmeow.SendMessage", func(ctx context.Context) (whatsmeow.SendResponse, error) {
+		return client.SendMessage(ctx, dataWaRecipient, msg)
+	})
 	if err != nil {
 		return response, err
 	}
+	countSentMessage(ctx, msg)
+
+	deviceID, chatJID := storageKeys(ctx, client, dataWaRecipient)
+	err = service.chatStorageRepo.StoreReaction(&domainChatStorage.MessageReaction{
+		DeviceID:   deviceID,
+		MessageID:  request.MessageID,
+		ChatJID:    chatJID,
+		ReactorJID: client.Store.ID.ToNonAD().String(),
+		Emoji:      request.Emoji,
+		ReactedAt:  ts.Timestamp,
+	})
+	if err != nil {
+		logrus.Warnf("Failed to store reaction on message %s: %v", request.MessageID, err)
+	}
 
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
@@ -103,6 +128,9 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 }
 
 func (service serviceMessage) RevokeMessage(ctx context.Context, request domainMessage.RevokeRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.RevokeMessage")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateRevokeMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -112,15 +140,25 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
-	ts, err := client.SendMessage(ctx, dataWaRecipient, client.BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
+	revoke := client.BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID)
+	ts, err := tracing.Call(ctx, "whatsmeow.SendMessage", func(ctx context.Context) (whatsmeow.SendResponse, error) {
+		return client.SendMessage(ctx, dataWaRecipient, revoke)
+	})
 	if err != nil {
 		return response, err
 	}
+	countSentMessage(ctx, revoke)
+
+	deviceID, chatJID := storageKeys(ctx, client, dataWaRecipient)
+	err = service.chatStorageRepo.MarkMessageRevoked(deviceID, chatJID, request.MessageID, client.Store.ID.ToNonAD().String(), ts.Timestamp)
+	if err != nil {
+		logrus.Warnf("Failed to mark message %s revoked: %v", request.MessageID, err)
+	}
 
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Revoke success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
@@ -128,6 +166,9 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 }
 
 func (service serviceMessage) DeleteMessage(ctx context.Context, request domainMessage.DeleteRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "message.DeleteMessage")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateDeleteMessage(ctx, request); err != nil {
 		return err
 	}
@@ -137,7 +178,7 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 		return pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return err
 	}
@@ -161,13 +202,19 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 		}},
 	}
 
-	if err = client.SendAppState(ctx, patchInfo); err != nil {
+	err = tracing.Run(ctx, "whatsmeow.SendAppState", func(ctx context.Context) error {
+		return client.SendAppState(ctx, patchInfo)
+	})
+	if err != nil {
 		return err
 	}
 	return nil
 }
 
 func (service serviceMessage) UpdateMessage(ctx context.Context, request domainMessage.UpdateMessageRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.UpdateMessage")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateUpdateMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -177,24 +224,53 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	msg := &waE2E.Message{Conversation: proto.String(request.Message)}
-	ts, err := client.SendMessage(ctx, dataWaRecipient, client.BuildEdit(dataWaRecipient, request.MessageID, msg))
+	edit := client.BuildEdit(dataWaRecipient, request.MessageID, msg)
+	ts, err := tracing.Call(ctx, "whatsmeow.SendMessage", func(ctx context.Context) (whatsmeow.SendResponse, error) {
+		return client.SendMessage(ctx, dataWaRecipient, edit)
+	})
 	if err != nil {
 		return response, err
 	}
+	countSentMessage(ctx, edit)
+
+	deviceID, chatJID := storageKeys(ctx, client, dataWaRecipient)
+	err = service.chatStorageRepo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+		DeviceID:  deviceID,
+		MessageID: request.MessageID,
+		ChatJID:   chatJID,
+		Content:   request.Message,
+		EditedAt:  ts.Timestamp,
+	})
+	if err != nil {
+		logrus.Warnf("Failed to store edit of message %s: %v", request.MessageID, err)
+	}
 
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Update message success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
 }
 
+// storageKeys returns the device and chat JIDs a message to recipient is stored under, since whatsmeow
+// does not echo our own reactions, edits and revocations back as events
+func storageKeys(ctx context.Context, client *whatsmeow.Client, recipient types.JID) (deviceID, chatJID string) {
+	deviceID = deviceIDFromContext(ctx)
+	if deviceID == "" {
+		deviceID = client.Store.ID.ToNonAD().String()
+	}
+	return deviceID, whatsapp.NormalizeJIDFromLID(ctx, recipient, client).String()
+}
+
 // StarMessage implements message.IMessageService.
 func (service serviceMessage) StarMessage(ctx context.Context, request domainMessage.StarRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "message.StarMessage")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateStarMessage(ctx, request); err != nil {
 		return err
 	}
@@ -204,7 +280,7 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 		return pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return err
 	}
@@ -216,7 +292,10 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 
 	patchInfo := appstate.BuildStar(dataWaRecipient.ToNonAD(), *client.Store.ID, request.MessageID, isFromMe, request.IsStarred)
 
-	if err = client.SendAppState(ctx, patchInfo); err != nil {
+	err = tracing.Run(ctx, "whatsmeow.SendAppState", func(ctx context.Context) error {
+		return client.SendAppState(ctx, patchInfo)
+	})
+	if err != nil {
 		return err
 	}
 	return nil
@@ -224,6 +303,9 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 
 // DownloadMedia implements message.IMessageService.
 func (service serviceMessage) DownloadMedia(ctx context.Context, request domainMessage.DownloadMediaRequest) (response domainMessage.DownloadMediaResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.DownloadMedia")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateDownloadMedia(ctx, request); err != nil {
 		return response, err
 	}
@@ -233,7 +315,7 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -258,15 +340,38 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, fmt.Errorf("message %s does not belong to chat %s", request.MessageID, dataWaRecipient.String())
 	}
 
-	// Create directory structure for organized storage
-	chatDir := filepath.Join(config.PathMedia, utils.ExtractPhoneNumber(message.ChatJID))
-	dateDir := filepath.Join(chatDir, message.Timestamp.Format("2006-01-02"))
-
-	err = os.MkdirAll(dateDir, 0755)
+	extractedMedia, err := downloadStoredMedia(ctx, client, message)
 	if err != nil {
-		return response, fmt.Errorf("failed to create directory: %v", err)
+		return response, err
 	}
 
+	// Build response
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Media downloaded successfully to %s", extractedMedia.MediaPath)
+	response.MediaType = message.MediaType
+	response.Filename = filepath.Base(extractedMedia.MediaPath)
+	response.FilePath = extractedMedia.MediaPath
+	response.FileURL = extractedMedia.MediaURL
+	response.FileSize = extractedMedia.FileSize
+
+	logrus.Info(map[string]any{
+		"message_id": request.MessageID,
+		"phone":      request.Phone,
+		"chat":       dataWaRecipient.String(),
+		"media_type": response.MediaType,
+		"file_path":  response.FilePath,
+		"file_size":  response.FileSize,
+	})
+
+	return response, nil
+}
+
+// downloadStoredMedia downloads the media of a stored message into the media storage, organized by chat and day
+func downloadStoredMedia(ctx context.Context, client *whatsmeow.Client, message *domainChatStorage.Message) (*utils.ExtractedMedia, error) {
+	// Keys are organized by chat and day, the storage creates folders as needed
+	chatDir := filepath.Join(config.PathMedia, utils.ExtractPhoneNumber(message.ChatJID))
+	dateDir := filepath.Join(chatDir, message.Timestamp.Format("2006-01-02"))
+
 	// Create a downloadable message interface based on media type
 	var downloadableMsg interface{}
 
@@ -313,39 +418,108 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 			FileLength:    proto.Uint64(message.FileLength),
 		}
 	default:
-		return response, fmt.Errorf("unsupported media type: %s", message.MediaType)
+		return nil, fmt.Errorf("unsupported media type: %s", message.MediaType)
 	}
 
 	// Download the media using existing utils.ExtractMedia function
 	extractedMedia, err := utils.ExtractMedia(ctx, client, dateDir, downloadableMsg.(whatsmeow.DownloadableMessage))
 	if err != nil {
-		return response, fmt.Errorf("failed to download media: %v", err)
+		return nil, fmt.Errorf("failed to download media: %v", err)
 	}
+	return &extractedMedia, nil
+}
+
+// GetMessageStatus returns the delivery state of an outgoing message for each recipient
+func (service serviceMessage) GetMessageStatus(ctx context.Context, request domainMessage.MessageStatusRequest) (response domainMessage.MessageStatusResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.GetMessageStatus")
+	defer func() { tracing.End(span, err) }()
 
-	// Get file size
-	fileInfo, err := os.Stat(extractedMedia.MediaPath)
+	if err = validations.ValidateMessageStatus(ctx, request); err != nil {
+		return response, err
+	}
+
+	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID)
 	if err != nil {
-		logrus.Warnf("Could not get file size for %s: %v", extractedMedia.MediaPath, err)
+		return response, fmt.Errorf("failed to get message: %w", err)
+	}
+	if message == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("message with ID %s not found", request.MessageID))
+	}
+	if !message.IsFromMe {
+		return response, pkgError.ValidationError(fmt.Sprintf("message %s was not sent by this device", request.MessageID))
 	}
 
-	// Build response
-	response.MessageID = request.MessageID
-	response.Status = fmt.Sprintf("Media downloaded successfully to %s", extractedMedia.MediaPath)
-	response.MediaType = message.MediaType
-	response.Filename = filepath.Base(extractedMedia.MediaPath)
-	response.FilePath = extractedMedia.MediaPath
-	if fileInfo != nil {
-		response.FileSize = fileInfo.Size()
+	receiptsByMessage, err := service.chatStorageRepo.GetMessageReceipts(message.DeviceID, []string{message.ID})
+	if err != nil {
+		return response, fmt.Errorf("failed to get message receipts: %w", err)
+	}
+	receipts := receiptsByMessage[message.ID]
+
+	response.MessageID = message.ID
+	response.ChatJID = message.ChatJID
+	response.Status = domainChatStorage.ReceiptStatus(receipts)
+	response.SentAt = message.Timestamp.Format(time.RFC3339)
+	response.Recipients = make([]domainMessage.RecipientStatus, 0, len(receipts))
+	for _, receipt := range receipts {
+		response.Recipients = append(response.Recipients, domainMessage.RecipientStatus{
+			RecipientJID: receipt.RecipientJID,
+			Status:       receipt.Status(),
+			DeliveredAt:  formatOptionalTime(receipt.DeliveredAt),
+			ReadAt:       formatOptionalTime(receipt.ReadAt),
+			PlayedAt:     formatOptionalTime(receipt.PlayedAt),
+		})
 	}
+	return response, nil
+}
 
-	logrus.Info(map[string]any{
-		"message_id": request.MessageID,
-		"phone":      request.Phone,
-		"chat":       dataWaRecipient.String(),
-		"media_type": response.MediaType,
-		"file_path":  response.FilePath,
-		"file_size":  response.FileSize,
-	})
+// GetPollResults counts the latest vote of every voter on a poll per option
+func (service serviceMessage) GetPollResults(ctx context.Context, request domainMessage.PollResultsRequest) (response domainMessage.PollResultsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.GetPollResults")
+	defer func() { tracing.End(span, err) }()
 
+	if err = validations.ValidatePollResults(ctx, request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	poll, err := service.chatStorageRepo.GetPoll(deviceID, request.MessageID)
+	if err != nil {
+		return response, fmt.Errorf("failed to get poll: %w", err)
+	}
+	if poll == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("poll with message ID %s not found", request.MessageID))
+	}
+
+	votes, err := service.chatStorageRepo.GetPollVotes(deviceID, poll.MessageID)
+	if err != nil {
+		return response, fmt.Errorf("failed to get poll votes: %w", err)
+	}
+
+	response.MessageID = poll.MessageID
+	response.ChatJID = poll.ChatJID
+	response.Question = poll.Question
+	response.SelectableCount = poll.SelectableCount
+	response.Options = make([]domainMessage.PollOptionResult, len(poll.Options))
+	optionIndex := make(map[string]int, len(poll.Options))
+	for i, option := range poll.Options {
+		response.Options[i] = domainMessage.PollOptionResult{Option: option, Voters: []string{}}
+		optionIndex[option] = i
+	}
+
+	for _, vote := range votes {
+		counted := false
+		for _, option := range vote.SelectedOptions {
+			i, ok := optionIndex[option]
+			if !ok {
+				continue
+			}
+			response.Options[i].Votes++
+			response.Options[i].Voters = append(response.Options[i].Voters, vote.VoterJID)
+			counted = true
+		}
+		if counted {
+			response.TotalVoters++
+		}
+	}
 	return response, nil
 }
@@ -6,6 +6,7 @@ import (
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 )
@@ -17,6 +18,9 @@ func NewNewsletterService() domainNewsletter.INewsletterUsecase {
 }
 
 func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNewsletter.UnfollowRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "newsletter.Unfollow")
+	defer func() { tracing.End(span, err) }()
+
 	if err = validations.ValidateUnfollowNewsletter(ctx, request); err != nil {
 		return err
 	}
@@ -26,10 +30,12 @@ func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNew
 		return pkgError.ErrWaCLI
 	}
 
-	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	JID, err := utils.ValidateJidWithLogin(ctx, client, request.NewsletterID)
 	if err != nil {
 		return err
 	}
 
-	return client.UnfollowNewsletter(ctx, JID)
+	return tracing.Run(ctx, "whatsmeow.UnfollowNewsletter", func(ctx context.Context) error {
+		return client.UnfollowNewsletter(ctx, JID)
+	})
 }
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainQueue "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/queue"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	queueIdleWait    = 30 * time.Second
+	queueSendTimeout = 2 * time.Minute
+)
+
+type serviceQueue struct {
+	queueRepo     domainQueue.IQueueRepository
+	sendService   domainSend.ISendUsecase
+	templateRepo  domainTemplate.ITemplateRepository
+	deviceManager *whatsapp.DeviceManager
+	wake          *deviceWakeups
+}
+
+func NewQueueService(queueRepo domainQueue.IQueueRepository, sendService domainSend.ISendUsecase, templateRepo domainTemplate.ITemplateRepository, deviceManager *whatsapp.DeviceManager) domainQueue.IQueueUsecase {
+	return &serviceQueue{
+		queueRepo:     queueRepo,
+		sendService:   sendService,
+		templateRepo:  templateRepo,
+		deviceManager: deviceManager,
+		wake:          newDeviceWakeups(),
+	}
+}
+
+func (service serviceQueue) Enqueue(ctx context.Context, request domainQueue.EnqueueRequest) (domainQueue.Job, error) {
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return domainQueue.Job{}, err
+	}
+
+	prepared, err := prepareSendRequest(request.Type, request.Payload)
+	if err != nil {
+		return domainQueue.Job{}, err
+	}
+	if err := prepared.validate(ctx, service.templateRepo); err != nil {
+		return domainQueue.Job{}, err
+	}
+	payload, err := prepared.payload()
+	if err != nil {
+		return domainQueue.Job{}, fmt.Errorf("failed to encode queued message: %w", err)
+	}
+
+	job := &domainQueue.Job{
+		DeviceID: deviceID,
+		Type:     request.Type,
+		Phone:    prepared.phone,
+		Payload:  payload,
+	}
+	if err := service.queueRepo.EnqueueJob(job); err != nil {
+		return domainQueue.Job{}, fmt.Errorf("failed to queue message: %w", err)
+	}
+
+	service.wake.notify(deviceID)
+	return *job, nil
+}
+
+func (service serviceQueue) Get(ctx context.Context, jobID string) (domainQueue.Job, error) {
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return domainQueue.Job{}, err
+	}
+
+	job, err := service.queueRepo.GetJob(deviceID, jobID)
+	if err != nil {
+		return domainQueue.Job{}, fmt.Errorf("failed to get queued message: %w", err)
+	}
+	if job == nil {
+		return domainQueue.Job{}, pkgError.NotFoundError(fmt.Sprintf("queued message %s not found", jobID))
+	}
+	return *job, nil
+}
+
+func (service serviceQueue) Run(ctx context.Context) {
+	if failed, err := service.queueRepo.FailInterruptedJobs(); err != nil {
+		logrus.Errorf("Failed to recover interrupted queued messages: %v", err)
+	} else if failed > 0 {
+		logrus.Warnf("Marked %d queued message(s) interrupted by the last shutdown as failed", failed)
+	}
+
+	// A reconnect is what a waiting queue is held for, so flush right away instead of on the next idle tick
+	whatsapp.OnDeviceConnected(service.wake.notify)
+
+	superviseDeviceWorkers(ctx, service.deviceManager, "send queue", service.runDeviceWorker)
+}
+
+// runDeviceWorker flushes the queue of one device whenever a job is added or the device reconnects.
+func (service serviceQueue) runDeviceWorker(ctx context.Context, deviceID string) {
+	wake := service.wake.channel(deviceID)
+	defer service.wake.remove(deviceID)
+
+	for {
+		service.flush(ctx, deviceID)
+
+		timer := time.NewTimer(queueIdleWait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-wake:
+			timer.Stop()
+		}
+	}
+}
+
+// flush sends queued jobs oldest first and stops as soon as the device is offline, so order is kept.
+func (service serviceQueue) flush(ctx context.Context, deviceID string) {
+	for ctx.Err() == nil {
+		inst, ok := service.deviceManager.GetDevice(deviceID)
+		if !ok || inst == nil || !inst.IsConnected() || !inst.IsLoggedIn() {
+			return
+		}
+
+		job, err := service.queueRepo.NextQueuedJob(deviceID)
+		if err != nil {
+			logrus.Errorf("Failed to fetch queued messages for device %s: %v", deviceID, err)
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		claimed, err := service.queueRepo.ClaimJob(job.ID)
+		if err != nil {
+			logrus.Errorf("Failed to claim queued message %s: %v", job.ID, err)
+			return
+		}
+		if !claimed {
+			continue
+		}
+		if !service.deliver(whatsapp.ContextWithDevice(ctx, inst), inst, job) {
+			return
+		}
+	}
+}
+
+// deliver sends one job. It returns false when the device went offline and the job was put back in the queue.
+func (service serviceQueue) deliver(ctx context.Context, inst *whatsapp.DeviceInstance, job *domainQueue.Job) bool {
+	// Let an in-flight send finish on shutdown instead of leaving the row in sending.
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), queueSendTimeout)
+	defer cancel()
+
+	prepared, err := prepareSendRequest(job.Type, job.Payload)
+	var response domainSend.GenericResponse
+	if err == nil {
+		response, err = prepared.send(sendCtx, service.sendService)
+	}
+	if err != nil {
+		if !inst.IsConnected() || !inst.IsLoggedIn() {
+			if requeueErr := service.queueRepo.RequeueJob(job.ID); requeueErr != nil {
+				logrus.Errorf("Failed to requeue message %s: %v", job.ID, requeueErr)
+			}
+			logrus.Infof("Device %s went offline while sending queued message %s; keeping it queued", inst.ID(), job.ID)
+			return false
+		}
+
+		if markErr := service.queueRepo.MarkJobFailed(job.ID, err.Error()); markErr != nil {
+			logrus.Errorf("Failed to record queued message %s failure: %v", job.ID, markErr)
+		}
+		logrus.Warnf("Queued message %s to %s failed: %v", job.ID, job.Phone, err)
+		return true
+	}
+
+	if err := service.queueRepo.MarkJobSent(job.ID, response.MessageID, time.Now()); err != nil {
+		logrus.Errorf("Failed to mark queued message %s as sent: %v", job.ID, err)
+	}
+	logrus.Infof("Queued message %s sent to %s as %s", job.ID, job.Phone, response.MessageID)
+	return true
+}
@@ -0,0 +1,363 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainRetention "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/retention"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	retentionInterval  = time.Hour
+	retentionBatchSize = 1000
+)
+
+type serviceRetention struct {
+	retentionRepo   domainRetention.IRetentionRepository
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	deviceManager   *whatsapp.DeviceManager
+}
+
+func NewRetentionService(retentionRepo domainRetention.IRetentionRepository, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceManager *whatsapp.DeviceManager) domainRetention.IRetentionUsecase {
+	return &serviceRetention{
+		retentionRepo:   retentionRepo,
+		chatStorageRepo: chatStorageRepo,
+		deviceManager:   deviceManager,
+	}
+}
+
+func (service serviceRetention) ListPolicies(_ context.Context) ([]domainRetention.Policy, error) {
+	policies, err := service.retentionRepo.ListPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention policies: %w", err)
+	}
+
+	response := make([]domainRetention.Policy, 0, len(policies))
+	for _, policy := range policies {
+		response = append(response, *policy)
+	}
+	return response, nil
+}
+
+func (service serviceRetention) CreatePolicy(ctx context.Context, request domainRetention.CreatePolicyRequest) (domainRetention.Policy, error) {
+	if err := validations.ValidateCreateRetentionPolicy(ctx, &request); err != nil {
+		return domainRetention.Policy{}, err
+	}
+
+	policy := &domainRetention.Policy{
+		ChatJID:     request.ChatJID,
+		MessageDays: request.MessageDays,
+		MediaDays:   request.MediaDays,
+	}
+	if request.DeviceID != "" {
+		deviceID, err := service.storageDeviceID(request.DeviceID)
+		if err != nil {
+			return domainRetention.Policy{}, err
+		}
+		policy.DeviceID = deviceID
+	}
+
+	existing, err := service.retentionRepo.GetPolicyByTarget(policy.DeviceID, policy.ChatJID)
+	if err != nil {
+		return domainRetention.Policy{}, fmt.Errorf("failed to check retention policy: %w", err)
+	}
+	if existing != nil {
+		return domainRetention.Policy{}, pkgError.ValidationError(fmt.Sprintf("scope: retention policy %s already covers this %s.", existing.ID, request.Scope))
+	}
+
+	if err := service.retentionRepo.SavePolicy(policy); err != nil {
+		return domainRetention.Policy{}, fmt.Errorf("failed to save retention policy: %w", err)
+	}
+	return *policy, nil
+}
+
+func (service serviceRetention) UpdatePolicy(ctx context.Context, request domainRetention.UpdatePolicyRequest) (domainRetention.Policy, error) {
+	if err := validations.ValidateUpdateRetentionPolicy(ctx, &request); err != nil {
+		return domainRetention.Policy{}, err
+	}
+
+	policy, err := service.findPolicy(request.PolicyID)
+	if err != nil {
+		return domainRetention.Policy{}, err
+	}
+
+	policy.MessageDays = request.MessageDays
+	policy.MediaDays = request.MediaDays
+	if err := service.retentionRepo.SavePolicy(policy); err != nil {
+		return domainRetention.Policy{}, fmt.Errorf("failed to save retention policy: %w", err)
+	}
+	return *policy, nil
+}
+
+func (service serviceRetention) DeletePolicy(_ context.Context, policyID string) error {
+	if _, err := service.findPolicy(policyID); err != nil {
+		return err
+	}
+	if err := service.retentionRepo.DeletePolicy(policyID); err != nil {
+		return fmt.Errorf("failed to delete retention policy: %w", err)
+	}
+	return nil
+}
+
+func (service serviceRetention) Report(_ context.Context) (domainRetention.Report, error) {
+	return service.enforce(true)
+}
+
+func (service serviceRetention) Run(ctx context.Context) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		report, err := service.enforce(false)
+		if err != nil {
+			logrus.Errorf("Failed to enforce retention policies: %v", err)
+		} else if report.MessagesDeleted > 0 || report.MediaFilesDeleted > 0 {
+			logrus.Infof("Retention deleted %d message(s) and %d media file(s), freeing %d bytes",
+				report.MessagesDeleted, report.MediaFilesDeleted, report.MediaBytesFreed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforce deletes the messages and media files older than their policies allow, or only counts them on a dry run
+func (service serviceRetention) enforce(dryRun bool) (domainRetention.Report, error) {
+	report := domainRetention.Report{
+		DryRun:    dryRun,
+		StartedAt: time.Now().UTC(),
+		Chats:     []domainRetention.ChatReport{},
+		Media:     []domainRetention.MediaReport{},
+	}
+
+	policies, err := service.retentionRepo.ListPolicies()
+	if err != nil {
+		return report, fmt.Errorf("failed to list retention policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return report, nil
+	}
+	rules := newRetentionRules(policies)
+
+	chats, err := service.chatStorageRepo.GetChats(&domainChatStorage.ChatFilter{})
+	if err != nil {
+		return report, fmt.Errorf("failed to list chats: %w", err)
+	}
+
+	// Media folders are per chat and shared by devices, so a folder keeps the longest retention of its chats
+	folderDays := make(map[string]int)
+	for _, chat := range chats {
+		messageDays, mediaDays := rules.resolve(chat.DeviceID, chat.JID)
+
+		folder := utils.ExtractPhoneNumber(chat.JID)
+		if current, seen := folderDays[folder]; !seen || (current > 0 && (mediaDays == 0 || mediaDays > current)) {
+			folderDays[folder] = mediaDays
+		}
+
+		if messageDays == 0 {
+			continue
+		}
+		cutoff := report.StartedAt.AddDate(0, 0, -messageDays)
+		deleted, err := service.expireMessages(chat, cutoff, dryRun)
+		if err != nil {
+			return report, err
+		}
+		if deleted > 0 {
+			report.Chats = append(report.Chats, domainRetention.ChatReport{
+				DeviceID:        chat.DeviceID,
+				ChatJID:         chat.JID,
+				Cutoff:          cutoff,
+				MessagesDeleted: deleted,
+			})
+			report.MessagesDeleted += deleted
+		}
+	}
+
+	storage := mediastorage.Active()
+	objects, err := storage.List(context.Background(), config.PathMedia)
+	if err != nil {
+		return report, fmt.Errorf("failed to list media: %w", err)
+	}
+
+	// Files outside chat folders, like auto-downloaded media, follow the global policy
+	_, globalMediaDays := rules.resolve("", "")
+	folders := make(map[string][]mediastorage.Object)
+	for _, object := range objects {
+		folder, _, nested := strings.Cut(strings.TrimPrefix(object.Key, config.PathMedia+"/"), "/")
+		if !nested {
+			folder = ""
+		}
+		folders[folder] = append(folders[folder], object)
+	}
+
+	for folder, objects := range folders {
+		days, known := folderDays[folder]
+		if folder == "" || !known {
+			days = globalMediaDays
+		}
+		if days == 0 {
+			continue
+		}
+		media := domainRetention.MediaReport{
+			Folder: path.Join(config.PathMedia, folder),
+			Cutoff: report.StartedAt.AddDate(0, 0, -days),
+		}
+		expireMediaFiles(storage, &media, objects, dryRun)
+		if folder != "" && !dryRun {
+			removeEmptyFolders(filepath.FromSlash(media.Folder))
+		}
+		if media.FilesDeleted > 0 {
+			report.Media = append(report.Media, media)
+			report.MediaFilesDeleted += media.FilesDeleted
+			report.MediaBytesFreed += media.BytesFreed
+		}
+	}
+
+	return report, nil
+}
+
+// expireMessages deletes the messages of a chat sent before cutoff and returns how many there were
+func (service serviceRetention) expireMessages(chat *domainChatStorage.Chat, cutoff time.Time, dryRun bool) (int, error) {
+	filter := &domainChatStorage.MessageFilter{
+		DeviceID: chat.DeviceID,
+		ChatJID:  chat.JID,
+		EndTime:  &cutoff,
+		Limit:    retentionBatchSize,
+	}
+
+	deleted := 0
+	for {
+		messages, err := service.chatStorageRepo.GetMessages(filter)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to get expired messages of chat %s: %w", chat.JID, err)
+		}
+
+		for _, message := range messages {
+			if !dryRun {
+				if err := service.chatStorageRepo.DeleteMessageByDevice(message.DeviceID, message.ID, message.ChatJID); err != nil {
+					return deleted, fmt.Errorf("failed to delete message %s: %w", message.ID, err)
+				}
+			}
+			deleted++
+		}
+
+		if len(messages) < retentionBatchSize {
+			return deleted, nil
+		}
+		// Deleted messages drop out of the next query, a dry run has to page instead
+		if dryRun {
+			filter.Offset += len(messages)
+		}
+	}
+}
+
+// expireMediaFiles deletes the objects of the report's folder last modified before its cutoff from the storage
+func expireMediaFiles(storage mediastorage.Storage, media *domainRetention.MediaReport, objects []mediastorage.Object, dryRun bool) {
+	for _, object := range objects {
+		if !object.ModTime.Before(media.Cutoff) {
+			continue
+		}
+		if !dryRun {
+			if err := storage.Delete(context.Background(), object.Key); err != nil {
+				logrus.Warnf("Retention failed to delete media file %s: %v", object.Key, err)
+				continue
+			}
+		}
+		media.FilesDeleted++
+		media.BytesFreed += object.Size
+	}
+}
+
+// removeEmptyFolders removes the empty subfolders a local storage keeps below root, deepest first
+func removeEmptyFolders(root string) {
+	var folders []string
+	_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.IsDir() && path != root {
+			folders = append(folders, path)
+		}
+		return nil
+	})
+	for i := len(folders) - 1; i >= 0; i-- {
+		_ = os.Remove(folders[i])
+	}
+}
+
+func (service serviceRetention) findPolicy(policyID string) (*domainRetention.Policy, error) {
+	policy, err := service.retentionRepo.GetPolicy(policyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retention policy: %w", err)
+	}
+	if policy == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("retention policy %s not found", policyID))
+	}
+	return policy, nil
+}
+
+// storageDeviceID returns the ID chat storage keys the device's data by, its JID once logged in
+func (service serviceRetention) storageDeviceID(deviceID string) (string, error) {
+	if service.deviceManager == nil {
+		return "", fmt.Errorf("device manager not initialized")
+	}
+	inst, ok := service.deviceManager.GetDevice(deviceID)
+	if !ok || inst == nil {
+		return "", pkgError.NotFoundError(fmt.Sprintf("device %s not found", deviceID))
+	}
+	if jid := inst.JID(); jid != "" {
+		return jid, nil
+	}
+	return inst.ID(), nil
+}
+
+// retentionRules resolves the effective settings of a chat from the policies that cover it
+type retentionRules map[[2]string]*domainRetention.Policy
+
+func newRetentionRules(policies []*domainRetention.Policy) retentionRules {
+	rules := make(retentionRules, len(policies))
+	for _, policy := range policies {
+		rules[[2]string{policy.DeviceID, policy.ChatJID}] = policy
+	}
+	return rules
+}
+
+// resolve returns the message and media retention in days of a chat, zero when the data is kept forever.
+// Each setting comes from the most specific policy that sets it.
+func (rules retentionRules) resolve(deviceID, chatJID string) (messageDays, mediaDays int) {
+	var message, media *int
+	for _, target := range [][2]string{{deviceID, chatJID}, {"", chatJID}, {deviceID, ""}, {"", ""}} {
+		policy, ok := rules[target]
+		if !ok {
+			continue
+		}
+		if message == nil {
+			message = policy.MessageDays
+		}
+		if media == nil {
+			media = policy.MediaDays
+		}
+	}
+
+	if message != nil {
+		messageDays = *message
+	}
+	if media != nil {
+		mediaDays = *media
+	}
+	return messageDays, mediaDays
+}
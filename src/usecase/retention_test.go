@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	domainRetention "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/retention"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/mediastorage"
+)
+
+func TestRetentionRules_MostSpecificSettingWins(t *testing.T) {
+	days := func(value int) *int { return &value }
+	device := "6289685028129@s.whatsapp.net"
+	group := "120363024512399999@g.us"
+
+	rules := newRetentionRules([]*domainRetention.Policy{
+		{MessageDays: days(90), MediaDays: days(30)},
+		{DeviceID: device, MediaDays: days(7)},
+		{ChatJID: group, MessageDays: days(0)},
+		{DeviceID: device, ChatJID: group, MediaDays: days(1)},
+	})
+
+	tests := []struct {
+		name        string
+		deviceID    string
+		chatJID     string
+		messageDays int
+		mediaDays   int
+	}{
+		{name: "global only", deviceID: "other", chatJID: "6281111111111@s.whatsapp.net", messageDays: 90, mediaDays: 30},
+		{name: "device overrides media", deviceID: device, chatJID: "6281111111111@s.whatsapp.net", messageDays: 90, mediaDays: 7},
+		{name: "chat keeps messages on every device", deviceID: "other", chatJID: group, messageDays: 0, mediaDays: 30},
+		{name: "chat on device overrides media", deviceID: device, chatJID: group, messageDays: 0, mediaDays: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageDays, mediaDays := rules.resolve(tt.deviceID, tt.chatJID)
+			if messageDays != tt.messageDays || mediaDays != tt.mediaDays {
+				t.Errorf("expected %d/%d days, got %d/%d", tt.messageDays, tt.mediaDays, messageDays, mediaDays)
+			}
+		})
+	}
+}
+
+func TestExpireMediaFiles_DeletesOldFilesOnly(t *testing.T) {
+	storage := mediastorage.NewLocal(t.TempDir())
+	ctx := context.Background()
+	oldKey, newKey := "statics/media/6289685028129/old.jpg", "statics/media/6289685028129/new.jpg"
+	for _, key := range []string{oldKey, newKey} {
+		if err := storage.Put(ctx, key, strings.NewReader("jpeg"), 4, "image/jpeg"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	objects, err := storage.List(ctx, "statics/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range objects {
+		if objects[i].Key == oldKey {
+			objects[i].ModTime = time.Now().AddDate(0, 0, -10)
+		}
+	}
+
+	dryRun := &domainRetention.MediaReport{Folder: "statics/media/6289685028129", Cutoff: time.Now().AddDate(0, 0, -7)}
+	expireMediaFiles(storage, dryRun, objects, true)
+	if dryRun.FilesDeleted != 1 || dryRun.BytesFreed != objects[0].Size {
+		t.Errorf("expected dry run to count one file, got %+v", dryRun)
+	}
+	if _, err := storage.Open(ctx, oldKey); err != nil {
+		t.Errorf("expected dry run to keep the file, got %v", err)
+	}
+
+	run := &domainRetention.MediaReport{Folder: dryRun.Folder, Cutoff: dryRun.Cutoff}
+	expireMediaFiles(storage, run, objects, false)
+	if _, err := storage.Open(ctx, oldKey); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected expired file to be removed, got %v", err)
+	}
+	if _, err := storage.Open(ctx, newKey); err != nil {
+		t.Errorf("expected recent file to be kept, got %v", err)
+	}
+}
+
+func TestRemoveEmptyFolders(t *testing.T) {
+	root := t.TempDir()
+	empty := filepath.Join(root, "2024-01-01", "nested")
+	kept := filepath.Join(root, "2024-03-01", "new.jpg")
+	if err := os.MkdirAll(empty, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(kept), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(kept, []byte("jpeg"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	removeEmptyFolders(root)
+	if _, err := os.Stat(filepath.Join(root, "2024-01-01")); !os.IsNotExist(err) {
+		t.Errorf("expected empty folders to be removed, got %v", err)
+	}
+	if _, err := os.Stat(kept); err != nil {
+		t.Errorf("expected file to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	scheduleBatchSize   = 20
+	scheduleIdleWait    = 30 * time.Second
+	scheduleMinWait     = time.Second
+	scheduleOfflineWait = 5 * time.Second
+	scheduleSendTimeout = 2 * time.Minute
+)
+
+type serviceSchedule struct {
+	scheduleRepo  domainSchedule.IScheduleRepository
+	sendService   domainSend.ISendUsecase
+	templateRepo  domainTemplate.ITemplateRepository
+	deviceManager *whatsapp.DeviceManager
+	wake          *deviceWakeups
+}
+
+func NewScheduleService(scheduleRepo domainSchedule.IScheduleRepository, sendService domainSend.ISendUsecase, templateRepo domainTemplate.ITemplateRepository, deviceManager *whatsapp.DeviceManager) domainSchedule.IScheduleUsecase {
+	return &serviceSchedule{
+		scheduleRepo:  scheduleRepo,
+		sendService:   sendService,
+		templateRepo:  templateRepo,
+		deviceManager: deviceManager,
+		wake:          newDeviceWakeups(),
+	}
+}
+
+func (service serviceSchedule) Schedule(ctx context.Context, request domainSchedule.CreateRequest) (domainSchedule.ScheduledMessage, error) {
+	if err := validations.ValidateScheduleMessage(ctx, &request); err != nil {
+		return domainSchedule.ScheduledMessage{}, err
+	}
+
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return domainSchedule.ScheduledMessage{}, err
+	}
+
+	prepared, err := prepareSendRequest(request.Type, request.Payload)
+	if err != nil {
+		return domainSchedule.ScheduledMessage{}, err
+	}
+	if err := prepared.validate(ctx, service.templateRepo); err != nil {
+		return domainSchedule.ScheduledMessage{}, err
+	}
+	payload, err := prepared.payload()
+	if err != nil {
+		return domainSchedule.ScheduledMessage{}, fmt.Errorf("failed to encode scheduled message: %w", err)
+	}
+
+	sendAt, _ := time.Parse(time.RFC3339, request.SendAt)
+	schedule := &domainSchedule.ScheduledMessage{
+		DeviceID: deviceID,
+		Type:     request.Type,
+		Phone:    prepared.phone,
+		Payload:  payload,
+		SendAt:   sendAt,
+	}
+	if err := service.scheduleRepo.CreateSchedule(schedule); err != nil {
+		return domainSchedule.ScheduledMessage{}, fmt.Errorf("failed to save scheduled message: %w", err)
+	}
+
+	service.wake.notify(deviceID)
+	return *schedule, nil
+}
+
+func (service serviceSchedule) List(ctx context.Context, request domainSchedule.ListRequest) (response domainSchedule.ListResponse, err error) {
+	if err = validations.ValidateListSchedules(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	schedules, total, err := service.scheduleRepo.ListSchedules(&domainSchedule.Filter{
+		DeviceID: deviceID,
+		Status:   domainSchedule.Status(request.Status),
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	})
+	if err != nil {
+		return response, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	response.Data = make([]domainSchedule.ScheduledMessage, 0, len(schedules))
+	for _, schedule := range schedules {
+		response.Data = append(response.Data, *schedule)
+	}
+	response.Pagination = domainSchedule.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceSchedule) Get(ctx context.Context, scheduleID string) (domainSchedule.ScheduledMessage, error) {
+	deviceID, err := deviceRegistryID(ctx)
+	if err != nil {
+		return domainSchedule.ScheduledMessage{}, err
+	}
+
+	schedule, err := service.scheduleRepo.GetSchedule(deviceID, scheduleID)
+	if err != nil {
+		return domainSchedule.ScheduledMessage{}, fmt.Errorf("failed to get scheduled message: %w", err)
+	}
+	if schedule == nil {
+		return domainSchedule.ScheduledMessage{}, pkgError.NotFoundError(fmt.Sprintf("scheduled message %s not found", scheduleID))
+	}
+	return *schedule, nil
+}
+
+func (service serviceSchedule) Cancel(ctx context.Context, scheduleID string) (domainSchedule.ScheduledMessage, error) {
+	schedule, err := service.Get(ctx, scheduleID)
+	if err != nil {
+		return schedule, err
+	}
+
+	cancelled, err := service.scheduleRepo.UpdateScheduleStatus(schedule.DeviceID, scheduleID,
+		domainSchedule.StatusCancelled, domainSchedule.StatusScheduled, domainSchedule.StatusFailed)
+	if err != nil {
+		return schedule, fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+	if !cancelled {
+		return schedule, pkgError.ValidationError(fmt.Sprintf("scheduled message is already %s", schedule.Status))
+	}
+	return service.Get(ctx, scheduleID)
+}
+
+func (service serviceSchedule) Reschedule(ctx context.Context, request domainSchedule.RescheduleRequest) (domainSchedule.ScheduledMessage, error) {
+	if err := validations.ValidateReschedule(ctx, &request); err != nil {
+		return domainSchedule.ScheduledMessage{}, err
+	}
+
+	schedule, err := service.Get(ctx, request.ScheduleID)
+	if err != nil {
+		return schedule, err
+	}
+
+	sendAt, _ := time.Parse(time.RFC3339, request.SendAt)
+	rescheduled, err := service.scheduleRepo.RescheduleSchedule(schedule.DeviceID, request.ScheduleID, sendAt)
+	if err != nil {
+		return schedule, fmt.Errorf("failed to reschedule message: %w", err)
+	}
+	if !rescheduled {
+		return schedule, pkgError.ValidationError(fmt.Sprintf("scheduled message is already %s", schedule.Status))
+	}
+
+	service.wake.notify(schedule.DeviceID)
+	return service.Get(ctx, request.ScheduleID)
+}
+
+func (service serviceSchedule) Run(ctx context.Context) {
+	if failed, err := service.scheduleRepo.FailInterruptedSchedules(); err != nil {
+		logrus.Errorf("Failed to recover interrupted scheduled messages: %v", err)
+	} else if failed > 0 {
+		logrus.Warnf("Marked %d scheduled message(s) interrupted by the last shutdown as failed", failed)
+	}
+
+	superviseDeviceWorkers(ctx, service.deviceManager, "scheduler", service.runDeviceWorker)
+}
+
+// runDeviceWorker sends due messages of one device, sleeping until the next send_at or a wake-up.
+func (service serviceSchedule) runDeviceWorker(ctx context.Context, deviceID string) {
+	wake := service.wake.channel(deviceID)
+	defer service.wake.remove(deviceID)
+
+	for {
+		wait := scheduleOfflineWait
+		if service.dispatchDue(ctx, deviceID) {
+			wait = service.nextWait(deviceID)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-wake:
+			timer.Stop()
+		}
+	}
+}
+
+// dispatchDue sends every due schedule of the device. It returns false when the device cannot send right now.
+func (service serviceSchedule) dispatchDue(ctx context.Context, deviceID string) bool {
+	inst, ok := service.deviceManager.GetDevice(deviceID)
+	if !ok || inst == nil || !inst.IsConnected() || !inst.IsLoggedIn() {
+		return false
+	}
+
+	schedules, err := service.scheduleRepo.FetchDueSchedules(deviceID, time.Now(), scheduleBatchSize)
+	if err != nil {
+		logrus.Errorf("Failed to fetch due scheduled messages for device %s: %v", deviceID, err)
+		return true
+	}
+
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return true
+		}
+
+		claimed, err := service.scheduleRepo.ClaimSchedule(schedule.ID)
+		if err != nil {
+			logrus.Errorf("Failed to claim scheduled message %s: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		service.deliver(whatsapp.ContextWithDevice(ctx, inst), schedule)
+	}
+	return true
+}
+
+func (service serviceSchedule) deliver(ctx context.Context, schedule *domainSchedule.ScheduledMessage) {
+	// Let an in-flight send finish on shutdown instead of leaving the row in sending.
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), scheduleSendTimeout)
+	defer cancel()
+
+	prepared, err := prepareSendRequest(schedule.Type, schedule.Payload)
+	var response domainSend.GenericResponse
+	if err == nil {
+		response, err = prepared.send(sendCtx, service.sendService)
+	}
+	if err != nil {
+		if markErr := service.scheduleRepo.MarkScheduleFailed(schedule.ID, err.Error()); markErr != nil {
+			logrus.Errorf("Failed to record scheduled message %s failure: %v", schedule.ID, markErr)
+		}
+		logrus.Warnf("Scheduled message %s to %s failed: %v", schedule.ID, schedule.Phone, err)
+		return
+	}
+
+	sentAt := time.Now().UTC()
+	if err := service.scheduleRepo.MarkScheduleSent(schedule.ID, response.MessageID, sentAt); err != nil {
+		logrus.Errorf("Failed to mark scheduled message %s as sent: %v", schedule.ID, err)
+	}
+	logrus.Infof("Scheduled message %s sent to %s as %s", schedule.ID, schedule.Phone, response.MessageID)
+
+	payload := map[string]any{
+		"schedule_id": schedule.ID,
+		"message_id":  response.MessageID,
+		"type":        schedule.Type,
+		"phone":       schedule.Phone,
+		"send_at":     schedule.SendAt.Format(time.RFC3339),
+		"sent_at":     sentAt.Format(time.RFC3339),
+	}
+	if err := whatsapp.ForwardEventToWebhooks(sendCtx, whatsapp.EventTypeMessageScheduledSent, payload); err != nil {
+		logrus.Errorf("Failed to forward scheduled message %s event to webhook: %v", schedule.ID, err)
+	}
+}
+
+func (service serviceSchedule) nextWait(deviceID string) time.Duration {
+	next, err := service.scheduleRepo.NextSendAt(deviceID)
+	if err != nil || next == nil {
+		return scheduleIdleWait
+	}
+
+	wait := time.Until(*next)
+	if wait < scheduleMinWait {
+		return scheduleMinWait
+	}
+	if wait > scheduleIdleWait {
+		return scheduleIdleWait
+	}
+	return wait
+}
@@ -23,9 +23,11 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/pollstore"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
@@ -45,21 +47,53 @@ var webpCanvasSizeRegex = regexp.MustCompile(`Canvas size:\s*(\d+)\s*x\s*(\d+)`)
 type serviceSend struct {
 	appService      app.IAppUsecase
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	templateRepo    domainTemplate.ITemplateRepository
 }
 
-func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainSend.ISendUsecase {
+func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository, templateRepo domainTemplate.ITemplateRepository) domainSend.ISendUsecase {
 	return &serviceSend{
 		appService:      appService,
 		chatStorageRepo: chatStorageRepo,
+		templateRepo:    templateRepo,
+	}
+}
+
+// loadTemplate replaces text with the content of the referenced template so validation sees its placeholders.
+func loadTemplate(templates domainTemplate.ITemplateRepository, text *string, template domainSend.TemplateRequest) error {
+	if template.TemplateID == "" {
+		return nil
+	}
+	if *text != "" {
+		return pkgError.ValidationError("template_id cannot be combined with an inline message or caption")
+	}
+
+	stored, err := templates.GetTemplate(template.TemplateID)
+	if err != nil {
+		return fmt.Errorf("failed to load template: %w", err)
+	}
+	if stored == nil {
+		return pkgError.NotFoundError(fmt.Sprintf("template %s not found", template.TemplateID))
+	}
+	*text = stored.Content
+	return nil
+}
+
+// renderTemplate fills the placeholders of text once validation confirmed every variable has a value.
+func renderTemplate(text *string, template domainSend.TemplateRequest) {
+	if template.IsTemplated() {
+		*text, _ = utils.RenderTemplate(*text, template.Variables)
 	}
 }
 
 // wrapSendMessage wraps the message sending process with message ID saving
 func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
-	ts, err := client.SendMessage(ctx, recipient, msg)
+	ts, err := tracing.Call(ctx, "whatsmeow.SendMessage", func(ctx context.Context) (whatsmeow.SendResponse, error) {
+		return client.SendMessage(ctx, recipient, msg)
+	})
 	if err != nil {
 		return whatsmeow.SendResponse{}, err
 	}
+	countSentMessage(ctx, msg)
 
 	// Store the sent message using chatstorage
 	senderJID := ""
@@ -70,7 +104,7 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeo
 	// Store message asynchronously with timeout
 	// Use a goroutine to avoid blocking the send operation
 	go func() {
-		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		storeCtx, cancel := context.WithTimeout(tracing.Detach(ctx), 2*time.Second)
 		defer cancel()
 
 		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
@@ -85,18 +119,30 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeo
 	return ts, nil
 }
 
+// countSentMessage records a message WhatsApp accepted in the messages sent metric
+func countSentMessage(ctx context.Context, msg *waE2E.Message) {
+	metrics.MessagesSent.WithLabelValues(whatsapp.InstanceIDFromContext(ctx), metrics.MessageType(msg)).Inc()
+}
+
 func (service serviceSend) SendText(ctx context.Context, request domainSend.MessageRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendText")
+	defer func() { tracing.End(span, err) }()
+
+	if err = loadTemplate(service.templateRepo, &request.Message, request.TemplateRequest); err != nil {
+		return response, err
+	}
 	err = validations.ValidateSendMessage(ctx, request)
 	if err != nil {
 		return response, err
 	}
+	renderTemplate(&request.Message, request.TemplateRequest)
 
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -238,17 +284,24 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 }
 
 func (service serviceSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendImage")
+	defer func() { tracing.End(span, err) }()
+
+	if err = loadTemplate(service.templateRepo, &request.Caption, request.TemplateRequest); err != nil {
+		return response, err
+	}
 	err = validations.ValidateSendImage(ctx, request)
 	if err != nil {
 		return response, err
 	}
+	renderTemplate(&request.Caption, request.TemplateRequest)
 
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -393,17 +446,24 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 }
 
 func (service serviceSend) SendFile(ctx context.Context, request domainSend.FileRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendFile")
+	defer func() { tracing.End(span, err) }()
+
+	if err = loadTemplate(service.templateRepo, &request.Caption, request.TemplateRequest); err != nil {
+		return response, err
+	}
 	err = validations.ValidateSendFile(ctx, request)
 	if err != nil {
 		return response, err
 	}
+	renderTemplate(&request.Caption, request.TemplateRequest)
 
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -675,6 +735,9 @@ func generateDefaultWaveform() []byte {
 }
 
 func (service serviceSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendVideo")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateSendVideo(ctx, request)
 	if err != nil {
 		return response, err
@@ -685,7 +748,7 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -855,6 +918,9 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 }
 
 func (service serviceSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendContact")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateSendContact(ctx, request)
 	if err != nil {
 		return response, err
@@ -865,7 +931,7 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -905,6 +971,9 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 }
 
 func (service serviceSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendLink")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateSendLink(ctx, request)
 	if err != nil {
 		return response, err
@@ -915,7 +984,7 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -987,6 +1056,9 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 }
 
 func (service serviceSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendLocation")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateSendLocation(ctx, request)
 	if err != nil {
 		return response, err
@@ -997,7 +1069,7 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -1039,6 +1111,9 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendAudio")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate request
 	err = validations.ValidateSendAudio(ctx, request)
 	if err != nil {
@@ -1050,7 +1125,7 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -1177,6 +1252,9 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 }
 
 func (service serviceSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendPoll")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateSendPoll(ctx, request)
 	if err != nil {
 		return response, err
@@ -1187,7 +1265,7 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.BaseRequest.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.BaseRequest.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -1209,13 +1287,21 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		return response, err
 	}
 
-	// Save poll data to store
-	pollData := pollstore.PollData{
-		Question: request.Question,
-		Options:  request.Options,
-		EncKey:   msg.GetMessageContextInfo().GetMessageSecret(),
+	// Save the poll so incoming votes can be decrypted and counted
+	deviceID, chatJID := storageKeys(ctx, client, dataWaRecipient)
+	err = service.chatStorageRepo.StorePoll(&domainChatStorage.Poll{
+		DeviceID:        deviceID,
+		MessageID:       ts.ID,
+		ChatJID:         chatJID,
+		Question:        request.Question,
+		Options:         request.Options,
+		SelectableCount: request.MaxAnswer,
+		EncKey:          msg.GetMessageContextInfo().GetMessageSecret(),
+		CreatedAt:       ts.Timestamp,
+	})
+	if err != nil {
+		logrus.Warnf("Failed to store poll %s: %v", ts.ID, err)
 	}
-	pollstore.DefaultPollStore.SavePoll(ts.ID, pollData)
 
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Send poll success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
@@ -1223,6 +1309,9 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 }
 
 func (service serviceSend) SendPresence(ctx context.Context, request domainSend.PresenceRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendPresence")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateSendPresence(ctx, request)
 	if err != nil {
 		return response, err
@@ -1233,7 +1322,9 @@ func (service serviceSend) SendPresence(ctx context.Context, request domainSend.
 		return response, pkgError.ErrWaCLI
 	}
 
-	err = client.SendPresence(ctx, types.Presence(request.Type))
+	err = tracing.Run(ctx, "whatsmeow.SendPresence", func(ctx context.Context) error {
+		return client.SendPresence(ctx, types.Presence(request.Type))
+	})
 	if err != nil {
 		return response, err
 	}
@@ -1244,6 +1335,9 @@ func (service serviceSend) SendPresence(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendChatPresence(ctx context.Context, request domainSend.ChatPresenceRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendChatPresence")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateSendChatPresence(ctx, request)
 	if err != nil {
 		return response, err
@@ -1254,7 +1348,7 @@ func (service serviceSend) SendChatPresence(ctx context.Context, request domainS
 		return response, pkgError.ErrWaCLI
 	}
 
-	userJid, err := utils.ValidateJidWithLogin(client, request.Phone)
+	userJid, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -1284,7 +1378,9 @@ func (service serviceSend) SendChatPresence(ctx context.Context, request domainS
 		return response, fmt.Errorf("invalid action: %s. Must be 'start', 'stop' or 'recording'", request.Action)
 	}
 
-	err = client.SendChatPresence(ctx, userJid, presenceType, mediaType) // Usar mediaType aqui
+	err = tracing.Run(ctx, "whatsmeow.SendChatPresence", func(ctx context.Context) error {
+		return client.SendChatPresence(ctx, userJid, presenceType, mediaType)
+	}) // Usar mediaType aqui
 	if err != nil {
 		return response, err
 	}
@@ -1303,7 +1399,7 @@ func (service serviceSend) getMentionFromText(ctx context.Context, messages stri
 	mentions := utils.ContainsMention(messages)
 	for _, mention := range mentions {
 		// Get JID from phone number
-		if dataWaRecipient, err := utils.ValidateJidWithLogin(client, mention); err == nil {
+		if dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, mention); err == nil {
 			result = append(result, dataWaRecipient.String())
 		}
 	}
@@ -1322,7 +1418,9 @@ func (service serviceSend) getMentionsFromList(ctx context.Context, mentions []s
 		// Handle @everyone keyword - fetch all group participants
 		if mention == "@everyone" {
 			if recipientJID.Server == types.GroupServer {
-				groupInfo, err := client.GetGroupInfo(ctx, recipientJID)
+				groupInfo, err := tracing.Call(ctx, "whatsmeow.GetGroupInfo", func(ctx context.Context) (*types.GroupInfo, error) {
+					return client.GetGroupInfo(ctx, recipientJID)
+				})
 				if err == nil && groupInfo != nil {
 					for _, participant := range groupInfo.Participants {
 						result = append(result, participant.JID.String())
@@ -1333,7 +1431,7 @@ func (service serviceSend) getMentionsFromList(ctx context.Context, mentions []s
 		}
 
 		// Regular phone number mention
-		if dataWaRecipient, err := utils.ValidateJidWithLogin(client, mention); err == nil {
+		if dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, mention); err == nil {
 			result = append(result, dataWaRecipient.String())
 		}
 	}
@@ -1341,6 +1439,9 @@ func (service serviceSend) getMentionsFromList(ctx context.Context, mentions []s
 }
 
 func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := tracing.Start(ctx, "send.SendSticker")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate request
 	err = validations.ValidateSendSticker(ctx, request)
 	if err != nil {
@@ -1352,7 +1453,7 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -1662,9 +1763,13 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 
 func (service serviceSend) uploadMedia(ctx context.Context, client *whatsmeow.Client, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
 	if recipient.Server == types.NewsletterServer {
-		uploaded, err = client.UploadNewsletter(ctx, media, mediaType)
+		uploaded, err = tracing.Call(ctx, "whatsmeow.UploadNewsletter", func(ctx context.Context) (whatsmeow.UploadResponse, error) {
+			return client.UploadNewsletter(ctx, media, mediaType)
+		})
 	} else {
-		uploaded, err = client.Upload(ctx, media, mediaType)
+		uploaded, err = tracing.Call(ctx, "whatsmeow.Upload", func(ctx context.Context) (whatsmeow.UploadResponse, error) {
+			return client.Upload(ctx, media, mediaType)
+		})
 	}
 	return uploaded, err
 }
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+// preparedSend is a decoded send request that can be validated now and sent later.
+type preparedSend struct {
+	phone    string
+	request  any
+	validate func(ctx context.Context, templates domainTemplate.ITemplateRepository) error
+	send     func(ctx context.Context, sendService domainSend.ISendUsecase) (domainSend.GenericResponse, error)
+}
+
+// payload re-encodes the sanitized request for storage.
+func (p *preparedSend) payload() (json.RawMessage, error) {
+	return json.Marshal(p.request)
+}
+
+// prepareSendRequest decodes a stored JSON body into the domainSend request matching sendType.
+// Only JSON-representable fields survive, so media must be given as a URL or base64 path rather than an upload.
+func prepareSendRequest(sendType string, payload json.RawMessage) (*preparedSend, error) {
+	switch sendType {
+	case domainSend.TypeMessage:
+		return decodeSendRequest(payload, func(r *domainSend.MessageRequest) *string { return &r.Phone },
+			validations.ValidateSendMessage, domainSend.ISendUsecase.SendText)
+	case domainSend.TypeImage:
+		return decodeSendRequest(payload, func(r *domainSend.ImageRequest) *string { return &r.Phone },
+			validations.ValidateSendImage, domainSend.ISendUsecase.SendImage)
+	case domainSend.TypeFile:
+		return decodeSendRequest(payload, func(r *domainSend.FileRequest) *string { return &r.Phone },
+			validations.ValidateSendFile, domainSend.ISendUsecase.SendFile)
+	case domainSend.TypeVideo:
+		return decodeSendRequest(payload, func(r *domainSend.VideoRequest) *string { return &r.Phone },
+			validations.ValidateSendVideo, domainSend.ISendUsecase.SendVideo)
+	case domainSend.TypeAudio:
+		return decodeSendRequest(payload, func(r *domainSend.AudioRequest) *string { return &r.Phone },
+			validations.ValidateSendAudio, domainSend.ISendUsecase.SendAudio)
+	case domainSend.TypeSticker:
+		return decodeSendRequest(payload, func(r *domainSend.StickerRequest) *string { return &r.Phone },
+			validations.ValidateSendSticker, domainSend.ISendUsecase.SendSticker)
+	case domainSend.TypeContact:
+		return decodeSendRequest(payload, func(r *domainSend.ContactRequest) *string { return &r.Phone },
+			validations.ValidateSendContact, domainSend.ISendUsecase.SendContact)
+	case domainSend.TypeLink:
+		return decodeSendRequest(payload, func(r *domainSend.LinkRequest) *string { return &r.Phone },
+			validations.ValidateSendLink, domainSend.ISendUsecase.SendLink)
+	case domainSend.TypeLocation:
+		return decodeSendRequest(payload, func(r *domainSend.LocationRequest) *string { return &r.Phone },
+			validations.ValidateSendLocation, domainSend.ISendUsecase.SendLocation)
+	case domainSend.TypePoll:
+		return decodeSendRequest(payload, func(r *domainSend.PollRequest) *string { return &r.Phone },
+			validations.ValidateSendPoll, domainSend.ISendUsecase.SendPoll)
+	default:
+		return nil, pkgError.ValidationError(fmt.Sprintf("unsupported message type: %s", sendType))
+	}
+}
+
+// templateText returns the text a stored template fills for the request types that support templates
+func templateText(request any) (*string, domainSend.TemplateRequest, bool) {
+	switch r := request.(type) {
+	case *domainSend.MessageRequest:
+		return &r.Message, r.TemplateRequest, true
+	case *domainSend.ImageRequest:
+		return &r.Caption, r.TemplateRequest, true
+	case *domainSend.FileRequest:
+		return &r.Caption, r.TemplateRequest, true
+	}
+	return nil, domainSend.TemplateRequest{}, false
+}
+
+func decodeSendRequest[T any](
+	payload json.RawMessage,
+	phone func(*T) *string,
+	validate func(context.Context, T) error,
+	send func(domainSend.ISendUsecase, context.Context, T) (domainSend.GenericResponse, error),
+) (*preparedSend, error) {
+	var request T
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("invalid message payload: %v", err))
+	}
+	utils.SanitizePhone(phone(&request))
+
+	return &preparedSend{
+		phone:   *phone(&request),
+		request: request,
+		validate: func(ctx context.Context, templates domainTemplate.ITemplateRepository) error {
+			// Validate with the stored template's content, so a missing variable fails now and not at send time
+			loaded := request
+			if text, template, ok := templateText(&loaded); ok {
+				if err := loadTemplate(templates, text, template); err != nil {
+					return err
+				}
+			}
+			return validate(ctx, loaded)
+		},
+		send: func(ctx context.Context, sendService domainSend.ISendUsecase) (domainSend.GenericResponse, error) {
+			return send(sendService, ctx, request)
+		},
+	}, nil
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+type recordingSendService struct {
+	domainSend.ISendUsecase
+	poll domainSend.PollRequest
+}
+
+func (r *recordingSendService) SendPoll(_ context.Context, request domainSend.PollRequest) (domainSend.GenericResponse, error) {
+	r.poll = request
+	return domainSend.GenericResponse{MessageID: "3EB0POLL"}, nil
+}
+
+func TestPrepareSendRequest_RoundTripsPoll(t *testing.T) {
+	raw := json.RawMessage(`{"phone":"6289685028129","question":"Lunch?","options":["yes","no"],"max_answer":1,"send_at":"2030-01-01T00:00:00Z"}`)
+
+	prepared, err := prepareSendRequest(domainSend.TypePoll, raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if prepared.phone != "6289685028129@s.whatsapp.net" {
+		t.Fatalf("expected sanitized phone, got %q", prepared.phone)
+	}
+
+	stored, err := prepared.payload()
+	if err != nil {
+		t.Fatalf("expected no error encoding payload, got %v", err)
+	}
+	reloaded, err := prepareSendRequest(domainSend.TypePoll, stored)
+	if err != nil {
+		t.Fatalf("expected stored payload to decode, got %v", err)
+	}
+
+	service := &recordingSendService{}
+	response, err := reloaded.send(context.Background(), service)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.MessageID != "3EB0POLL" || service.poll.Question != "Lunch?" || len(service.poll.Options) != 2 {
+		t.Fatalf("unexpected dispatch %+v / %+v", response, service.poll)
+	}
+}
+
+func TestPrepareSendRequest_RejectsUnknownType(t *testing.T) {
+	if _, err := prepareSendRequest("presence", json.RawMessage(`{}`)); err == nil {
+		t.Fatal("expected unsupported type to be rejected")
+	}
+}
+
+type fakeTemplateRepository struct {
+	domainTemplate.ITemplateRepository
+	templates map[string]*domainTemplate.Template
+}
+
+func (f *fakeTemplateRepository) GetTemplate(templateID string) (*domainTemplate.Template, error) {
+	return f.templates[templateID], nil
+}
+
+func TestPreparedSend_ValidatesStoredTemplateVariables(t *testing.T) {
+	templates := &fakeTemplateRepository{templates: map[string]*domainTemplate.Template{
+		"greeting": {ID: "greeting", Content: "Hi {{name}}, your order {{order}} shipped"},
+	}}
+
+	tests := []struct {
+		name       string
+		payload    string
+		wantStatus int
+	}{
+		{"all variables", `{"phone":"6289685028129","template_id":"greeting","variables":{"name":"Ana","order":"42"}}`, 0},
+		{"missing variable", `{"phone":"6289685028129","template_id":"greeting","variables":{"name":"Ana"}}`, http.StatusBadRequest},
+		{"unknown template", `{"phone":"6289685028129","template_id":"missing"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prepared, err := prepareSendRequest(domainSend.TypeMessage, json.RawMessage(tt.payload))
+			if err != nil {
+				t.Fatalf("expected payload to decode, got %v", err)
+			}
+			err = prepared.validate(context.Background(), templates)
+			var genericError pkgError.GenericError
+			if tt.wantStatus == 0 && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.wantStatus != 0 && (!errors.As(err, &genericError) || genericError.StatusCode() != tt.wantStatus) {
+				t.Fatalf("expected status %d, got %v", tt.wantStatus, err)
+			}
+		})
+	}
+
+	// The stored payload still refers to the template, it is rendered when the message is sent
+	prepared, _ := prepareSendRequest(domainSend.TypeMessage, json.RawMessage(tests[0].payload))
+	_ = prepared.validate(context.Background(), templates)
+	if prepared.request.(domainSend.MessageRequest).Message != "" {
+		t.Fatalf("expected validation to leave the request untouched, got %+v", prepared.request)
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type serviceTemplate struct {
+	templateRepo domainTemplate.ITemplateRepository
+}
+
+func NewTemplateService(templateRepo domainTemplate.ITemplateRepository) domainTemplate.ITemplateUsecase {
+	return &serviceTemplate{
+		templateRepo: templateRepo,
+	}
+}
+
+func (service serviceTemplate) ListTemplates(_ context.Context) ([]domainTemplate.Template, error) {
+	templates, err := service.templateRepo.ListTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	response := make([]domainTemplate.Template, 0, len(templates))
+	for _, template := range templates {
+		response = append(response, *template)
+	}
+	return response, nil
+}
+
+func (service serviceTemplate) GetTemplate(_ context.Context, templateID string) (domainTemplate.Template, error) {
+	template, err := service.findTemplate(templateID)
+	if err != nil {
+		return domainTemplate.Template{}, err
+	}
+	return *template, nil
+}
+
+func (service serviceTemplate) CreateTemplate(ctx context.Context, request domainTemplate.CreateTemplateRequest) (domainTemplate.Template, error) {
+	if err := validations.ValidateCreateTemplate(ctx, &request); err != nil {
+		return domainTemplate.Template{}, err
+	}
+
+	template := &domainTemplate.Template{
+		Name:    strings.TrimSpace(request.Name),
+		Content: request.Content,
+	}
+	if err := service.ensureUniqueName(template.Name, ""); err != nil {
+		return domainTemplate.Template{}, err
+	}
+	if err := service.templateRepo.SaveTemplate(template); err != nil {
+		return domainTemplate.Template{}, fmt.Errorf("failed to save template: %w", err)
+	}
+	return *template, nil
+}
+
+func (service serviceTemplate) UpdateTemplate(ctx context.Context, request domainTemplate.UpdateTemplateRequest) (domainTemplate.Template, error) {
+	if err := validations.ValidateUpdateTemplate(ctx, &request); err != nil {
+		return domainTemplate.Template{}, err
+	}
+
+	template, err := service.findTemplate(request.TemplateID)
+	if err != nil {
+		return domainTemplate.Template{}, err
+	}
+
+	if request.Name != nil {
+		template.Name = strings.TrimSpace(*request.Name)
+		if err := service.ensureUniqueName(template.Name, template.ID); err != nil {
+			return domainTemplate.Template{}, err
+		}
+	}
+	if request.Content != nil {
+		template.Content = *request.Content
+	}
+
+	if err := service.templateRepo.SaveTemplate(template); err != nil {
+		return domainTemplate.Template{}, fmt.Errorf("failed to save template: %w", err)
+	}
+	return *template, nil
+}
+
+func (service serviceTemplate) DeleteTemplate(_ context.Context, templateID string) error {
+	if _, err := service.findTemplate(templateID); err != nil {
+		return err
+	}
+	if err := service.templateRepo.DeleteTemplate(templateID); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+func (service serviceTemplate) findTemplate(templateID string) (*domainTemplate.Template, error) {
+	template, err := service.templateRepo.GetTemplate(templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	if template == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("template %s not found", templateID))
+	}
+	return template, nil
+}
+
+// ensureUniqueName rejects a name already used by a template other than exceptID.
+func (service serviceTemplate) ensureUniqueName(name, exceptID string) error {
+	existing, err := service.templateRepo.GetTemplateByName(name)
+	if err != nil {
+		return fmt.Errorf("failed to check template name: %w", err)
+	}
+	if existing != nil && existing.ID != exceptID {
+		return pkgError.ValidationError(fmt.Sprintf("name: template %q already exists.", name))
+	}
+	return nil
+}
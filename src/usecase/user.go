@@ -12,6 +12,7 @@ import (
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/tracing"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/disintegration/imaging"
@@ -32,6 +33,9 @@ func NewUserService(chatService domainChat.IChatUsecase) domainUser.IUserUsecase
 }
 
 func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequest) (response domainUser.InfoResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.Info")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateUserInfo(ctx, request)
 	if err != nil {
 		return response, err
@@ -43,13 +47,15 @@ func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequ
 	}
 
 	var jids []types.JID
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	jids = append(jids, dataWaRecipient)
-	resp, err := client.GetUserInfo(ctx, jids)
+	resp, err := tracing.Call(ctx, "whatsmeow.GetUserInfo", func(ctx context.Context) (map[types.JID]types.UserInfo, error) {
+		return client.GetUserInfo(ctx, jids)
+	})
 	if err != nil {
 		return response, err
 	}
@@ -92,6 +98,9 @@ func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequ
 }
 
 func (service serviceUser) Avatar(ctx context.Context, request domainUser.AvatarRequest) (response domainUser.AvatarResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.Avatar")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -102,7 +111,7 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -117,9 +126,11 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 	avatarCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	pic, err := client.GetProfilePictureInfo(avatarCtx, dataWaRecipient, &whatsmeow.GetProfilePictureParams{
-		Preview:     request.IsPreview,
-		IsCommunity: isCommunity,
+	pic, err := tracing.Call(avatarCtx, "whatsmeow.GetProfilePictureInfo", func(ctx context.Context) (*types.ProfilePictureInfo, error) {
+		return client.GetProfilePictureInfo(ctx, dataWaRecipient, &whatsmeow.GetProfilePictureParams{
+			Preview:     request.IsPreview,
+			IsCommunity: isCommunity,
+		})
 	})
 	if err != nil {
 		if avatarCtx.Err() == context.DeadlineExceeded {
@@ -130,9 +141,11 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 			avatarCtx2, cancel2 := context.WithTimeout(ctx, 5*time.Second)
 			defer cancel2()
 
-			pic, err = client.GetProfilePictureInfo(avatarCtx2, dataWaRecipient, &whatsmeow.GetProfilePictureParams{
-				Preview:     request.IsPreview,
-				IsCommunity: false,
+			pic, err = tracing.Call(avatarCtx2, "whatsmeow.GetProfilePictureInfo", func(ctx context.Context) (*types.ProfilePictureInfo, error) {
+				return client.GetProfilePictureInfo(ctx, dataWaRecipient, &whatsmeow.GetProfilePictureParams{
+					Preview:     request.IsPreview,
+					IsCommunity: false,
+				})
 			})
 			if err != nil {
 				if avatarCtx2.Err() == context.DeadlineExceeded {
@@ -156,13 +169,18 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 }
 
 func (service serviceUser) MyListGroups(ctx context.Context) (response domainUser.MyListGroupsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.MyListGroups")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
 	utils.MustLogin(client)
 
-	groups, err := client.GetJoinedGroups(ctx)
+	groups, err := tracing.Call(ctx, "whatsmeow.GetJoinedGroups", func(ctx context.Context) ([]*types.GroupInfo, error) {
+		return client.GetJoinedGroups(ctx)
+	})
 	if err != nil {
 		return
 	}
@@ -174,13 +192,18 @@ func (service serviceUser) MyListGroups(ctx context.Context) (response domainUse
 }
 
 func (service serviceUser) MyListNewsletter(ctx context.Context) (response domainUser.MyListNewsletterResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.MyListNewsletter")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
 	utils.MustLogin(client)
 
-	datas, err := client.GetSubscribedNewsletters(ctx)
+	datas, err := tracing.Call(ctx, "whatsmeow.GetSubscribedNewsletters", func(ctx context.Context) ([]*types.NewsletterMetadata, error) {
+		return client.GetSubscribedNewsletters(ctx)
+	})
 	if err != nil {
 		return
 	}
@@ -192,13 +215,18 @@ func (service serviceUser) MyListNewsletter(ctx context.Context) (response domai
 }
 
 func (service serviceUser) MyPrivacySetting(ctx context.Context) (response domainUser.MyPrivacySettingResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.MyPrivacySetting")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
 	utils.MustLogin(client)
 
-	resp, err := client.TryFetchPrivacySettings(ctx, true)
+	resp, err := tracing.Call(ctx, "whatsmeow.TryFetchPrivacySettings", func(ctx context.Context) (*types.PrivacySettings, error) {
+		return client.TryFetchPrivacySettings(ctx, true)
+	})
 	if err != nil {
 		return
 	}
@@ -211,6 +239,9 @@ func (service serviceUser) MyPrivacySetting(ctx context.Context) (response domai
 }
 
 func (service serviceUser) MyListContacts(ctx context.Context, request domainUser.MyListContactsRequest) (response domainUser.MyListContactsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.MyListContacts")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -298,7 +329,9 @@ func (service serviceUser) MyListContacts(ctx context.Context, request domainUse
 			if len(jidsToFetch) < maxFetch {
 				maxFetch = len(jidsToFetch)
 			}
-			userInfo, _ := client.GetUserInfo(ctx, jidsToFetch[:maxFetch])
+			userInfo, _ := tracing.Call(ctx, "whatsmeow.GetUserInfo", func(ctx context.Context) (map[types.JID]types.UserInfo, error) {
+				return client.GetUserInfo(ctx, jidsToFetch[:maxFetch])
+			})
 			for jid, info := range userInfo {
 				for i, item := range contactList {
 					if item.JID == jid && (item.Name == "" || item.Name == jid.User) {
@@ -362,7 +395,9 @@ func (service serviceUser) MyListContacts(ctx context.Context, request domainUse
 			if len(jidsToFetch) < maxFetch {
 				maxFetch = len(jidsToFetch)
 			}
-			userInfo, _ := client.GetUserInfo(ctx, jidsToFetch[:maxFetch])
+			userInfo, _ := tracing.Call(ctx, "whatsmeow.GetUserInfo", func(ctx context.Context) (map[types.JID]types.UserInfo, error) {
+				return client.GetUserInfo(ctx, jidsToFetch[:maxFetch])
+			})
 			for jid, info := range userInfo {
 				for i, item := range response.Data {
 					if item.JID == jid && (item.Name == "" || item.Name == jid.User) {
@@ -379,6 +414,9 @@ func (service serviceUser) MyListContacts(ctx context.Context, request domainUse
 }
 
 func (service serviceUser) ChangeAvatar(ctx context.Context, request domainUser.ChangeAvatarRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "user.ChangeAvatar")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return pkgError.ErrWaCLI
@@ -428,7 +466,9 @@ func (service serviceUser) ChangeAvatar(ctx context.Context, request domainUser.
 		return fmt.Errorf("failed to encode image: %v", err)
 	}
 
-	_, err = client.SetGroupPhoto(ctx, types.JID{}, buf.Bytes())
+	_, err = tracing.Call(ctx, "whatsmeow.SetGroupPhoto", func(ctx context.Context) (string, error) {
+		return client.SetGroupPhoto(ctx, types.JID{}, buf.Bytes())
+	})
 	if err != nil {
 		return err
 	}
@@ -437,13 +477,18 @@ func (service serviceUser) ChangeAvatar(ctx context.Context, request domainUser.
 }
 
 func (service serviceUser) ChangePushName(ctx context.Context, request domainUser.ChangePushNameRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "user.ChangePushName")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return pkgError.ErrWaCLI
 	}
 	utils.MustLogin(client)
 
-	err = client.SendAppState(ctx, appstate.BuildSettingPushName(request.PushName))
+	err = tracing.Run(ctx, "whatsmeow.SendAppState", func(ctx context.Context) error {
+		return client.SendAppState(ctx, appstate.BuildSettingPushName(request.PushName))
+	})
 	if err != nil {
 		return err
 	}
@@ -451,6 +496,9 @@ func (service serviceUser) ChangePushName(ctx context.Context, request domainUse
 }
 
 func (service serviceUser) IsOnWhatsApp(ctx context.Context, request domainUser.CheckRequest) (response domainUser.CheckResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.IsOnWhatsApp")
+	defer func() { tracing.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -459,12 +507,15 @@ func (service serviceUser) IsOnWhatsApp(ctx context.Context, request domainUser.
 
 	utils.SanitizePhone(&request.Phone)
 
-	response.IsOnWhatsApp = utils.IsOnWhatsapp(client, request.Phone)
+	response.IsOnWhatsApp = utils.IsOnWhatsapp(ctx, client, request.Phone)
 
 	return response, nil
 }
 
 func (service serviceUser) BusinessProfile(ctx context.Context, request domainUser.BusinessProfileRequest) (response domainUser.BusinessProfileResponse, err error) {
+	ctx, span := tracing.Start(ctx, "user.BusinessProfile")
+	defer func() { tracing.End(span, err) }()
+
 	err = validations.ValidateBusinessProfile(ctx, request)
 	if err != nil {
 		return response, err
@@ -475,12 +526,14 @@ func (service serviceUser) BusinessProfile(ctx context.Context, request domainUs
 		return response, pkgError.ErrWaCLI
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(ctx, client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
-	profile, err := client.GetBusinessProfile(ctx, dataWaRecipient)
+	profile, err := tracing.Call(ctx, "whatsmeow.GetBusinessProfile", func(ctx context.Context) (*types.BusinessProfile, error) {
+		return client.GetBusinessProfile(ctx, dataWaRecipient)
+	})
 	if err != nil {
 		return response, err
 	}
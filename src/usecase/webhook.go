@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceWebhook struct {
+	webhookRepo     domainWebhook.IWebhookRepository
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	deviceManager   *whatsapp.DeviceManager
+}
+
+func NewWebhookService(webhookRepo domainWebhook.IWebhookRepository, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceManager *whatsapp.DeviceManager) domainWebhook.IWebhookUsecase {
+	return &serviceWebhook{
+		webhookRepo:     webhookRepo,
+		chatStorageRepo: chatStorageRepo,
+		deviceManager:   deviceManager,
+	}
+}
+
+func (service serviceWebhook) ListDeliveries(ctx context.Context, request domainWebhook.ListDeliveriesRequest) (response domainWebhook.ListDeliveriesResponse, err error) {
+	if err = validations.ValidateListWebhookDeliveries(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deliveries, total, err := service.webhookRepo.ListDeliveries(&domainWebhook.DeliveryFilter{
+		DeviceID: request.DeviceID,
+		Status:   domainWebhook.DeliveryStatus(request.Status),
+		Event:    request.Event,
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	})
+	if err != nil {
+		return response, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	response.Data = make([]domainWebhook.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response.Data = append(response.Data, *delivery)
+	}
+	response.Pagination = domainWebhook.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+	return response, nil
+}
+
+func (service serviceWebhook) GetDelivery(_ context.Context, deliveryID string) (domainWebhook.Delivery, error) {
+	delivery, err := service.webhookRepo.GetDelivery(deliveryID)
+	if err != nil {
+		return domainWebhook.Delivery{}, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery == nil {
+		return domainWebhook.Delivery{}, pkgError.NotFoundError(fmt.Sprintf("webhook delivery %s not found", deliveryID))
+	}
+	return *delivery, nil
+}
+
+func (service serviceWebhook) RedriveDelivery(ctx context.Context, deliveryID string) (domainWebhook.Delivery, error) {
+	delivery, err := service.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return delivery, err
+	}
+	if delivery.Status != domainWebhook.DeliveryStatusDead {
+		return delivery, pkgError.ConflictError(fmt.Sprintf("only dead-lettered webhooks can be re-driven, delivery is %s", delivery.Status))
+	}
+
+	redriven, err := service.webhookRepo.RedriveDelivery(deliveryID)
+	if err != nil {
+		return delivery, fmt.Errorf("failed to re-drive webhook delivery: %w", err)
+	}
+	if !redriven {
+		return delivery, pkgError.ConflictError("webhook delivery changed while re-driving it, fetch it again")
+	}
+	return service.GetDelivery(ctx, deliveryID)
+}
+
+func (service serviceWebhook) RedriveDeadDeliveries(_ context.Context, request domainWebhook.RedriveDeliveriesRequest) (response domainWebhook.RedriveDeliveriesResponse, err error) {
+	count, err := service.webhookRepo.RedriveDeadDeliveries(request.DeviceID)
+	if err != nil {
+		return response, fmt.Errorf("failed to re-drive dead webhook deliveries: %w", err)
+	}
+	response.Redriven = count
+	return response, nil
+}
+
+func (service serviceWebhook) ListSubscriptions(_ context.Context, deviceID string) ([]domainWebhook.SubscriptionResponse, error) {
+	if err := service.ensureDevice(deviceID); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := service.webhookRepo.ListSubscriptions(deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	response := make([]domainWebhook.SubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, toSubscriptionResponse(subscription))
+	}
+	return response, nil
+}
+
+func (service serviceWebhook) GetSubscription(_ context.Context, deviceID, subscriptionID string) (domainWebhook.SubscriptionResponse, error) {
+	subscription, err := service.findSubscription(deviceID, subscriptionID)
+	if err != nil {
+		return domainWebhook.SubscriptionResponse{}, err
+	}
+	return toSubscriptionResponse(subscription), nil
+}
+
+func (service serviceWebhook) CreateSubscription(ctx context.Context, request domainWebhook.CreateSubscriptionRequest) (domainWebhook.SubscriptionResponse, error) {
+	if err := validations.ValidateCreateWebhookSubscription(ctx, &request); err != nil {
+		return domainWebhook.SubscriptionResponse{}, err
+	}
+	if err := service.ensureDevice(request.DeviceID); err != nil {
+		return domainWebhook.SubscriptionResponse{}, err
+	}
+
+	subscription := &domainWebhook.Subscription{
+		DeviceID: request.DeviceID,
+		URL:      strings.TrimSpace(request.URL),
+		Secret:   request.Secret,
+		Events:   normalizeWebhookEvents(request.Events),
+		Enabled:  request.Enabled == nil || *request.Enabled,
+	}
+	if err := service.webhookRepo.SaveSubscription(subscription); err != nil {
+		return domainWebhook.SubscriptionResponse{}, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return toSubscriptionResponse(subscription), nil
+}
+
+func (service serviceWebhook) UpdateSubscription(ctx context.Context, request domainWebhook.UpdateSubscriptionRequest) (domainWebhook.SubscriptionResponse, error) {
+	if err := validations.ValidateUpdateWebhookSubscription(ctx, &request); err != nil {
+		return domainWebhook.SubscriptionResponse{}, err
+	}
+
+	subscription, err := service.findSubscription(request.DeviceID, request.SubscriptionID)
+	if err != nil {
+		return domainWebhook.SubscriptionResponse{}, err
+	}
+
+	if request.URL != nil {
+		subscription.URL = strings.TrimSpace(*request.URL)
+	}
+	if request.Secret != nil {
+		subscription.Secret = *request.Secret
+	}
+	if request.Events != nil {
+		subscription.Events = normalizeWebhookEvents(*request.Events)
+	}
+	if request.Enabled != nil {
+		subscription.Enabled = *request.Enabled
+	}
+
+	if err := service.webhookRepo.SaveSubscription(subscription); err != nil {
+		return domainWebhook.SubscriptionResponse{}, fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return toSubscriptionResponse(subscription), nil
+}
+
+func (service serviceWebhook) DeleteSubscription(_ context.Context, deviceID, subscriptionID string) error {
+	if _, err := service.findSubscription(deviceID, subscriptionID); err != nil {
+		return err
+	}
+	if err := service.webhookRepo.DeleteSubscription(deviceID, subscriptionID); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (service serviceWebhook) Replay(ctx context.Context, request domainWebhook.ReplayRequest) (response domainWebhook.ReplayResponse, err error) {
+	if err = validations.ValidateReplayWebhook(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	filter := &domainChatStorage.MessageFilter{
+		DeviceID:    whatsapp.DeviceIDFromContext(ctx),
+		ChatJID:     request.ChatJID,
+		Limit:       request.Limit,
+		OldestFirst: true,
+	}
+	if request.StartTime != "" {
+		startTime, parseErr := time.Parse(time.RFC3339, request.StartTime)
+		if parseErr != nil {
+			return response, pkgError.ValidationError("start_time: must be a valid date.")
+		}
+		startTime = startTime.UTC()
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != "" {
+		endTime, parseErr := time.Parse(time.RFC3339, request.EndTime)
+		if parseErr != nil {
+			return response, pkgError.ValidationError("end_time: must be a valid date.")
+		}
+		endTime = endTime.UTC()
+		filter.EndTime = &endTime
+	}
+	if request.Cursor != "" {
+		if filter.After, err = decodeReplayCursor(request.Cursor); err != nil {
+			return response, pkgError.ValidationError("cursor: is invalid.")
+		}
+	}
+
+	messages, err := service.chatStorageRepo.GetMessages(filter)
+	if err != nil {
+		return response, fmt.Errorf("failed to load messages for replay: %w", err)
+	}
+
+	response.Matched = len(messages)
+	response.Failed = []string{}
+
+	// Replay from start_time onwards, in the order the events originally happened
+	for _, message := range messages {
+		if err := whatsapp.ReplayStoredMessage(ctx, client, message, request.WebhookURL); err != nil {
+			logrus.WithError(err).WithField("message_id", message.ID).Warn("Failed to replay message to webhook")
+			response.Failed = append(response.Failed, message.ID)
+			continue
+		}
+		response.Replayed++
+	}
+	if filter.Limit > 0 && len(messages) == filter.Limit {
+		last := messages[len(messages)-1]
+		response.NextCursor = encodeReplayCursor(&domainChatStorage.MessageCursor{Timestamp: last.Timestamp, ID: last.ID})
+	}
+	return response, nil
+}
+
+// replayCursor is the opaque next_cursor of a replay. The timestamp keeps its offset so it matches the stored
+// value exactly.
+type replayCursor struct {
+	Timestamp string `json:"t"`
+	ID        string `json:"id"`
+}
+
+func encodeReplayCursor(cursor *domainChatStorage.MessageCursor) string {
+	encoded, _ := json.Marshal(replayCursor{Timestamp: cursor.Timestamp.Format(time.RFC3339Nano), ID: cursor.ID})
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeReplayCursor(value string) (*domainChatStorage.MessageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var cursor replayCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, cursor.Timestamp)
+	if err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid replay cursor")
+	}
+	return &domainChatStorage.MessageCursor{Timestamp: timestamp, ID: cursor.ID}, nil
+}
+
+func (service serviceWebhook) ensureDevice(deviceID string) error {
+	if service.deviceManager == nil {
+		return fmt.Errorf("device manager not initialized")
+	}
+	if _, ok := service.deviceManager.GetDevice(deviceID); !ok {
+		return pkgError.NotFoundError(fmt.Sprintf("device %s not found", deviceID))
+	}
+	return nil
+}
+
+func (service serviceWebhook) findSubscription(deviceID, subscriptionID string) (*domainWebhook.Subscription, error) {
+	if err := service.ensureDevice(deviceID); err != nil {
+		return nil, err
+	}
+
+	subscription, err := service.webhookRepo.GetSubscription(deviceID, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if subscription == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("webhook subscription %s not found", subscriptionID))
+	}
+	return subscription, nil
+}
+
+func toSubscriptionResponse(subscription *domainWebhook.Subscription) domainWebhook.SubscriptionResponse {
+	response := domainWebhook.SubscriptionResponse{
+		Subscription: *subscription,
+		HasSecret:    subscription.Secret != "",
+	}
+	if response.Events == nil {
+		response.Events = []string{}
+	}
+	return response
+}
+
+// normalizeWebhookEvents trims and de-duplicates the whitelist so matching at dispatch time stays cheap.
+func normalizeWebhookEvents(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" || seen[strings.ToLower(event)] {
+			continue
+		}
+		seen[strings.ToLower(event)] = true
+		normalized = append(normalized, event)
+	}
+	return normalized
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow"
+)
+
+type replayMessageStore struct {
+	domainChatStorage.IChatStorageRepository
+	messages []*domainChatStorage.Message
+}
+
+func (s *replayMessageStore) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
+	var matched []*domainChatStorage.Message
+	for _, message := range s.messages {
+		if filter.StartTime != nil && message.Timestamp.Before(*filter.StartTime) {
+			continue
+		}
+		if after := filter.After; after != nil && (message.Timestamp.Before(after.Timestamp) ||
+			message.Timestamp.Equal(after.Timestamp) && message.ID <= after.ID) {
+			continue
+		}
+		matched = append(matched, message)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].Timestamp.Equal(matched[j].Timestamp) {
+			return matched[i].Timestamp.Before(matched[j].Timestamp) == filter.OldestFirst
+		}
+		return matched[i].ID < matched[j].ID
+	})
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, nil
+}
+
+type replayOutbox struct {
+	domainWebhook.IWebhookRepository
+	messageIDs []string
+}
+
+func (o *replayOutbox) EnqueueDelivery(delivery *domainWebhook.Delivery) error {
+	var decoded struct {
+		Payload struct {
+			ID string `json:"ID"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(delivery.Payload, &decoded); err != nil {
+		return err
+	}
+	o.messageIDs = append(o.messageIDs, decoded.Payload.ID)
+	return nil
+}
+
+func TestReplay_StartsAtStartTimeWhenLimited(t *testing.T) {
+	outbox := &replayOutbox{}
+	whatsapp.SetWebhookStore(outbox)
+	defer whatsapp.SetWebhookStore(nil)
+
+	start := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	store := &replayMessageStore{}
+	for i, id := range []string{"before", "first", "second", "third", "fourth"} {
+		store.messages = append(store.messages, &domainChatStorage.Message{
+			ID: id, ChatJID: "628123@s.whatsapp.net", Sender: "628123@s.whatsapp.net",
+			Content: id, Timestamp: start.Add(time.Duration(i-1) * time.Minute),
+		})
+	}
+
+	service := NewWebhookService(nil, store, nil)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("dev", &whatsmeow.Client{}, nil))
+	response, err := service.Replay(ctx, domainWebhook.ReplayRequest{
+		WebhookURL: "https://example.com/replay",
+		StartTime:  start.Format(time.RFC3339),
+		Limit:      2,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(outbox.messageIDs) != 2 || outbox.messageIDs[0] != "first" || outbox.messageIDs[1] != "second" {
+		t.Fatalf("expected the two messages right after start_time in order, got %v", outbox.messageIDs)
+	}
+	if response.NextCursor == "" {
+		t.Error("expected a cursor for the rest of the window")
+	}
+}
+
+func TestReplay_CursorPagesThroughMessagesSharingASecond(t *testing.T) {
+	outbox := &replayOutbox{}
+	whatsapp.SetWebhookStore(outbox)
+	defer whatsapp.SetWebhookStore(nil)
+
+	sentAt := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	store := &replayMessageStore{}
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		store.messages = append(store.messages, &domainChatStorage.Message{
+			ID: id, ChatJID: "628123@s.whatsapp.net", Sender: "628123@s.whatsapp.net", Content: id, Timestamp: sentAt,
+		})
+	}
+
+	service := NewWebhookService(nil, store, nil)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("dev", &whatsmeow.Client{}, nil))
+	request := domainWebhook.ReplayRequest{WebhookURL: "https://example.com/replay", StartTime: sentAt.Format(time.RFC3339), Limit: 2}
+	for page := 0; page < 5; page++ {
+		response, err := service.Replay(ctx, request)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if response.NextCursor == "" {
+			break
+		}
+		request.Cursor = response.NextCursor
+	}
+
+	if got := strings.Join(outbox.messageIDs, ","); got != "a,b,c,d,e" {
+		t.Fatalf("expected every message once in order, got %s", got)
+	}
+}
+
+func TestReplay_RejectsInvalidCursor(t *testing.T) {
+	service := NewWebhookService(nil, &replayMessageStore{}, nil)
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("dev", &whatsmeow.Client{}, nil))
+	_, err := service.Replay(ctx, domainWebhook.ReplayRequest{
+		WebhookURL: "https://example.com/replay", ChatJID: "628123@s.whatsapp.net", Cursor: "not-a-cursor",
+	})
+	if err != pkgError.ValidationError("cursor: is invalid.") {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package validations
+
+import (
+	"context"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateCreateAPIKey(ctx context.Context, request *domainAPIKey.CreateAPIKeyRequest) error {
+	permissions := make([]any, 0, len(domainAPIKey.Permissions))
+	for _, permission := range domainAPIKey.Permissions {
+		permissions = append(permissions, permission)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Devices, validation.Each(validation.Required)),
+		validation.Field(&request.Permissions, validation.Required, validation.Each(validation.Required, validation.In(permissions...))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAPIKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainAPIKey.CreateAPIKeyRequest
+		err     any
+	}{
+		{
+			name: "should success with devices and permissions",
+			request: domainAPIKey.CreateAPIKeyRequest{Name: "crm", Devices: []string{"sales"},
+				Permissions: []string{domainAPIKey.PermissionSend, domainAPIKey.PermissionReadChats}},
+			err: nil,
+		},
+		{
+			name:    "should success without devices",
+			request: domainAPIKey.CreateAPIKeyRequest{Name: "ops", Permissions: []string{domainAPIKey.PermissionAdmin}},
+			err:     nil,
+		},
+		{
+			name:    "should error without permissions",
+			request: domainAPIKey.CreateAPIKeyRequest{Name: "crm"},
+			err:     pkgError.ValidationError("permissions: cannot be blank."),
+		},
+		{
+			name:    "should error with unknown permission",
+			request: domainAPIKey.CreateAPIKeyRequest{Name: "crm", Permissions: []string{"send", "delete_everything"}},
+			err:     pkgError.ValidationError("permissions: (1: must be a valid value.)."),
+		},
+		{
+			name:    "should error with blank device",
+			request: domainAPIKey.CreateAPIKeyRequest{Name: "crm", Devices: []string{""}, Permissions: []string{"send"}},
+			err:     pkgError.ValidationError("devices: (0: cannot be blank.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateAPIKey(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
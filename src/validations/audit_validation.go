@@ -0,0 +1,30 @@
+package validations
+
+import (
+	"context"
+	"time"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateListAudit(ctx context.Context, request *domainAudit.ListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.StartTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
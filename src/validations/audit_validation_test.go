@@ -0,0 +1,46 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListAudit(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainAudit.ListRequest
+		err     any
+	}{
+		{
+			name:    "should success with empty filter",
+			request: domainAudit.ListRequest{},
+			err:     nil,
+		},
+		{
+			name:    "should success with time range",
+			request: domainAudit.ListRequest{StartTime: "2024-06-01T00:00:00Z", EndTime: "2024-06-02T00:00:00+07:00"},
+			err:     nil,
+		},
+		{
+			name:    "should error with invalid start time",
+			request: domainAudit.ListRequest{StartTime: "yesterday"},
+			err:     pkgError.ValidationError("start_time: must be a valid date."),
+		},
+		{
+			name:    "should error with limit too high",
+			request: domainAudit.ListRequest{Limit: 501},
+			err:     pkgError.ValidationError("limit: must be no greater than 500."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListAudit(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const maxCampaignRecipients = 10000
+
+func ValidateCreateCampaign(ctx context.Context, request *domainCampaign.CreateRequest) error {
+	// Fall back to the server-wide throttling defaults
+	if request.RatePerMinute == 0 {
+		request.RatePerMinute = config.WhatsappCampaignRatePerMinute
+	}
+	if request.JitterSeconds == nil {
+		jitter := config.WhatsappCampaignJitterSeconds
+		request.JitterSeconds = &jitter
+	}
+	if request.DailyCap == nil {
+		dailyCap := config.WhatsappCampaignDailyCap
+		request.DailyCap = &dailyCap
+	}
+
+	types := make([]any, len(domainSend.StorableTypes))
+	for i, t := range domainSend.StorableTypes {
+		types[i] = t
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 255)),
+		validation.Field(&request.Type, validation.Required, validation.In(types...)),
+		validation.Field(&request.Message, validation.Required),
+		validation.Field(&request.Recipients, validation.Required, validation.Length(1, maxCampaignRecipients)),
+		validation.Field(&request.RatePerMinute, validation.Min(1), validation.Max(120)),
+		validation.Field(&request.JitterSeconds, validation.Min(0), validation.Max(3600)),
+		validation.Field(&request.DailyCap, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	for i, recipient := range request.Recipients {
+		if strings.TrimSpace(recipient.Phone) == "" {
+			return pkgError.ValidationError(fmt.Sprintf("recipients: phone of recipient %d cannot be blank.", i+1))
+		}
+	}
+
+	return nil
+}
+
+func ValidateListCampaigns(ctx context.Context, request *domainCampaign.ListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			string(domainCampaign.StatusRunning),
+			string(domainCampaign.StatusPaused),
+			string(domainCampaign.StatusCompleted),
+			string(domainCampaign.StatusCancelled),
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(200)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListCampaignRecipients(ctx context.Context, request *domainCampaign.ListRecipientsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 100
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.CampaignID, validation.Required),
+		validation.Field(&request.Status, validation.In(
+			string(domainCampaign.RecipientQueued),
+			string(domainCampaign.RecipientSending),
+			string(domainCampaign.RecipientSent),
+			string(domainCampaign.RecipientFailed),
+			string(domainCampaign.RecipientNotOnWhatsApp),
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(1000)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package validations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainCampaign "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/campaign"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateCampaign(t *testing.T) {
+	message := json.RawMessage(`{"message":"Hi {{name}}"}`)
+	recipients := []domainCampaign.RecipientRequest{{Phone: "6289685028129"}}
+	negative := -1
+
+	tests := []struct {
+		name    string
+		request domainCampaign.CreateRequest
+		err     any
+	}{
+		{
+			name:    "should success with defaults",
+			request: domainCampaign.CreateRequest{Name: "Promo", Type: "message", Message: message, Recipients: recipients},
+			err:     nil,
+		},
+		{
+			name:    "should error without recipients",
+			request: domainCampaign.CreateRequest{Name: "Promo", Type: "message", Message: message},
+			err:     pkgError.ValidationError("recipients: cannot be blank."),
+		},
+		{
+			name:    "should error with unsupported type",
+			request: domainCampaign.CreateRequest{Name: "Promo", Type: "presence", Message: message, Recipients: recipients},
+			err:     pkgError.ValidationError("type: must be a valid value."),
+		},
+		{
+			name:    "should error with rate above limit",
+			request: domainCampaign.CreateRequest{Name: "Promo", Type: "message", Message: message, Recipients: recipients, RatePerMinute: 500},
+			err:     pkgError.ValidationError("rate_per_minute: must be no greater than 120."),
+		},
+		{
+			name:    "should error with negative jitter",
+			request: domainCampaign.CreateRequest{Name: "Promo", Type: "message", Message: message, Recipients: recipients, JitterSeconds: &negative},
+			err:     pkgError.ValidationError("jitter_seconds: must be no less than 0."),
+		},
+		{
+			name: "should error with blank recipient phone",
+			request: domainCampaign.CreateRequest{Name: "Promo", Type: "message", Message: message,
+				Recipients: []domainCampaign.RecipientRequest{{Phone: "6289685028129"}, {Phone: " "}}},
+			err: pkgError.ValidationError("recipients: phone of recipient 2 cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateCampaign(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCreateCampaign_AppliesConfigDefaults(t *testing.T) {
+	request := domainCampaign.CreateRequest{
+		Name:       "Promo",
+		Type:       "message",
+		Message:    json.RawMessage(`{"message":"hi"}`),
+		Recipients: []domainCampaign.RecipientRequest{{Phone: "6289685028129"}},
+	}
+
+	err := ValidateCreateCampaign(context.Background(), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, config.WhatsappCampaignRatePerMinute, request.RatePerMinute)
+	assert.Equal(t, config.WhatsappCampaignJitterSeconds, *request.JitterSeconds)
+	assert.Equal(t, config.WhatsappCampaignDailyCap, *request.DailyCap)
+}
@@ -2,6 +2,8 @@ package validations
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -45,6 +47,70 @@ func ValidateGetChatMessages(ctx context.Context, request *domainChat.GetChatMes
 	return nil
 }
 
+func ValidateSearchMessages(ctx context.Context, request *domainChat.SearchMessagesRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 20
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Query, validation.Required, validation.Length(1, 255)),
+		validation.Field(&request.MediaType, validation.In("image", "video", "audio", "document", "sticker")),
+		validation.Field(&request.StartTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateExportChat(ctx context.Context, request *domainChat.ExportChatRequest) error {
+	if request.Format == "" {
+		request.Format = domainChat.ExportFormatText
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.Format, validation.In(domainChat.ExportFormatText, domainChat.ExportFormatJSON, domainChat.ExportFormatHTML)),
+		validation.Field(&request.StartTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateImportChat(ctx context.Context, request *domainChat.ImportChatRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.DateOrder, validation.In(domainChat.ImportDateOrderDMY, domainChat.ImportDateOrderMDY)),
+		validation.Field(&request.Timezone, validation.By(func(value any) error {
+			if _, err := time.LoadLocation(value.(string)); err != nil {
+				return fmt.Errorf("unknown time zone %q", value)
+			}
+			return nil
+		})),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if len(request.File) == 0 {
+		return pkgError.ValidationError("file: cannot be blank.")
+	}
+
+	return nil
+}
+
 func ValidatePinChat(ctx context.Context, request *domainChat.PinChatRequest) error {
 	err := validation.ValidateStructWithContext(ctx, request,
 		validation.Field(&request.ChatJID, validation.Required),
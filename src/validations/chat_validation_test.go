@@ -141,6 +141,72 @@ func TestValidateGetChatMessages(t *testing.T) {
 	}
 }
 
+func TestValidateSearchMessages(t *testing.T) {
+	startTime := "2024-01-01T00:00:00Z"
+	badTime := "2024-01-01"
+
+	type args struct {
+		request domainChat.SearchMessagesRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with query and filters",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     "invoice",
+				MediaType: "document",
+				StartTime: &startTime,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty query",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query: "",
+			}},
+			err: pkgError.ValidationError("query: cannot be blank."),
+		},
+		{
+			name: "should error with unknown media type",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     "invoice",
+				MediaType: "gif",
+			}},
+			err: pkgError.ValidationError("media_type: must be a valid value."),
+		},
+		{
+			name: "should error with non RFC3339 end time",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:   "invoice",
+				EndTime: &badTime,
+			}},
+			err: pkgError.ValidationError("end_time: must be a valid date."),
+		},
+		{
+			name: "should error with limit too high",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query: "invoice",
+				Limit: 101,
+			}},
+			err: pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSearchMessages(context.Background(), &tt.args.request)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
 func TestValidatePinChat(t *testing.T) {
 	type args struct {
 		request domainChat.PinChatRequest
@@ -250,3 +316,142 @@ func TestValidateSetDisappearingTimer(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExportChat(t *testing.T) {
+	badTime := "2024-01-01"
+
+	type args struct {
+		request domainChat.ExportChatRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with default format",
+			args: args{request: domainChat.ExportChatRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with html and media",
+			args: args{request: domainChat.ExportChatRequest{
+				ChatJID:      "120363024512399999@g.us",
+				Format:       "html",
+				IncludeMedia: true,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty chat jid",
+			args: args{request: domainChat.ExportChatRequest{
+				ChatJID: "",
+			}},
+			err: pkgError.ValidationError("chat_jid: cannot be blank."),
+		},
+		{
+			name: "should error with unknown format",
+			args: args{request: domainChat.ExportChatRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+				Format:  "pdf",
+			}},
+			err: pkgError.ValidationError("format: must be a valid value."),
+		},
+		{
+			name: "should error with non RFC3339 start time",
+			args: args{request: domainChat.ExportChatRequest{
+				ChatJID:   "6289685028129@s.whatsapp.net",
+				StartTime: &badTime,
+			}},
+			err: pkgError.ValidationError("start_time: must be a valid date."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExportChat(context.Background(), &tt.args.request)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateImportChat(t *testing.T) {
+	archive := []byte("05/03/2024, 09:07 - Ana: Hello")
+
+	type args struct {
+		request domainChat.ImportChatRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with archive only",
+			args: args{request: domainChat.ImportChatRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+				File:    archive,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with timezone and date order",
+			args: args{request: domainChat.ImportChatRequest{
+				ChatJID:   "120363024512399999@g.us",
+				File:      archive,
+				Timezone:  "Asia/Jakarta",
+				DateOrder: "mdy",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty chat jid",
+			args: args{request: domainChat.ImportChatRequest{
+				File: archive,
+			}},
+			err: pkgError.ValidationError("chat_jid: cannot be blank."),
+		},
+		{
+			name: "should error without file",
+			args: args{request: domainChat.ImportChatRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+			}},
+			err: pkgError.ValidationError("file: cannot be blank."),
+		},
+		{
+			name: "should error with unknown date order",
+			args: args{request: domainChat.ImportChatRequest{
+				ChatJID:   "6289685028129@s.whatsapp.net",
+				File:      archive,
+				DateOrder: "ymd",
+			}},
+			err: pkgError.ValidationError("date_order: must be a valid value."),
+		},
+		{
+			name: "should error with unknown timezone",
+			args: args{request: domainChat.ImportChatRequest{
+				ChatJID:  "6289685028129@s.whatsapp.net",
+				File:     archive,
+				Timezone: "Mars/Olympus",
+			}},
+			err: pkgError.ValidationError(`timezone: unknown time zone "Mars/Olympus".`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImportChat(context.Background(), &tt.args.request)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
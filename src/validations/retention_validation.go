@@ -0,0 +1,48 @@
+package validations
+
+import (
+	"context"
+
+	domainRetention "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/retention"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// maxRetentionDays keeps cutoffs within a century
+const maxRetentionDays = 36500
+
+func ValidateCreateRetentionPolicy(ctx context.Context, request *domainRetention.CreatePolicyRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Scope, validation.Required,
+			validation.In(domainRetention.ScopeGlobal, domainRetention.ScopeDevice, domainRetention.ScopeChat)),
+		validation.Field(&request.DeviceID,
+			validation.When(request.Scope == domainRetention.ScopeDevice, validation.Required),
+			validation.When(request.Scope == domainRetention.ScopeGlobal, validation.Empty)),
+		validation.Field(&request.ChatJID,
+			validation.When(request.Scope == domainRetention.ScopeChat, validation.Required).Else(validation.Empty)),
+		validation.Field(&request.MessageDays, validation.Min(0), validation.Max(maxRetentionDays),
+			validation.When(request.MediaDays == nil, validation.NotNil.Error("message_days or media_days is required"))),
+		validation.Field(&request.MediaDays, validation.Min(0), validation.Max(maxRetentionDays)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateRetentionPolicy(ctx context.Context, request *domainRetention.UpdatePolicyRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.PolicyID, validation.Required),
+		validation.Field(&request.MessageDays, validation.Min(0), validation.Max(maxRetentionDays),
+			validation.When(request.MediaDays == nil, validation.NotNil.Error("message_days or media_days is required"))),
+		validation.Field(&request.MediaDays, validation.Min(0), validation.Max(maxRetentionDays)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
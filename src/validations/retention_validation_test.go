@@ -0,0 +1,72 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainRetention "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/retention"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateRetentionPolicy(t *testing.T) {
+	days := func(value int) *int { return &value }
+
+	tests := []struct {
+		name    string
+		request domainRetention.CreatePolicyRequest
+		err     any
+	}{
+		{
+			name:    "should success with global message retention",
+			request: domainRetention.CreatePolicyRequest{Scope: domainRetention.ScopeGlobal, MessageDays: days(90)},
+			err:     nil,
+		},
+		{
+			name: "should success with media retention of a chat on every device",
+			request: domainRetention.CreatePolicyRequest{
+				Scope: domainRetention.ScopeChat, ChatJID: "120363024512399999@g.us", MediaDays: days(7),
+			},
+			err: nil,
+		},
+		{
+			name:    "should success keeping a device forever",
+			request: domainRetention.CreatePolicyRequest{Scope: domainRetention.ScopeDevice, DeviceID: "my-device", MessageDays: days(0)},
+			err:     nil,
+		},
+		{
+			name:    "should error without any setting",
+			request: domainRetention.CreatePolicyRequest{Scope: domainRetention.ScopeGlobal},
+			err:     pkgError.ValidationError("message_days: message_days or media_days is required."),
+		},
+		{
+			name:    "should error with unknown scope",
+			request: domainRetention.CreatePolicyRequest{Scope: "group", MessageDays: days(30)},
+			err:     pkgError.ValidationError("scope: must be a valid value."),
+		},
+		{
+			name:    "should error on device scope without device",
+			request: domainRetention.CreatePolicyRequest{Scope: domainRetention.ScopeDevice, MessageDays: days(30)},
+			err:     pkgError.ValidationError("device_id: cannot be blank."),
+		},
+		{
+			name: "should error on global scope with chat",
+			request: domainRetention.CreatePolicyRequest{
+				Scope: domainRetention.ScopeGlobal, ChatJID: "6289685028129@s.whatsapp.net", MessageDays: days(30),
+			},
+			err: pkgError.ValidationError("chat_jid: must be blank."),
+		},
+		{
+			name:    "should error with negative days",
+			request: domainRetention.CreatePolicyRequest{Scope: domainRetention.ScopeGlobal, MediaDays: days(-1)},
+			err:     pkgError.ValidationError("media_days: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateRetentionPolicy(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
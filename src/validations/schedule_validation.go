@@ -0,0 +1,79 @@
+package validations
+
+import (
+	"context"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateScheduleMessage(ctx context.Context, request *domainSchedule.CreateRequest) error {
+	types := make([]any, len(domainSend.StorableTypes))
+	for i, t := range domainSend.StorableTypes {
+		types[i] = t
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Type, validation.Required, validation.In(types...)),
+		validation.Field(&request.SendAt, validation.Required, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return validateFutureTime("send_at", request.SendAt)
+}
+
+func ValidateListSchedules(ctx context.Context, request *domainSchedule.ListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			string(domainSchedule.StatusScheduled),
+			string(domainSchedule.StatusSending),
+			string(domainSchedule.StatusSent),
+			string(domainSchedule.StatusFailed),
+			string(domainSchedule.StatusCancelled),
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(200)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateReschedule(ctx context.Context, request *domainSchedule.RescheduleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ScheduleID, validation.Required),
+		validation.Field(&request.SendAt, validation.Required, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return validateFutureTime("send_at", request.SendAt)
+}
+
+// validateFutureTime rejects RFC3339 timestamps that are already in the past.
+func validateFutureTime(field, value string) error {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return pkgError.ValidationError(field + ": must be a valid date.")
+	}
+	if !parsed.After(time.Now()) {
+		return pkgError.ValidationError(field + ": must be in the future.")
+	}
+	return nil
+}
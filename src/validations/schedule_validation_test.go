@@ -0,0 +1,59 @@
+package validations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainSchedule "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/schedule"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScheduleMessage(t *testing.T) {
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name    string
+		request domainSchedule.CreateRequest
+		err     any
+	}{
+		{
+			name:    "should success with future send_at",
+			request: domainSchedule.CreateRequest{Type: "message", SendAt: future},
+			err:     nil,
+		},
+		{
+			name:    "should error with unsupported type",
+			request: domainSchedule.CreateRequest{Type: "presence", SendAt: future},
+			err:     pkgError.ValidationError("type: must be a valid value."),
+		},
+		{
+			name:    "should error without send_at",
+			request: domainSchedule.CreateRequest{Type: "image"},
+			err:     pkgError.ValidationError("send_at: cannot be blank."),
+		},
+		{
+			name:    "should error with past send_at",
+			request: domainSchedule.CreateRequest{Type: "poll", SendAt: past},
+			err:     pkgError.ValidationError("send_at: must be in the future."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScheduleMessage(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListSchedules(t *testing.T) {
+	request := domainSchedule.ListRequest{}
+	assert.Nil(t, ValidateListSchedules(context.Background(), &request))
+	assert.Equal(t, 50, request.Limit)
+
+	request = domainSchedule.ListRequest{Status: "queued"}
+	assert.Equal(t, pkgError.ValidationError("status: must be a valid value."), ValidateListSchedules(context.Background(), &request))
+}
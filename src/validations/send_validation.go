@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/dustin/go-humanize"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -53,9 +55,22 @@ func validatePhoneNumber(phone string) error {
 	return nil
 }
 
+// validateTemplateVariables rejects templated text whose placeholders are not all covered by the variables.
+func validateTemplateVariables(text string, template domainSend.TemplateRequest) error {
+	if !template.IsTemplated() {
+		return nil
+	}
+
+	if _, missing := utils.RenderTemplate(text, template.Variables); len(missing) > 0 {
+		return pkgError.ValidationError(fmt.Sprintf("variables: missing values for %s.", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
 func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
+		// A template_id is loaded into Message before validation
 		validation.Field(&request.Message, validation.Required),
 	)
 
@@ -63,6 +78,10 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 		return pkgError.ValidationError(err.Error())
 	}
 
+	if err := validateTemplateVariables(request.Message, request.TemplateRequest); err != nil {
+		return err
+	}
+
 	// Custom validation for phone number format
 	if err := validatePhoneNumber(request.Phone); err != nil {
 		return err
@@ -137,7 +156,7 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
-	return nil
+	return validateTemplateVariables(request.Caption, request.TemplateRequest)
 }
 
 func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest) error {
@@ -239,7 +258,7 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 		return err
 	}
 
-	return nil
+	return validateTemplateVariables(request.Caption, request.TemplateRequest)
 }
 
 func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) error {
@@ -0,0 +1,38 @@
+package validations
+
+import (
+	"context"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const maxTemplateContentLength = 65536
+
+func ValidateCreateTemplate(ctx context.Context, request *domainTemplate.CreateTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Content, validation.Required, validation.Length(1, maxTemplateContentLength)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateTemplate(ctx context.Context, request *domainTemplate.UpdateTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.TemplateID, validation.Required),
+		validation.Field(&request.Name, validation.NilOrNotEmpty, validation.Length(1, 100)),
+		validation.Field(&request.Content, validation.NilOrNotEmpty, validation.Length(1, maxTemplateContentLength)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
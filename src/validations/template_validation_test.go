@@ -0,0 +1,92 @@
+package validations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainTemplate.CreateTemplateRequest
+		err     any
+	}{
+		{
+			name:    "should success with name and content",
+			request: domainTemplate.CreateTemplateRequest{Name: "order-shipped", Content: "Hi {{name}}, order {{order_id}} has shipped"},
+			err:     nil,
+		},
+		{
+			name:    "should error without content",
+			request: domainTemplate.CreateTemplateRequest{Name: "order-shipped"},
+			err:     pkgError.ValidationError("content: cannot be blank."),
+		},
+		{
+			name:    "should error with too long name",
+			request: domainTemplate.CreateTemplateRequest{Name: strings.Repeat("a", 101), Content: "Hi"},
+			err:     pkgError.ValidationError("name: the length must be between 1 and 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateTemplate(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainSend.MessageRequest
+		err     any
+	}{
+		{
+			name: "should success with all variables",
+			request: domainSend.MessageRequest{
+				BaseRequest:     domainSend.BaseRequest{Phone: "6289685028129"},
+				TemplateRequest: domainSend.TemplateRequest{TemplateID: "tpl", Variables: map[string]string{"name": "Ana", "order_id": "42"}},
+				Message:         "Hi {{name}}, order {{order_id}} has shipped",
+			},
+			err: nil,
+		},
+		{
+			name: "should error with missing variables",
+			request: domainSend.MessageRequest{
+				BaseRequest:     domainSend.BaseRequest{Phone: "6289685028129"},
+				TemplateRequest: domainSend.TemplateRequest{TemplateID: "tpl", Variables: map[string]string{"name": "Ana"}},
+				Message:         "Hi {{name}}, order {{ order_id }} has shipped",
+			},
+			err: pkgError.ValidationError("variables: missing values for order_id."),
+		},
+		{
+			name: "should error with template not loaded",
+			request: domainSend.MessageRequest{
+				BaseRequest:     domainSend.BaseRequest{Phone: "6289685028129"},
+				TemplateRequest: domainSend.TemplateRequest{TemplateID: "tpl"},
+			},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+		{
+			name: "should error without message or template",
+			request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{Phone: "6289685028129"},
+			},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendMessage(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package validations
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// webhookEventPattern keeps event names free of separators used when persisting subscription whitelists.
+var webhookEventPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+func ValidateListWebhookDeliveries(ctx context.Context, request *domainWebhook.ListDeliveriesRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			string(domainWebhook.DeliveryStatusPending),
+			string(domainWebhook.DeliveryStatusDelivered),
+			string(domainWebhook.DeliveryStatusDead),
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(200)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateCreateWebhookSubscription(ctx context.Context, request *domainWebhook.CreateSubscriptionRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.DeviceID, validation.Required),
+		validation.Field(&request.URL, validation.Required, is.URL),
+		validation.Field(&request.Events, validation.Each(validation.Required, validation.Match(webhookEventPattern))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateWebhookSubscription(ctx context.Context, request *domainWebhook.UpdateSubscriptionRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.DeviceID, validation.Required),
+		validation.Field(&request.SubscriptionID, validation.Required),
+		validation.Field(&request.URL, validation.NilOrNotEmpty, is.URL),
+		validation.Field(&request.Events, validation.By(func(value any) error {
+			events, _ := value.(*[]string)
+			if events == nil {
+				return nil
+			}
+			return validation.Validate(*events, validation.Each(validation.Required, validation.Match(webhookEventPattern)))
+		})),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateReplayWebhook(ctx context.Context, request *domainWebhook.ReplayRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 100
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.WebhookURL, validation.Required, is.URL),
+		validation.Field(&request.StartTime,
+			validation.When(request.ChatJID == "", validation.Required.Error("is required when chat_jid is empty")),
+			validation.Date(time.RFC3339),
+		),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(1000)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
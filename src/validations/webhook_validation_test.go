@@ -0,0 +1,123 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListWebhookDeliveries(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainWebhook.ListDeliveriesRequest
+		err     any
+	}{
+		{
+			name:    "should success with empty filter",
+			request: domainWebhook.ListDeliveriesRequest{},
+			err:     nil,
+		},
+		{
+			name:    "should success with dead status",
+			request: domainWebhook.ListDeliveriesRequest{Status: "dead", Limit: 10},
+			err:     nil,
+		},
+		{
+			name:    "should error with unknown status",
+			request: domainWebhook.ListDeliveriesRequest{Status: "failed"},
+			err:     pkgError.ValidationError("status: must be a valid value."),
+		},
+		{
+			name:    "should error with limit too high",
+			request: domainWebhook.ListDeliveriesRequest{Limit: 201},
+			err:     pkgError.ValidationError("limit: must be no greater than 200."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListWebhookDeliveries(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCreateWebhookSubscription(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainWebhook.CreateSubscriptionRequest
+		err     any
+	}{
+		{
+			name:    "should success with url and events",
+			request: domainWebhook.CreateSubscriptionRequest{DeviceID: "sales", URL: "https://example.com/hook", Events: []string{"message", "message.ack"}},
+			err:     nil,
+		},
+		{
+			name:    "should error without url",
+			request: domainWebhook.CreateSubscriptionRequest{DeviceID: "sales"},
+			err:     pkgError.ValidationError("url: cannot be blank."),
+		},
+		{
+			name:    "should error with invalid url",
+			request: domainWebhook.CreateSubscriptionRequest{DeviceID: "sales", URL: "not a url"},
+			err:     pkgError.ValidationError("url: must be a valid URL."),
+		},
+		{
+			name:    "should error with separator in event name",
+			request: domainWebhook.CreateSubscriptionRequest{DeviceID: "sales", URL: "https://example.com/hook", Events: []string{"message,call.offer"}},
+			err:     pkgError.ValidationError("events: (0: must be in a valid format.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateWebhookSubscription(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateReplayWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainWebhook.ReplayRequest
+		err     any
+	}{
+		{
+			name:    "should success with chat jid",
+			request: domainWebhook.ReplayRequest{WebhookURL: "https://example.com/hook", ChatJID: "628123@s.whatsapp.net"},
+			err:     nil,
+		},
+		{
+			name:    "should success with time range",
+			request: domainWebhook.ReplayRequest{WebhookURL: "https://example.com/hook", StartTime: "2024-01-01T00:00:00Z", EndTime: "2024-01-02T00:00:00Z"},
+			err:     nil,
+		},
+		{
+			name:    "should error without chat jid or start time",
+			request: domainWebhook.ReplayRequest{WebhookURL: "https://example.com/hook"},
+			err:     pkgError.ValidationError("start_time: is required when chat_jid is empty."),
+		},
+		{
+			name:    "should error with invalid start time",
+			request: domainWebhook.ReplayRequest{WebhookURL: "https://example.com/hook", StartTime: "yesterday"},
+			err:     pkgError.ValidationError("start_time: must be a valid date."),
+		},
+		{
+			name:    "should error with limit too high",
+			request: domainWebhook.ReplayRequest{WebhookURL: "https://example.com/hook", ChatJID: "628123@s.whatsapp.net", Limit: 1001},
+			err:     pkgError.ValidationError("limit: must be no greater than 1000."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReplayWebhook(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}